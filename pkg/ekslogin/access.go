@@ -0,0 +1,257 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ListAccessEntriesResponse represents the response from eks list-access-entries
+type ListAccessEntriesResponse struct {
+	AccessEntries []string `json:"accessEntries"`
+}
+
+// ListAccessEntries retrieves the principal ARNs with an access entry on the selected cluster
+func (app *EKSLoginApp) ListAccessEntries() ([]string, error) {
+	output, err := app.AWS("eks", "list-access-entries",
+		"--cluster-name", app.config.Cluster,
+		"--region", app.config.Region,
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access entries: %w", err)
+	}
+
+	var response ListAccessEntriesResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse access entries: %w", err)
+	}
+
+	return response.AccessEntries, nil
+}
+
+// CheckClusterAccess warns when the caller's principal has no access entry on the cluster.
+// The check is best-effort: missing permissions to inspect access entries are not an error.
+func (app *EKSLoginApp) CheckClusterAccess() {
+	identity, err := app.GetCallerIdentity()
+	if err != nil {
+		return
+	}
+	principal := PrincipalARN(identity.Arn)
+
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return
+	}
+
+	mode := details.AccessConfig.AuthenticationMode
+	if !strings.HasPrefix(mode, "API") {
+		app.checkAWSAuth(details, principal)
+		return
+	}
+
+	entries, err := app.ListAccessEntries()
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if SamePrincipal(entry, principal) {
+			green.Println("✓ Access entry found for your role")
+			return
+		}
+	}
+
+	if mode == "API" {
+		app.printNoAccess(principal)
+		return
+	}
+
+	yellow.Printf("⚠️  No access entry for %s; access must come from the aws-auth ConfigMap\n", principal)
+}
+
+// awsAuthPath is the API path of the ConfigMap mapping IAM principals into
+// clusters without access entries
+const awsAuthPath = "/api/v1/namespaces/kube-system/configmaps/aws-auth"
+
+// awsAuthMapping is an entry of the mapRoles or mapUsers list of aws-auth
+type awsAuthMapping struct {
+	RoleARN string `yaml:"rolearn"`
+	UserARN string `yaml:"userarn"`
+}
+
+// checkAWSAuth looks for the caller's principal in the aws-auth ConfigMap of
+// a CONFIG_MAP cluster. An unmapped principal is refused by the API server;
+// one that is mapped but may not read the ConfigMap leaves nothing to tell.
+func (app *EKSLoginApp) checkAWSAuth(details *ClusterDetails, principal string) {
+	cannotTell := func() {
+		yellow.Printf("⚠️  Unable to check your access in advance: cluster %s maps roles in the aws-auth ConfigMap\n", app.config.Cluster)
+	}
+	if err := app.probeEndpoint(details); err != nil {
+		cannotTell()
+		return
+	}
+	credential, err := app.GetClusterToken()
+	if err != nil {
+		cannotTell()
+		return
+	}
+	client, err := app.clusterClient(details, credential.Status.Token)
+	if err != nil {
+		cannotTell()
+		return
+	}
+
+	body, _, err := client.Get(awsAuthPath)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		app.printNoAccess(principal)
+		return
+	}
+	if err != nil {
+		cannotTell()
+		return
+	}
+
+	var configMap struct {
+		Data struct {
+			MapRoles string `json:"mapRoles"`
+			MapUsers string `json:"mapUsers"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &configMap); err != nil {
+		cannotTell()
+		return
+	}
+	var mappings []awsAuthMapping
+	for _, list := range []string{configMap.Data.MapRoles, configMap.Data.MapUsers} {
+		var entries []awsAuthMapping
+		if yaml.Unmarshal([]byte(list), &entries) == nil {
+			mappings = append(mappings, entries...)
+		}
+	}
+	for _, mapping := range mappings {
+		if SamePrincipal(mapping.RoleARN, principal) || SamePrincipal(mapping.UserARN, principal) {
+			green.Println("✓ aws-auth mapping found for your role")
+			return
+		}
+	}
+	// The API server let us in without a mapping: the cluster's creator
+	green.Println("✓ Your role has access to the cluster (no aws-auth mapping; it likely created the cluster)")
+}
+
+// printNoAccess explains that the caller's principal is not mapped into the cluster
+func (app *EKSLoginApp) printNoAccess(principal string) {
+	red.Printf("✗ Your role has no access to cluster %s\n", app.config.Cluster)
+	fmt.Printf("  Principal: %s\n", principal)
+
+	contact := app.settings.AccessContact
+	if contact == "" {
+		contact = "a cluster administrator"
+	}
+	fmt.Printf("  Ask %s to create an EKS access entry (or aws-auth mapping) for it.\n", contact)
+}
+
+// GrantAccessOptions describes the access entry created by grant-access
+type GrantAccessOptions struct {
+	PrincipalARN     string
+	KubernetesGroups []string
+	Username         string
+	Policy           string
+	Namespaces       []string
+}
+
+// accessPolicyARN expands short access policy names such as AmazonEKSViewPolicy
+func (app *EKSLoginApp) accessPolicyARN(policy string) string {
+	if strings.HasPrefix(policy, "arn:") {
+		return policy
+	}
+	return ARN{
+		Partition: app.partition().ID,
+		Service:   "eks",
+		AccountID: "aws",
+		Resource:  "cluster-access-policy/" + policy,
+	}.String()
+}
+
+// GrantAccess creates an access entry for a principal and optionally associates an access policy
+func (app *EKSLoginApp) GrantAccess(opts GrantAccessOptions) error {
+	blue.Printf("🔑 Creating access entry for %s on %s...\n", opts.PrincipalARN, app.config.Cluster)
+
+	args := []string{
+		"eks", "create-access-entry",
+		"--cluster-name", app.config.Cluster,
+		"--principal-arn", opts.PrincipalARN,
+		"--region", app.config.Region,
+	}
+	if len(opts.KubernetesGroups) > 0 {
+		args = append(args, "--kubernetes-groups")
+		args = append(args, opts.KubernetesGroups...)
+	}
+	if opts.Username != "" {
+		args = append(args, "--username", opts.Username)
+	}
+
+	if _, err := app.AWS(args...); err != nil {
+		if !strings.Contains(err.Error(), "ResourceInUseException") {
+			return fmt.Errorf("failed to create access entry: %w", err)
+		}
+		yellow.Println("⚠️  Access entry already exists, keeping it")
+	} else {
+		green.Println("✓ Access entry created")
+	}
+
+	if opts.Policy == "" {
+		return nil
+	}
+
+	policyARN := app.accessPolicyARN(opts.Policy)
+	blue.Printf("🔑 Associating access policy %s...\n", policyARN)
+
+	scope := "type=cluster"
+	if len(opts.Namespaces) > 0 {
+		scope = "type=namespace,namespaces=" + strings.Join(opts.Namespaces, ",")
+	}
+
+	if _, err := app.AWS("eks", "associate-access-policy",
+		"--cluster-name", app.config.Cluster,
+		"--principal-arn", opts.PrincipalARN,
+		"--policy-arn", policyARN,
+		"--access-scope", scope,
+		"--region", app.config.Region); err != nil {
+		return fmt.Errorf("failed to associate access policy: %w", err)
+	}
+
+	green.Println("✓ Access policy associated")
+	return nil
+}
+
+func newGrantAccessCmd(app *EKSLoginApp) *cobra.Command {
+	var opts GrantAccessOptions
+
+	cmd := &cobra.Command{
+		Use:   "grant-access",
+		Short: "Create an EKS access entry for a principal on the cluster",
+		Example: `  eks-login grant-access -c my-cluster --principal-arn arn:aws:iam::123456789012:role/Developers --policy AmazonEKSViewPolicy
+  eks-login grant-access -c my-cluster --principal-arn arn:aws:iam::123456789012:role/Team --policy AmazonEKSEditPolicy --namespaces team-a,team-b`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.GrantAccess(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PrincipalARN, "principal-arn", "", "IAM principal ARN to grant access to")
+	cmd.Flags().StringSliceVar(&opts.KubernetesGroups, "kubernetes-groups", nil, "Kubernetes groups for the access entry")
+	cmd.Flags().StringVar(&opts.Username, "username", "", "Kubernetes username for the access entry")
+	cmd.Flags().StringVar(&opts.Policy, "policy", "", "Access policy to associate (name or ARN), e.g. AmazonEKSClusterAdminPolicy")
+	cmd.Flags().StringSliceVar(&opts.Namespaces, "namespaces", nil, "Limit the access policy to these namespaces")
+	cmd.MarkFlagRequired("principal-arn")
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package ekslogin
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// captureStdout returns what fn prints to stdout, colored messages included
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, colored := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+	fn()
+	os.Stdout, color.Output = stdout, colored
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+// checkAWSAuthAgainst runs checkAWSAuth against an API server answering the
+// aws-auth request with status and body
+func checkAWSAuthAgainst(t *testing.T, status int, body string) string {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != awsAuthPath || r.Header.Get("Authorization") != "Bearer k8s-aws-v1.fake" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	executor := &fakeExecutor{outputs: map[string]string{
+		"aws eks get-token": `{"kind":"ExecCredential","status":{"token":"k8s-aws-v1.fake"}}`,
+	}}
+	app := newTestApp(t, executor, &fakePrompter{})
+	app.config.Cluster = "legacy"
+
+	details := &ClusterDetails{Name: "legacy", Endpoint: server.URL}
+	details.ResourcesVpcConfig.EndpointPublicAccess = true
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	details.CertificateAuthority.Data = base64.StdEncoding.EncodeToString(ca)
+
+	return captureStdout(t, func() {
+		app.checkAWSAuth(details, "arn:aws:iam::123456789012:role/Dev")
+	})
+}
+
+func TestCheckAWSAuth(t *testing.T) {
+	mapped := `{"data":{"mapRoles":"- rolearn: arn:aws:iam::123456789012:role/Dev\n  username: dev\n  groups: [developers]\n"}}`
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"mapped", http.StatusOK, mapped, "aws-auth mapping found"},
+		{"unmapped", http.StatusUnauthorized, "", "has no access to cluster legacy"},
+		{"forbidden", http.StatusForbidden, "", "Unable to check your access in advance"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if out := checkAWSAuthAgainst(t, test.status, test.body); !strings.Contains(out, test.want) {
+				t.Errorf("output %q does not contain %q", out, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package ekslogin
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// plainOutput is set in accessible mode: status messages lose their
+// decorative symbols, see plainWriter
+var plainOutput bool
+
+// symbolWords replace the symbols that carry meaning; other symbols at the
+// start of a line are decoration and dropped
+var symbolWords = map[rune]string{
+	'✓': "OK:",
+	'✗': "Failed:",
+	'⚠': "Warning:",
+	'🚨': "Alert:",
+}
+
+// accessibleEnabled reports whether --accessible, EKS_LOGIN_ACCESSIBLE or
+// default.accessible asks for screen reader friendly output
+func (app *EKSLoginApp) accessibleEnabled() bool {
+	return app.config.Accessible || os.Getenv("EKS_LOGIN_ACCESSIBLE") != "" || app.settings.Default.Accessible
+}
+
+// EnableAccessibleMode makes the output usable with a screen reader: no
+// spinners or menus that redraw lines, and no decorative symbols, so every
+// state change is a discrete plain line
+func (app *EKSLoginApp) EnableAccessibleMode() {
+	app.config.Accessible = true
+	plainOutput = true
+	color.Output = statusOutput(color.Output)
+	if prompter, ok := app.prompter.(*terminalPrompter); ok {
+		prompter.plain = true
+	}
+}
+
+// statusOutput returns the writer status messages to w go through
+func statusOutput(w io.Writer) io.Writer {
+	if !plainOutput {
+		return w
+	}
+	if plain, ok := w.(*plainWriter); ok {
+		return plain
+	}
+	return &plainWriter{out: w, lineStart: true}
+}
+
+// inputRead tells the status output that the terminal is at the start of a
+// line again after the user answered a prompt
+func inputRead() {
+	if plain, ok := color.Output.(*plainWriter); ok {
+		plain.mu.Lock()
+		plain.lineStart = true
+		plain.mu.Unlock()
+	}
+}
+
+// plainWriter replaces or drops the symbol a line starts with, after its
+// indentation and color codes, e.g. "  ✓ Done" becomes "  OK: Done"
+type plainWriter struct {
+	mu        sync.Mutex
+	out       io.Writer
+	lineStart bool
+}
+
+func (w *plainWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var buf bytes.Buffer
+	for i := 0; i < len(p); {
+		if !w.lineStart {
+			if p[i] == '\n' {
+				w.lineStart = true
+			}
+			buf.WriteByte(p[i])
+			i++
+			continue
+		}
+
+		switch {
+		case p[i] == ' ' || p[i] == '\t' || p[i] == '\n':
+			buf.WriteByte(p[i])
+			i++
+		case p[i] == 0x1b:
+			// keep color codes, which are not read out
+			end := bytes.IndexByte(p[i:], 'm')
+			if end < 0 {
+				end = len(p) - i - 1
+			}
+			buf.Write(p[i : i+end+1])
+			i += end + 1
+		default:
+			w.lineStart = false
+			r, size := utf8.DecodeRune(p[i:])
+			if r <= unicode.MaxASCII || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				continue
+			}
+			i += size
+			// variation selectors and joiners belong to the symbol
+			for i < len(p) {
+				next, size := utf8.DecodeRune(p[i:])
+				if next != '\uFE0F' && next != '\u200D' && !unicode.Is(unicode.So, next) {
+					break
+				}
+				i += size
+			}
+			for i < len(p) && p[i] == ' ' {
+				i++
+			}
+			if word, ok := symbolWords[r]; ok {
+				buf.WriteString(word + " ")
+			}
+		}
+	}
+
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,153 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var versionNumberPattern = regexp.MustCompile(`\d+`)
+
+// AddonDetails is the subset of eks describe-addon output shown by addons
+type AddonDetails struct {
+	Name    string `json:"addonName"`
+	Version string `json:"addonVersion"`
+	Status  string `json:"status"`
+	Latest  string `json:"latestVersion,omitempty"`
+}
+
+// compareVersions compares add-on versions such as v1.18.1-eksbuild.3 number by number
+func compareVersions(a, b string) int {
+	numsA := versionNumberPattern.FindAllString(a, -1)
+	numsB := versionNumberPattern.FindAllString(b, -1)
+
+	for i := 0; i < len(numsA) && i < len(numsB); i++ {
+		x, _ := strconv.Atoi(numsA[i])
+		y, _ := strconv.Atoi(numsB[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(numsA) - len(numsB)
+}
+
+// LatestAddonVersion returns the newest version of an add-on compatible with kubernetesVersion
+func (app *EKSLoginApp) LatestAddonVersion(addon, kubernetesVersion string) (string, error) {
+	output, err := app.AWS("eks", "describe-addon-versions",
+		"--addon-name", addon,
+		"--kubernetes-version", kubernetesVersion,
+		"--region", app.config.Region,
+		"--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to describe add-on versions: %w", err)
+	}
+
+	var response struct {
+		Addons []struct {
+			AddonVersions []struct {
+				AddonVersion string `json:"addonVersion"`
+			} `json:"addonVersions"`
+		} `json:"addons"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return "", fmt.Errorf("failed to parse add-on versions: %w", err)
+	}
+
+	latest := ""
+	for _, addon := range response.Addons {
+		for _, version := range addon.AddonVersions {
+			if latest == "" || compareVersions(version.AddonVersion, latest) > 0 {
+				latest = version.AddonVersion
+			}
+		}
+	}
+	return latest, nil
+}
+
+// ListAddons retrieves the installed add-ons of the selected cluster with their latest versions
+func (app *EKSLoginApp) ListAddons() ([]AddonDetails, error) {
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Addons []string `json:"addons"`
+	}
+	if err := app.eksJSON(&list, "list-addons"); err != nil {
+		return nil, err
+	}
+
+	addons := make([]AddonDetails, 0, len(list.Addons))
+	for _, name := range list.Addons {
+		var response struct {
+			Addon AddonDetails `json:"addon"`
+		}
+		if err := app.eksJSON(&response, "describe-addon", "--addon-name", name); err != nil {
+			return nil, err
+		}
+
+		addon := response.Addon
+		if latest, err := app.LatestAddonVersion(name, details.Version); err == nil {
+			addon.Latest = latest
+		}
+		addons = append(addons, addon)
+	}
+
+	return addons, nil
+}
+
+// ShowAddons prints the installed add-ons and whether updates are available
+func (app *EKSLoginApp) ShowAddons(output string) error {
+	blue.Printf("🧩 Fetching add-ons for cluster: %s\n", app.config.Cluster)
+
+	addons, err := app.ListAddons()
+	if err != nil {
+		return err
+	}
+
+	return printOutput(output, addons, func() error {
+		if len(addons) == 0 {
+			fmt.Println("\nNo EKS add-ons installed.")
+			return nil
+		}
+
+		fmt.Println()
+		table := NewTable("", "NAME", "VERSION", "STATUS", "UPDATE")
+		for _, addon := range addons {
+			update := "up to date"
+			if addon.Latest == "" {
+				update = "unknown"
+			} else if compareVersions(addon.Latest, addon.Version) > 0 {
+				update = yellow.Sprint("→ " + addon.Latest)
+			}
+			table.Row(addon.Name, addon.Version, addon.Status, update)
+		}
+		return table.Write(os.Stdout)
+	})
+}
+
+func newAddonsCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "addons",
+		Short: "List installed EKS add-ons and available updates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.ShowAddons(output)
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	return cmd
+}
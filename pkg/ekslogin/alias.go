@@ -0,0 +1,107 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// aliasNames returns the configured alias names in order
+func aliasNames(settings *Settings) []string {
+	names := make([]string, 0, len(settings.Aliases))
+	for name := range settings.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyAlias sets the target from the alias name, if one is configured, and
+// reports whether it was. Flags given explicitly take precedence.
+func (app *EKSLoginApp) ApplyAlias(cmd *cobra.Command, name string) bool {
+	alias, ok := app.settings.Aliases[name]
+	if !ok {
+		return false
+	}
+
+	app.applyPreset(cmd, alias)
+	cyan.Printf("🔖 Using alias: %s\n", alias.describe(name))
+	return true
+}
+
+// completeTargets completes the positional target of the root command with
+// aliases and the clusters logged in to before. It runs without the
+// PersistentPreRun, so it reads the config file itself.
+func (app *EKSLoginApp) completeTargets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	if settings, err := LoadSettings(app.config.ConfigFile); err == nil {
+		for _, name := range aliasNames(settings) {
+			alias := settings.Aliases[name]
+			completions = append(completions, fmt.Sprintf("%s\talias for %s/%s", name, alias.Profile, alias.Cluster))
+		}
+	}
+	for _, cluster := range app.rememberedClusters() {
+		completions = append(completions, cluster+"\tcluster")
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// rememberedClusters returns the names of the clusters logged in to before
+func (app *EKSLoginApp) rememberedClusters() []string {
+	seen := make(map[string]bool)
+	var clusters []string
+	for key := range app.LoadState().Used {
+		parts := strings.SplitN(key, "/", 3)
+		if len(parts) == 3 && !seen[parts[2]] {
+			seen[parts[2]] = true
+			clusters = append(clusters, parts[2])
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// newAliasCmd creates the alias command
+func newAliasCmd(app *EKSLoginApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage shortcuts for 'eks-login NAME' defined under 'aliases' in the config file",
+	}
+
+	var output string
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the configured aliases",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases := app.settings.Aliases
+			if aliases == nil {
+				aliases = map[string]Preset{}
+			}
+			return printOutput(output, aliases, func() error {
+				names := aliasNames(app.settings)
+				if len(names) == 0 {
+					fmt.Printf("No aliases configured; add them under 'aliases' in %s\n", app.config.ConfigFile)
+					return nil
+				}
+				table := NewTable("", "ALIAS", "PROFILE", "REGION", "CLUSTER", "NAMESPACE")
+				for _, name := range names {
+					alias := aliases[name]
+					table.Row(name, alias.Profile, alias.Region, alias.Cluster, alias.Namespace)
+				}
+				return table.Write(os.Stdout)
+			})
+		},
+	}
+	addOutputFlag(list, &output)
+
+	cmd.AddCommand(list)
+	return cmd
+}
@@ -0,0 +1,946 @@
+package ekslogin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Colors
+var (
+	green  = color.New(color.FgGreen, color.Bold)
+	red    = color.New(color.FgRed, color.Bold)
+	yellow = color.New(color.FgYellow, color.Bold)
+	blue   = color.New(color.FgBlue, color.Bold)
+	cyan   = color.New(color.FgCyan, color.Bold)
+)
+
+// Config holds the application configuration
+type Config struct {
+	Profile           string
+	Region            string
+	Cluster           string
+	Clusters          []ClusterTarget
+	Regions           []string
+	Namespace         string
+	RoleARN           string
+	OrgRole           string
+	Everywhere        bool
+	DryRun            bool
+	HealthCheck       bool
+	SelectNamespace   bool
+	Interactive       bool
+	SkipSSO           bool
+	ECR               bool
+	LaunchK9s         bool
+	RBACCheck         bool
+	VerifyWithKubectl bool
+	DefaultRegion     string
+	RegionSet         bool
+	FIPS              bool
+	EndpointURLs      []string
+	CABundle          string
+	MaxAttempts       int
+	Concurrency       int
+	Timeout           time.Duration
+	LoginTimeout      time.Duration
+	ConfirmCluster    string
+	ReadOnly          bool
+	Force             bool
+	Diff              bool
+	CI                bool
+	Accessible        bool
+	ConfigFile        string
+	ProfileFilter     string
+	NoProjectConfig   bool
+	Reuse             bool
+	Sort              string
+	TokenExec         string
+	ProxyURL          string
+	UserAlias         string
+	SplitKubeconfig   bool
+	SelectRole        bool
+	SSORole           string
+	// WebIdentityRole is assumed with a CI OIDC token instead of using a profile
+	WebIdentityRole      string
+	WebIdentityTokenFile string
+	// STSRegion is the region whose STS endpoint signs EKS tokens
+	STSRegion string
+	// RoleDuration is the session duration of assumed roles; 0 for the STS default
+	RoleDuration time.Duration
+}
+
+// EKSCluster represents an EKS cluster
+type EKSCluster struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Region string `json:"region"`
+}
+
+// ListClustersResponse represents the response from eks list-clusters
+type ListClustersResponse struct {
+	Clusters []string `json:"clusters"`
+}
+
+// ProfileInfo holds AWS profile information
+type ProfileInfo struct {
+	Name   string
+	Region string
+}
+
+// EKSLoginApp represents the main application
+type EKSLoginApp struct {
+	ctx       context.Context
+	config    *Config
+	settings  *Settings
+	executor  Executor
+	prompter  Prompter
+	lifecycle *lifecycle
+
+	// credentials, when set, replace the profile for aws CLI calls (e.g. assumed roles)
+	credentials *AWSCredentials
+
+	// webIdentity is the role and token file of a --web-identity-role login
+	webIdentity *webIdentityCredentials
+
+	// endpointEnv holds the AWS_ENDPOINT_URL* overrides passed to aws CLI calls
+	endpointEnv []string
+
+	kubectlAvailable bool
+
+	// awsCLIMajor is the major version of the installed AWS CLI, 0 if unknown
+	awsCLIMajor int
+
+	// health is the post-login health check result shown in the summary
+	health *ClusterHealth
+}
+
+// NewEKSLoginApp creates a new instance of the application
+func NewEKSLoginApp() *EKSLoginApp {
+	return &EKSLoginApp{
+		config: &Config{
+			DefaultRegion: "us-west-2",
+			Interactive:   true,
+		},
+		settings:  &Settings{},
+		executor:  processExecutor{},
+		prompter:  newTerminalPrompter(),
+		lifecycle: &lifecycle{},
+	}
+}
+
+// Config returns the options of app. Embedders set them before LoadSettings
+// and the flow methods.
+func (app *EKSLoginApp) Config() *Config {
+	return app.config
+}
+
+// SetContext sets the context that bounds every external command of app
+func (app *EKSLoginApp) SetContext(ctx context.Context) {
+	app.ctx = ctx
+}
+
+// Execute runs a command and returns the output
+func (app *EKSLoginApp) Execute(command string, args ...string) (string, error) {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	err := app.run(ctx, Command{Name: command, Args: args, Stdout: &stdout, Stderr: &stderr, Isolate: true})
+	if err != nil {
+		err = timeoutError(ctx, command, app.timeout(), err)
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			return "", fmt.Errorf("command failed: %s\nstderr: %s", err, stderr.Bytes())
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// AWS runs an aws CLI command with the selected profile's credentials and returns the output
+func (app *EKSLoginApp) AWS(args ...string) (string, error) {
+	return app.withRetry(func() (string, error) {
+		return app.Execute("aws", app.awsArgs(args...)...)
+	})
+}
+
+// awsArgs appends the global options that select the credentials of aws CLI calls
+func (app *EKSLoginApp) awsArgs(args ...string) []string {
+	if app.credentials == nil && app.config.Profile != "" {
+		args = append(args, "--profile", app.config.Profile)
+	}
+	return args
+}
+
+// commandEnv returns the environment of child processes, or nil to inherit ours
+func (app *EKSLoginApp) commandEnv() []string {
+	var extra []string
+	if app.credentials != nil {
+		extra = append(extra, app.credentials.Env()...)
+	}
+	if app.fipsEnabled() {
+		extra = append(extra, "AWS_USE_FIPS_ENDPOINT=true")
+	}
+	if app.regionalSTS() {
+		extra = append(extra, "AWS_STS_REGIONAL_ENDPOINTS=regional")
+	}
+	extra = append(extra, app.endpointEnv...)
+	if bundle := app.caBundlePath(); bundle != "" {
+		extra = append(extra, "AWS_CA_BUNDLE="+bundle)
+	}
+
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// fipsEnabled reports whether AWS calls must use FIPS endpoints
+func (app *EKSLoginApp) fipsEnabled() bool {
+	return app.config.FIPS || app.settings.FIPS
+}
+
+// CheckDependencies verifies that required tools are installed.
+// kubectl is optional: features that need it are skipped when it is missing.
+func (app *EKSLoginApp) CheckDependencies() error {
+	dependencies := []string{"aws"}
+
+	blue.Println("🔍 Checking dependencies...")
+
+	for _, dep := range dependencies {
+		if _, err := exec.LookPath(dep); err != nil {
+			return withExitCode(ExitDependencyMissing, "dependency_missing", fmt.Errorf("required dependency '%s' not found in PATH", dep))
+		}
+		green.Printf("  ✓ %s found\n", dep)
+	}
+	app.detectAWSCLI()
+
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		yellow.Println("  ⚠️  kubectl not found, kubeconfig will be written but kubectl features are disabled")
+	} else {
+		app.kubectlAvailable = true
+		green.Println("  ✓ kubectl found")
+	}
+
+	return nil
+}
+
+// requireKubectl reports whether kubectl is available, warning that feature is skipped otherwise
+func (app *EKSLoginApp) requireKubectl(feature string) bool {
+	if !app.kubectlAvailable {
+		yellow.Printf("⚠️  kubectl not found, skipping %s\n", feature)
+	}
+	return app.kubectlAvailable
+}
+
+// GetAWSProfiles retrieves available AWS profiles
+func (app *EKSLoginApp) GetAWSProfiles() ([]ProfileInfo, error) {
+	spinner := app.StartSpinner("Reading AWS profiles")
+	defer spinner.Stop()
+
+	output, err := app.Execute("aws", "configure", "list-profiles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AWS profiles: %w", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	profiles := make([]ProfileInfo, 0, len(lines))
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			// Try to get region for this profile
+			region := app.ProfileRegion(line)
+			if region == "" {
+				region = app.config.DefaultRegion
+			}
+
+			profiles = append(profiles, ProfileInfo{
+				Name:   line,
+				Region: region,
+			})
+		}
+	}
+
+	return profiles, nil
+}
+
+// ProfileRegion returns the region configured for a profile, or "" if none is set
+func (app *EKSLoginApp) ProfileRegion(profile string) string {
+	region, _ := app.Execute("aws", "configure", "get", "region", "--profile", profile)
+	return region
+}
+
+// SelectProfile allows interactive profile selection
+func (app *EKSLoginApp) SelectProfile() error {
+	profiles, err := app.FilteredProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		return withExitCode(ExitNoProfiles, "no_profiles", fmt.Errorf("no AWS profiles found. Please configure AWS CLI first"))
+	}
+
+	// If only one profile, use it
+	if len(profiles) == 1 {
+		app.config.Profile = profiles[0].Name
+		app.config.Region = profiles[0].Region
+		cyan.Printf("📋 Using profile: %s (region: %s)\n", app.config.Profile, app.config.Region)
+		return nil
+	}
+
+	// Interactive selection
+	items := make([]string, len(profiles))
+	for i, profile := range profiles {
+		items[i] = fmt.Sprintf("%s (region: %s)", profile.Name, profile.Region)
+	}
+
+	blue.Println("\n📋 Available AWS Profiles:")
+	choice, err := app.Select("profile", items, -1)
+	if err != nil {
+		return err
+	}
+
+	selectedProfile := profiles[choice]
+	app.config.Profile = selectedProfile.Name
+	app.config.Region = selectedProfile.Region
+
+	return nil
+}
+
+// CheckSSOSession verifies if the SSO session is valid
+func (app *EKSLoginApp) CheckSSOSession() (bool, error) {
+	if app.awsV1() && app.useCachedSSOCredentials() {
+		return true, nil
+	}
+	spinner := app.StartSpinner("Checking SSO session")
+	_, err := app.AWS("sts", "get-caller-identity")
+	spinner.Stop()
+	return err == nil, nil
+}
+
+// LoginSSO performs AWS SSO login
+func (app *EKSLoginApp) LoginSSO() error {
+	if app.config.SkipSSO {
+		return nil
+	}
+	if app.config.DryRun {
+		yellow.Printf("🧪 SSO session for profile %s is not valid; the login would run:\n", app.config.Profile)
+		printDryRunCommand("aws", app.awsArgs("sso", "login")...)
+		return nil
+	}
+	if !app.config.Interactive {
+		return withExitCode(ExitSSOLoginFailed, "sso_login_failed", fmt.Errorf("SSO session for profile %s is not valid and interactive login is disabled", app.config.Profile))
+	}
+	if app.awsV1() {
+		return withExitCode(ExitSSOLoginFailed, "sso_login_failed", fmt.Errorf("SSO session for profile %s is not valid: %w", app.config.Profile, errAWSCLIv1SSOLogin))
+	}
+
+	blue.Println("🔐 Logging in to AWS SSO...")
+
+	ctx, cancel := app.withTimeout(app.loginTimeout())
+	defer cancel()
+
+	err := app.run(ctx, Command{Name: "aws", Args: app.awsArgs("sso", "login"), Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr})
+	if err != nil {
+		return withExitCode(ExitSSOLoginFailed, "sso_login_failed", fmt.Errorf("SSO login failed: %w", timeoutError(ctx, "aws sso login", app.loginTimeout(), err)))
+	}
+
+	green.Println("✓ SSO login successful")
+	return nil
+}
+
+// ListEKSClusters retrieves available EKS clusters
+func (app *EKSLoginApp) ListEKSClusters() ([]string, error) {
+	blue.Println("📋 Fetching EKS clusters...")
+
+	spinner := app.StartSpinner("Listing clusters in " + app.config.Region)
+	clusters, err := app.listClusters()
+	spinner.Stop()
+	return clusters, err
+}
+
+// listClusters lists the clusters of the selected region without printing,
+// so that several regions can be listed at once
+func (app *EKSLoginApp) listClusters() ([]string, error) {
+	output, err := app.AWS("eks", "list-clusters",
+		"--region", app.config.Region,
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EKS clusters: %w", err)
+	}
+
+	var response ListClustersResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster list: %w", err)
+	}
+
+	return response.Clusters, nil
+}
+
+// SelectCluster allows interactive cluster selection. With several --region
+// values, the clusters of all of them are offered.
+func (app *EKSLoginApp) SelectCluster() error {
+	regions := app.regions()
+	if len(regions) <= 1 {
+		regions = []string{app.config.Region}
+	}
+
+	mode, err := app.clusterSort()
+	if err != nil {
+		return err
+	}
+
+	targets, err := app.ListClusterTargets(regions)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		return withExitCode(ExitNoClusters, "no_clusters", fmt.Errorf("no EKS clusters found in region %s with profile %s", strings.Join(regions, ", "), app.config.Profile))
+	}
+
+	// If only one cluster, use it
+	if len(targets) == 1 {
+		app.useClusterTarget(targets[0])
+		cyan.Printf("🎯 Using cluster: %s\n", app.config.Cluster)
+		return nil
+	}
+
+	app.SortTargets(targets, mode)
+
+	// Reuse or preselect the cluster picked last time
+	last := -1
+	if lastCluster := app.LastCluster(); lastCluster != "" {
+		for i, target := range targets {
+			if target.Cluster == lastCluster && target.Region == app.config.Region {
+				last = i
+			}
+		}
+	}
+	if last >= 0 && app.config.Reuse {
+		app.useClusterTarget(targets[last])
+		cyan.Printf("🎯 Reusing last cluster: %s\n", app.config.Cluster)
+		return nil
+	}
+
+	// Interactive selection
+	items := make([]string, len(targets))
+	for i, target := range targets {
+		items[i] = target.describe(len(regions) > 1)
+	}
+
+	blue.Printf("\n🎯 Available EKS Clusters in %s:\n", strings.Join(regions, ", "))
+	choices, err := app.SelectMany("cluster", items, last)
+	if err != nil {
+		return err
+	}
+
+	app.useClusterTarget(targets[choices[0]])
+	if len(choices) > 1 {
+		app.config.Clusters = make([]ClusterTarget, len(choices))
+		for i, choice := range choices {
+			app.config.Clusters[i] = targets[choice]
+		}
+	}
+
+	return nil
+}
+
+// useClusterTarget selects target as the cluster to log in to
+func (app *EKSLoginApp) useClusterTarget(target ClusterTarget) {
+	app.config.Region = target.Region
+	app.config.Cluster = target.Cluster
+}
+
+// UpdateKubeconfig updates the kubeconfig file
+func (app *EKSLoginApp) UpdateKubeconfig() error {
+	blue.Printf("⚙️  Updating kubeconfig for cluster: %s\n", app.config.Cluster)
+
+	tokenExec, err := app.tokenExec()
+	if err != nil {
+		return err
+	}
+	if proxy := app.proxyURL(); proxy != "" {
+		if _, err := parseProxyURL(proxy); err != nil {
+			return err
+		}
+	}
+	if err := app.useSplitKubeconfig(); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	lock, err := app.lockKubeconfig()
+	if err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	defer lock.Unlock()
+
+	if err := app.ConfirmOverwrite(); err != nil {
+		return err
+	}
+	if app.config.Diff {
+		if err := app.PreviewKubeconfig(tokenExec); err != nil {
+			return err
+		}
+	}
+	app.BackupKubeconfig()
+
+	args := app.updateKubeconfigArgs()
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	command := Command{Name: "aws", Args: args, Stdout: os.Stdout, Stderr: io.MultiWriter(os.Stderr, &stderr)}
+	spinner := app.StartSpinner("Running aws eks update-kubeconfig")
+	if spinner.Active() {
+		// Keep the aws output off the spinner line; it is printed once the step ends
+		command.Stdout, command.Stderr = &stdout, &stderr
+	}
+	err = app.run(ctx, command)
+	spinner.Stop()
+	if spinner.Active() {
+		os.Stdout.Write(stdout.Bytes())
+		os.Stderr.Write(stderr.Bytes())
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to update kubeconfig: %w", timeoutError(ctx, "aws eks update-kubeconfig", app.timeout(), err))
+		if strings.Contains(stderr.String(), "ResourceNotFoundException") {
+			return withExitCode(ExitClusterNotFound, "cluster_not_found",
+				fmt.Errorf("cluster %s not found in region %s: %w", app.config.Cluster, app.config.Region, err))
+		}
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	// Apply eks-login's changes to the entries update-kubeconfig wrote in one
+	// pass, so the file is rewritten once
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	if err := app.RenameUser(kubeconfig); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to rename kubeconfig user: %w", err))
+	}
+
+	if tokenExec == "eks-login" {
+		if err := app.UseTokenExec(kubeconfig); err != nil {
+			return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to point kubeconfig at eks-login token: %w", err))
+		}
+	}
+
+	if err := app.SetExecEnv(kubeconfig); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to set the exec environment in kubeconfig: %w", err))
+	}
+
+	if err := app.SetProxyURL(kubeconfig); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to set proxy-url in kubeconfig: %w", err))
+	}
+
+	if err := app.ApplyNamespace(kubeconfig); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to set the default namespace: %w", err))
+	}
+
+	if err := app.RecordMetadata(kubeconfig); err != nil {
+		yellow.Printf("⚠️  Unable to record eks-login metadata in kubeconfig: %v\n", err)
+	}
+
+	if err := kubeconfig.Save(path); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	green.Println("✓ Kubeconfig updated successfully!")
+	if path, _ := app.splitKubeconfigPath(); path != "" {
+		cyan.Printf("📁 Written to %s; see every cluster with: export KUBECONFIG=$(eks-login kubeconfig-path)\n", path)
+	}
+	return nil
+}
+
+// updateKubeconfigArgs returns the aws arguments that write the selected cluster's context
+func (app *EKSLoginApp) updateKubeconfigArgs() []string {
+	args := []string{
+		"eks", "update-kubeconfig",
+		"--region", app.config.Region,
+		"--name", app.config.Cluster,
+	}
+	// Logins holding credentials (an assumed organization role, SSO role
+	// credentials for AWS CLI v1, a web identity) pass them in the environment,
+	// which an explicit --profile would override; the exec env names the
+	// profile for kubectl instead, like awsArgs
+	if app.config.Profile != "" && app.credentials == nil {
+		args = append(args, "--profile", app.config.Profile)
+	}
+	if app.config.RoleARN != "" {
+		args = append(args, "--role-arn", app.config.RoleARN)
+	}
+	return args
+}
+
+// VerifyConnection verifies the connection to the cluster
+func (app *EKSLoginApp) VerifyConnection() error {
+	if app.config.VerifyWithKubectl && app.requireKubectl("kubectl verification") {
+		return app.VerifyConnectionWithKubectl()
+	}
+
+	blue.Println("🔍 Verifying cluster connection...")
+
+	spinner := app.StartSpinner("Connecting to the API server")
+	client, err := app.NewClusterClient()
+	var unreachable *EndpointUnreachableError
+	if errors.As(err, &unreachable) {
+		spinner.Stop()
+		app.printEndpointGuidance(unreachable)
+		return nil
+	}
+	if err != nil {
+		spinner.Stop()
+		yellow.Printf("⚠️  Kubeconfig updated but unable to verify connection: %v\n", err)
+		return nil
+	}
+
+	version, latency, err := client.ServerVersion()
+	spinner.Stop()
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if identity, err := app.GetCallerIdentity(); err == nil {
+			app.printNoAccess(PrincipalARN(identity.Arn))
+			return nil
+		}
+	}
+	if err != nil {
+		yellow.Printf("⚠️  Kubeconfig updated but unable to verify connection: %v\n", err)
+		return nil
+	}
+
+	if err := client.Healthz(); err != nil {
+		yellow.Printf("⚠️  Connected, but the API server is unhealthy: %v\n", err)
+	} else {
+		green.Println("✓ Successfully connected to cluster!")
+	}
+
+	cyan.Printf("📦 Kubernetes %s (latency: %s)\n", version.GitVersion, latency.Round(time.Millisecond))
+	app.CheckVersionSkew(version)
+
+	if app.wantsHealthCheck() {
+		app.checkHealth(client, latency)
+	}
+	return nil
+}
+
+// VerifyConnectionWithKubectl verifies the connection using kubectl cluster-info
+func (app *EKSLoginApp) VerifyConnectionWithKubectl() error {
+	blue.Println("🔍 Verifying cluster connection...")
+
+	// Check if kubectl can connect
+	output, err := app.Execute("kubectl", "cluster-info")
+	if err != nil && strings.Contains(err.Error(), "Unauthorized") {
+		// The API server does not know the principal: no access entry or aws-auth mapping
+		if identity, err := app.GetCallerIdentity(); err == nil {
+			app.printNoAccess(PrincipalARN(identity.Arn))
+			return nil
+		}
+	}
+	if err != nil {
+		yellow.Println("⚠️  Kubeconfig updated but unable to verify connection")
+		return nil
+	}
+
+	green.Println("✓ Successfully connected to cluster!")
+
+	// Show current context
+	if context, err := app.Execute("kubectl", "config", "current-context"); err == nil {
+		cyan.Printf("📍 Current context: %s\n", context)
+	}
+
+	// Optionally show cluster info
+	fmt.Println("\n" + strings.TrimSpace(output))
+
+	if app.wantsHealthCheck() {
+		app.CheckHealth()
+	}
+	return nil
+}
+
+// ShowSummary displays a summary of the operation
+func (app *EKSLoginApp) ShowSummary() {
+	green.Println("\n🎉 EKS Login Complete!")
+	if app.webIdentity != nil {
+		fmt.Printf("Web identity role: %s\n", app.webIdentity.RoleARN)
+	} else {
+		fmt.Printf("Profile: %s\n", app.config.Profile)
+	}
+	fmt.Printf("Region: %s\n", app.config.Region)
+	fmt.Printf("Cluster: %s\n", app.config.Cluster)
+	if sts := app.describeSTSRegion(); sts != "" {
+		fmt.Printf("Tokens signed by STS in: %s\n", sts)
+	}
+	if app.config.RoleARN != "" && app.roleDuration() != 0 {
+		fmt.Printf("Role: %s (sessions of %s)\n", app.config.RoleARN, formatRoleDuration(app.roleDuration()))
+	} else if app.config.RoleARN != "" {
+		fmt.Printf("Role: %s\n", app.config.RoleARN)
+	}
+	if app.config.Namespace != "" {
+		fmt.Printf("Namespace: %s\n", app.config.Namespace)
+	}
+	printExpiry(app.SessionExpiry())
+	if app.health != nil {
+		app.health.Print()
+	}
+	fmt.Println("\nYou can now use kubectl to interact with your cluster.")
+}
+
+// LoadSettings loads the config file selected by --config
+func (app *EKSLoginApp) LoadSettings() error {
+	settings, err := LoadSettings(app.config.ConfigFile)
+	if err != nil {
+		return err
+	}
+	app.settings = settings
+
+	if err := app.loadProjectSettings(); err != nil {
+		return err
+	}
+	app.applyDefaults()
+	if err := applyTheme(app.settings.Theme); err != nil {
+		return err
+	}
+	if app.accessibleEnabled() {
+		app.EnableAccessibleMode()
+	}
+
+	overrides, err := app.endpointOverrides()
+	if err != nil {
+		return err
+	}
+	app.endpointEnv = endpointEnv(overrides)
+
+	if region := app.config.STSRegion; region != "" && !regionPattern.MatchString(region) {
+		return usageError("invalid --sts-region %q, e.g. ap-southeast-2", region)
+	}
+	if err := checkRoleDuration(app.config.RoleDuration); err != nil {
+		return usageError("invalid --duration: %v", err)
+	}
+
+	return nil
+}
+
+// applyDefaults fills options the user did not pass from the config file's defaults
+func (app *EKSLoginApp) applyDefaults() {
+	defaults := app.settings.Default
+	// A web identity role takes the place of the profile
+	if app.config.Profile == "" && app.config.WebIdentityRole == "" {
+		app.config.Profile = defaults.Profile
+	}
+	if !app.config.RegionSet && defaults.Region != "" {
+		app.config.Region = defaults.Region
+		app.config.Regions = []string{defaults.Region}
+		app.config.RegionSet = true
+	}
+	if app.config.Cluster == "" {
+		app.config.Cluster = defaults.Cluster
+	}
+	if app.config.Namespace == "" {
+		app.config.Namespace = defaults.Namespace
+	}
+}
+
+// Authenticate selects the profile and makes sure its SSO session is valid
+func (app *EKSLoginApp) Authenticate() error {
+	// CI jobs assume their role with an OIDC token; there is no profile or SSO session
+	if app.config.WebIdentityRole != "" {
+		if err := app.RunHooks("pre-login", app.settings.Hooks.PreLogin); err != nil {
+			return err
+		}
+		return app.AuthenticateWebIdentity()
+	}
+
+	// Select profile if not provided
+	if app.config.Profile == "" {
+		if err := app.SelectProfile(); err != nil {
+			return err
+		}
+	} else if !app.config.RegionSet {
+		// Default to the profile's own region, which also selects the partition
+		if region := app.ProfileRegion(app.config.Profile); region != "" {
+			app.config.Region = region
+		}
+	}
+
+	// Run pre-login hooks
+	if err := app.RunHooks("pre-login", app.settings.Hooks.PreLogin); err != nil {
+		return err
+	}
+
+	// Check SSO session
+	if sessionValid, err := app.CheckSSOSession(); err != nil {
+		return fmt.Errorf("failed to check SSO session: %w", err)
+	} else if sessionValid {
+		green.Println("✓ SSO session is valid")
+	} else {
+		if err := app.LoginSSO(); err != nil {
+			return err
+		}
+	}
+
+	// Switch to another permission set of the account if asked to
+	return app.SelectSSORole()
+}
+
+// SelectTarget authenticates and resolves the cluster to work with
+func (app *EKSLoginApp) SelectTarget() error {
+	// Pick the cluster first across all profiles; its profile follows from it
+	if app.config.Everywhere && app.config.Profile == "" {
+		if err := app.SelectEverywhere(); err != nil {
+			return err
+		}
+		return app.Authenticate()
+	}
+
+	if err := app.Authenticate(); err != nil {
+		return err
+	}
+
+	// Select cluster if not provided
+	if app.config.Cluster == "" && app.config.OrgRole != "" {
+		if err := app.SelectOrgCluster(app.config.OrgRole); err != nil {
+			return err
+		}
+	} else if app.config.Cluster == "" {
+		if err := app.SelectCluster(); err != nil {
+			return err
+		}
+	} else if err := app.MatchCluster(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes the login flow and records its outcome in the audit log
+func (app *EKSLoginApp) Run() error {
+	err := app.login()
+	if len(app.config.Clusters) <= 1 && !app.config.DryRun {
+		app.Audit("login", err)
+	}
+	return err
+}
+
+// login executes the main application logic
+func (app *EKSLoginApp) login() error {
+	if app.settings.ProjectFile != "" {
+		cyan.Printf("📁 Using project config: %s\n", app.settings.ProjectFile)
+	}
+
+	// CI runs must name their target
+	if err := app.requireExplicitTarget(); err != nil {
+		return err
+	}
+
+	// Check dependencies
+	if err := app.CheckDependencies(); err != nil {
+		return err
+	}
+
+	// Resolve profile and cluster
+	if err := app.SelectTarget(); err != nil {
+		return err
+	}
+
+	// Show what the login would do instead of doing it
+	if app.config.DryRun {
+		return app.dryRunClusters()
+	}
+
+	// Several clusters were picked: set up a context for each
+	if len(app.config.Clusters) > 1 {
+		return app.SetupContexts(app.config.Clusters)
+	}
+
+	// Check the caller is mapped into the cluster
+	app.CheckClusterAccess()
+
+	// Run pre-kubeconfig hooks
+	if err := app.RunHooks("pre-kubeconfig", app.settings.Hooks.PreKubeconfig); err != nil {
+		return err
+	}
+
+	// Guard protected clusters
+	if err := app.ConfirmProtected(); err != nil {
+		return err
+	}
+
+	// Update kubeconfig
+	if err := app.UpdateKubeconfig(); err != nil {
+		return err
+	}
+
+	// Report logins to protected clusters
+	app.PostLoginEvent()
+
+	// Remember the cluster for the next run
+	app.RememberCluster()
+
+	// Verify connection
+	if err := app.VerifyConnection(); err != nil {
+		return err
+	}
+
+	// Pick namespace interactively; a --namespace default was set by UpdateKubeconfig
+	if app.config.Namespace == "" && app.config.SelectNamespace && app.requireKubectl("namespace selection") {
+		if err := app.SelectNamespace(); err != nil {
+			return err
+		}
+		if app.config.Namespace != "" {
+			if err := app.SetNamespace(app.config.Namespace); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Summarize RBAC permissions of the login's own context, before a
+	// read-only context becomes current
+	if app.config.RBACCheck && app.requireKubectl("the RBAC check") {
+		app.RBACSmokeTest()
+	}
+
+	// Add a read-only context
+	if app.wantsReadOnly() {
+		if err := app.CreateReadOnlyContext(); err != nil {
+			return err
+		}
+	}
+
+	// Log in to ECR
+	if app.config.ECR {
+		if err := app.LoginECR(); err != nil {
+			return err
+		}
+	}
+
+	// Show summary
+	app.ShowSummary()
+
+	// Opt-in check for a newer release
+	app.MaybeCheckForUpdate()
+
+	// Launch k9s or the configured tool
+	if app.config.LaunchK9s || app.settings.Launch.Enabled {
+		return app.LaunchTool()
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ARN is a parsed Amazon Resource Name
+type ARN struct {
+	Partition string
+	Service   string
+	Region    string
+	AccountID string
+	Resource  string
+}
+
+// ParseARN splits an ARN string into its components
+func ParseARN(value string) (ARN, error) {
+	parts := strings.SplitN(value, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ARN{}, fmt.Errorf("invalid ARN: %s", value)
+	}
+
+	return ARN{
+		Partition: parts[1],
+		Service:   parts[2],
+		Region:    parts[3],
+		AccountID: parts[4],
+		Resource:  parts[5],
+	}, nil
+}
+
+// String formats the ARN
+func (a ARN) String() string {
+	return strings.Join([]string{"arn", a.Partition, a.Service, a.Region, a.AccountID, a.Resource}, ":")
+}
+
+// PrincipalARN converts an STS assumed-role ARN to the IAM role ARN it was assumed from.
+// Other ARNs are returned unchanged.
+func PrincipalARN(callerARN string) string {
+	arn, err := ParseARN(callerARN)
+	if err != nil || arn.Service != "sts" || !strings.HasPrefix(arn.Resource, "assumed-role/") {
+		return callerARN
+	}
+
+	role := strings.Split(strings.TrimPrefix(arn.Resource, "assumed-role/"), "/")[0]
+	return ARN{
+		Partition: arn.Partition,
+		Service:   "iam",
+		AccountID: arn.AccountID,
+		Resource:  "role/" + role,
+	}.String()
+}
+
+// SamePrincipal compares two IAM principal ARNs ignoring the IAM path,
+// which aws-auth and some access entries omit for SSO roles.
+func SamePrincipal(a, b string) bool {
+	arnA, errA := ParseARN(a)
+	arnB, errB := ParseARN(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	kind := func(resource string) (string, string) {
+		segments := strings.Split(resource, "/")
+		return segments[0], segments[len(segments)-1]
+	}
+	kindA, nameA := kind(arnA.Resource)
+	kindB, nameB := kind(arnB.Resource)
+
+	return arnA.AccountID == arnB.AccountID && kindA == kindB && nameA == nameB
+}
@@ -0,0 +1,268 @@
+package ekslogin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// AuditConfig configures the local audit log
+type AuditConfig struct {
+	// Path overrides the location of the JSONL audit log
+	Path string `yaml:"path,omitempty"`
+	// Disabled turns the audit log off
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// AuditEvent is one line of the audit log
+type AuditEvent struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	User    string    `json:"user,omitempty"`
+	Profile string    `json:"profile,omitempty"`
+	Account string    `json:"account,omitempty"`
+	Region  string    `json:"region,omitempty"`
+	Cluster string    `json:"cluster,omitempty"`
+	Outcome string    `json:"outcome"`
+	Reason  string    `json:"reason,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// auditPath returns the location of the audit log
+func (app *EKSLoginApp) auditPath() (string, error) {
+	if app.settings.Audit.Path != "" {
+		return app.settings.Audit.Path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "eks-login", "audit.jsonl"), nil
+}
+
+// appendAudit appends event to the audit log under its lock
+func (app *EKSLoginApp) appendAudit(event AuditEvent) error {
+	path, err := app.auditPath()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	_, err = file.Write(append(line, '\n'))
+	return errors.Join(err, file.Close())
+}
+
+// Audit records the outcome of an action on the selected target. Failing to
+// write the log is only warned about.
+func (app *EKSLoginApp) Audit(action string, err error) {
+	if app.settings.Audit.Disabled {
+		return
+	}
+
+	event := AuditEvent{
+		Time:    time.Now().UTC(),
+		Action:  action,
+		Profile: app.config.Profile,
+		Region:  app.config.Region,
+		Cluster: app.config.Cluster,
+		Outcome: "success",
+	}
+	if current, err := user.Current(); err == nil {
+		event.User = current.Username
+	}
+	if err != nil {
+		_, event.Reason = exitCode(err)
+		event.Outcome = "failure"
+		event.Error = err.Error()
+	} else if _, metadata, err := currentContext(); err == nil && metadata != nil && metadata.Cluster == app.config.Cluster {
+		event.Account = metadata.Account
+	}
+
+	if err := app.appendAudit(event); err != nil {
+		yellow.Printf("⚠️  Unable to write audit log: %v\n", err)
+	}
+}
+
+// AuditFilter selects audit events
+type AuditFilter struct {
+	Since   time.Duration
+	Action  string
+	Profile string
+	Cluster string
+	Outcome string
+}
+
+// matches reports whether event passes the filter; profile and cluster are globs
+func (f AuditFilter) matches(event AuditEvent) bool {
+	if f.Since > 0 && time.Since(event.Time) > f.Since {
+		return false
+	}
+	if f.Action != "" && event.Action != f.Action {
+		return false
+	}
+	if f.Outcome != "" && event.Outcome != f.Outcome {
+		return false
+	}
+	if f.Profile != "" && !matchesAny([]string{f.Profile}, event.Profile) {
+		return false
+	}
+	if f.Cluster != "" && !matchesAny([]string{f.Cluster}, event.Cluster) {
+		return false
+	}
+	return true
+}
+
+// ReadAudit returns the audit events that pass filter, oldest first
+func (app *EKSLoginApp) ReadAudit(filter AuditFilter) ([]AuditEvent, error) {
+	path, err := app.auditPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid audit event: %w", path, line, err)
+		}
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return events, nil
+}
+
+// writeAudit renders audit events as a table, YAML or JSON lines
+func writeAudit(events []AuditEvent, output string) error {
+	switch output {
+	case outputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return printOutput(output, events, func() error {
+			table := NewTable("", "TIME", "ACTION", "USER", "PROFILE", "ACCOUNT", "CLUSTER", "OUTCOME")
+			for _, e := range events {
+				outcome := e.Outcome
+				if e.Reason != "" {
+					outcome += " (" + e.Reason + ")"
+				}
+				table.Row(e.Time.Local().Format("2006-01-02 15:04:05"), e.Action, e.User, e.Profile, e.Account, e.Cluster, outcome)
+			}
+			return table.Write(os.Stdout)
+		})
+	}
+}
+
+// Logout ends the cached SSO sessions via aws sso logout
+func (app *EKSLoginApp) Logout() error {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var output bytes.Buffer
+	err := app.run(ctx, Command{Name: "aws", Args: app.awsArgs("sso", "logout"), Stdout: &output, Stderr: &output})
+	if err != nil {
+		detail := strings.TrimSpace(output.String())
+		if detail == "" {
+			detail = timeoutError(ctx, "aws sso logout", app.timeout(), err).Error()
+		}
+		return fmt.Errorf("aws sso logout failed: %s", detail)
+	}
+
+	green.Println("✓ Logged out of AWS SSO")
+	return nil
+}
+
+// newLogoutCmd creates the logout subcommand
+func newLogoutCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "End the cached AWS SSO sessions (aws sso logout)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := app.Logout()
+			app.Audit("logout", err)
+			return err
+		},
+	}
+}
+
+// newAuditCmd creates the audit subcommand
+func newAuditCmd(app *EKSLoginApp) *cobra.Command {
+	var filter AuditFilter
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the local audit log of logins and logouts",
+		Long: `Query the append-only JSONL audit log that records the time, user, profile,
+account, cluster and outcome of every login and logout.
+
+--profile and --cluster filter by glob. The log is kept next to the config
+file unless audit.path is set.`,
+		Example: `  eks-login audit --since 720h --cluster 'prod-*'
+  eks-login audit --outcome failure -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("profile") {
+				filter.Profile = app.config.Profile
+			}
+			if cmd.Flags().Changed("cluster") {
+				filter.Cluster = app.config.Cluster
+			}
+			events, err := app.ReadAudit(filter)
+			if err != nil {
+				return err
+			}
+			return writeAudit(events, output)
+		},
+	}
+
+	cmd.Flags().DurationVar(&filter.Since, "since", 0, "Only show events newer than this, e.g. 24h")
+	cmd.Flags().StringVar(&filter.Action, "action", "", "Only show this action: login, logout or refresh")
+	cmd.Flags().StringVar(&filter.Outcome, "outcome", "", "Only show this outcome: success or failure")
+	// json stays JSON lines, one event each, for log tooling
+	addOutputFlag(cmd, &output)
+	return cmd
+}
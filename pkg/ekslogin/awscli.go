@@ -0,0 +1,86 @@
+package ekslogin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// awsCLIInstallURL explains how to install AWS CLI v2
+const awsCLIInstallURL = "https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html"
+
+// errAWSCLIv1SSOLogin is returned when an SSO login is needed but only AWS CLI v1 is installed
+var errAWSCLIv1SSOLogin = errors.New("AWS CLI v1 has no 'aws sso login': install AWS CLI v2 (" + awsCLIInstallURL +
+	"), or log in with a tool that writes the SSO token cache in ~/.aws/sso/cache")
+
+// AWSCLIInfo is the remembered version of the aws binary at Path, valid while
+// the binary's modification time is unchanged
+type AWSCLIInfo struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"mod_time"`
+	Version string    `json:"version"`
+	Major   int       `json:"major"`
+}
+
+// detectAWSCLI finds out which major version of the AWS CLI is installed,
+// running 'aws --version' only when the binary changed since the last run
+func (app *EKSLoginApp) detectAWSCLI() {
+	path, err := exec.LookPath("aws")
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	cached := app.LoadState().AWSCLI
+	if cached == nil || cached.Path != path || !cached.ModTime.Equal(info.ModTime()) {
+		version, major, err := app.AWSCLIVersion()
+		if err != nil {
+			return
+		}
+		cached = &AWSCLIInfo{Path: path, ModTime: info.ModTime(), Version: version, Major: major}
+		app.UpdateState(func(state *State) {
+			state.AWSCLI = cached
+		})
+	}
+
+	app.awsCLIMajor = cached.Major
+	if app.awsV1() {
+		yellow.Printf("  ⚠️  AWS CLI v%s found: v1 cannot run 'aws sso login' and its 'aws eks get-token' differs from v2.\n", cached.Version)
+		yellow.Println("     eks-login generates tokens itself and uses cached SSO sessions where it can;")
+		yellow.Printf("     install AWS CLI v2 for SSO logins: %s\n", awsCLIInstallURL)
+	}
+}
+
+// awsV1 reports whether the installed AWS CLI is v1
+func (app *EKSLoginApp) awsV1() bool {
+	return app.awsCLIMajor == 1
+}
+
+// useCachedSSOCredentials lets AWS CLI v1, which cannot read every SSO
+// profile, act for an SSO profile: the role credentials are resolved from the
+// cached SSO token in-process and handed to the CLI in its environment. It
+// reports whether credentials were resolved.
+func (app *EKSLoginApp) useCachedSSOCredentials() bool {
+	if app.credentials != nil {
+		return true
+	}
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		return false
+	}
+	settings, ok := files.profile(app.config.Profile)
+	if !ok || (settings["sso_session"] == "" && settings["sso_start_url"] == "") {
+		return false
+	}
+
+	creds, err := app.ssoRoleCredentials(files, settings)
+	if err != nil {
+		return false
+	}
+	app.credentials = creds
+	return true
+}
@@ -0,0 +1,687 @@
+package ekslogin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errUnsupportedProfile is returned for profiles whose credentials cannot be
+// resolved without the AWS CLI, e.g. MFA or web identity profiles
+var errUnsupportedProfile = errors.New("profile is not supported without the AWS CLI")
+
+// maxSourceProfiles limits role_arn/source_profile chains
+const maxSourceProfiles = 5
+
+// iniSections maps section names to their keys and values
+type iniSections map[string]map[string]string
+
+// readINI parses an AWS shared config or credentials file. Indented
+// sub-settings (such as s3 options) are skipped; a missing file is empty.
+func readINI(path string) (iniSections, error) {
+	sections := iniSections{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sections, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			name := strings.Join(strings.Fields(strings.Trim(trimmed, "[]")), " ")
+			current = sections[name]
+			if current == nil {
+				current = map[string]string{}
+				sections[name] = current
+			}
+		case current != nil && line[0] != ' ' && line[0] != '\t':
+			if key, value, ok := strings.Cut(trimmed, "="); ok {
+				current[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return sections, scanner.Err()
+}
+
+// setINISection writes section to the AWS shared config or credentials file at
+// path with the given keys, replacing its previous contents if it exists.
+// comments are written below the section header. Other sections and comments
+// are left as they are.
+func setINISection(path, section string, values map[string]string, comments ...string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	body := []string{"[" + section + "]"}
+	for _, comment := range comments {
+		body = append(body, "# "+comment)
+	}
+	for _, key := range keys {
+		body = append(body, key+" = "+values[key])
+	}
+
+	var lines []string
+	found, inSection := false, false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			inSection = strings.Join(strings.Fields(strings.Trim(trimmed, "[]")), " ") == section
+			if inSection {
+				found = true
+				lines = append(lines, body...)
+				continue
+			}
+		}
+		if !inSection {
+			lines = append(lines, line)
+		}
+	}
+	if !found {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, body...)
+	}
+	if len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// awsFile returns the shared AWS file named by env, or ~/.aws/name
+func awsFile(env, name string) string {
+	if path := os.Getenv(env); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// awsConfigFiles holds the parsed shared config and credentials files
+type awsConfigFiles struct {
+	config      iniSections
+	credentials iniSections
+}
+
+// loadAWSConfigFiles reads the shared config and credentials files
+func loadAWSConfigFiles() (*awsConfigFiles, error) {
+	config, err := readINI(awsFile("AWS_CONFIG_FILE", "config"))
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := readINI(awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials"))
+	if err != nil {
+		return nil, err
+	}
+	return &awsConfigFiles{config: config, credentials: credentials}, nil
+}
+
+// profile returns the settings of a profile, with credentials file values
+// taking precedence over the config file
+func (f *awsConfigFiles) profile(name string) (map[string]string, bool) {
+	section := "profile " + name
+	if name == "default" {
+		if _, ok := f.config[section]; !ok {
+			section = "default"
+		}
+	}
+
+	settings := map[string]string{}
+	config, inConfig := f.config[section]
+	for key, value := range config {
+		settings[key] = value
+	}
+	credentials, inCredentials := f.credentials[name]
+	for key, value := range credentials {
+		settings[key] = value
+	}
+	return settings, inConfig || inCredentials
+}
+
+// environmentCredentials returns the credentials of the AWS_* variables, if set
+func environmentCredentials() *AWSCredentials {
+	if os.Getenv("AWS_ACCESS_KEY_ID") == "" || os.Getenv("AWS_SECRET_ACCESS_KEY") == "" {
+		return nil
+	}
+	return &AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// selectedProfile returns the --profile value or AWS_PROFILE
+func (app *EKSLoginApp) selectedProfile() string {
+	if app.config.Profile != "" {
+		return app.config.Profile
+	}
+	return os.Getenv("AWS_PROFILE")
+}
+
+// resolveRegion returns the region for in-process AWS calls: --region,
+// AWS_REGION, the profile's region, then the default region
+func (app *EKSLoginApp) resolveRegion() string {
+	if app.config.Region != "" {
+		return app.config.Region
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(env); region != "" {
+			return region
+		}
+	}
+
+	profile := app.selectedProfile()
+	if profile == "" {
+		profile = "default"
+	}
+	if files, err := loadAWSConfigFiles(); err == nil {
+		if settings, ok := files.profile(profile); ok && settings["region"] != "" {
+			return settings["region"]
+		}
+	}
+	return app.config.DefaultRegion
+}
+
+// ResolveCredentials resolves the credentials of the selected profile without the
+// AWS CLI: static keys, credential_process, cached SSO sessions and role_arn
+// chains are supported. Other profiles fall back to the AWS CLI when it is installed.
+func (app *EKSLoginApp) ResolveCredentials() (*AWSCredentials, error) {
+	if app.credentials != nil {
+		return app.credentials, nil
+	}
+
+	profile := app.selectedProfile()
+	if profile == "" {
+		if creds := environmentCredentials(); creds != nil {
+			return creds, nil
+		}
+		if identity := environmentWebIdentity(); identity != nil {
+			return app.stsAssumeRoleWithWebIdentity(app.resolveRegion(), identity)
+		}
+		profile = "default"
+	}
+
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := app.profileCredentials(files, profile, 0)
+	if errors.Is(err, errUnsupportedProfile) {
+		if _, lookErr := exec.LookPath("aws"); lookErr == nil && !app.awsV1() {
+			return app.ExportCredentials()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials for profile %s: %w", profile, err)
+	}
+	return creds, nil
+}
+
+// profileCredentials resolves the credentials of a named profile
+func (app *EKSLoginApp) profileCredentials(files *awsConfigFiles, name string, depth int) (*AWSCredentials, error) {
+	if depth > maxSourceProfiles {
+		return nil, fmt.Errorf("source_profile chain is longer than %d profiles", maxSourceProfiles)
+	}
+
+	settings, ok := files.profile(name)
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+
+	static := func() *AWSCredentials {
+		if settings["aws_access_key_id"] == "" {
+			return nil
+		}
+		return &AWSCredentials{
+			AccessKeyID:     settings["aws_access_key_id"],
+			SecretAccessKey: settings["aws_secret_access_key"],
+			SessionToken:    settings["aws_session_token"],
+		}
+	}
+
+	switch {
+	case settings["role_arn"] != "":
+		if settings["mfa_serial"] != "" {
+			return nil, errUnsupportedProfile
+		}
+		if settings["web_identity_token_file"] != "" {
+			region := settings["region"]
+			if region == "" {
+				region = app.config.Region
+			}
+			return app.stsAssumeRoleWithWebIdentity(region, &webIdentityCredentials{
+				RoleARN:     settings["role_arn"],
+				TokenFile:   settings["web_identity_token_file"],
+				SessionName: settings["role_session_name"],
+			})
+		}
+
+		var source *AWSCredentials
+		switch {
+		case settings["source_profile"] == name:
+			if source = static(); source == nil {
+				return nil, fmt.Errorf("profile %s is its own source_profile but has no static keys", name)
+			}
+		case settings["source_profile"] != "":
+		case settings["credential_source"] == "Environment":
+			if source = environmentCredentials(); source == nil {
+				return nil, fmt.Errorf("credential_source Environment but AWS_ACCESS_KEY_ID is not set")
+			}
+		default:
+			return nil, errUnsupportedProfile
+		}
+
+		region := settings["region"]
+		if region == "" {
+			region = app.config.Region
+		}
+		input := stsAssumeRoleInput{
+			RoleARN:     settings["role_arn"],
+			SessionName: settings["role_session_name"],
+			ExternalID:  settings["external_id"],
+			Duration:    settings["duration_seconds"],
+		}
+		// Like the AWS CLI, a cached session of the role skips the source profile
+		return app.cachedCredentials(assumeRoleCacheKey(input), "", func() (*AWSCredentials, error) {
+			if source == nil {
+				var err error
+				if source, err = app.profileCredentials(files, settings["source_profile"], depth+1); err != nil {
+					return nil, err
+				}
+			}
+			return app.stsAssumeRole(source, region, input)
+		})
+
+	case static() != nil:
+		return static(), nil
+
+	case settings["credential_process"] != "":
+		return app.processCredentials(settings["credential_process"])
+
+	case settings["sso_session"] != "" || settings["sso_start_url"] != "":
+		return app.ssoRoleCredentials(files, settings)
+	}
+
+	return nil, errUnsupportedProfile
+}
+
+// processCredentials runs a credential_process command and parses its output.
+// Like botocore, the command is split into arguments and run without a shell,
+// so quoting, $VARIABLES and metacharacters mean what they mean to the AWS CLI.
+func (app *EKSLoginApp) processCredentials(command string) (*AWSCredentials, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential_process %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("credential_process is empty")
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := Command{Name: args[0], Args: args[1:], Env: os.Environ(), Stdout: &stdout, Stderr: os.Stderr}
+	if err := app.run(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("credential_process failed: %w", timeoutError(ctx, "credential_process", app.timeout(), err))
+	}
+
+	var creds AWSCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credential_process output: %w", err)
+	}
+	return &creds, nil
+}
+
+// splitCommandLine splits a credential_process command into arguments as
+// botocore does: with shlex's POSIX rules, or the Windows rules on Windows
+func splitCommandLine(line string) ([]string, error) {
+	if runtime.GOOS == "windows" {
+		return splitWindowsCommandLine(line)
+	}
+	return splitPOSIXCommandLine(line)
+}
+
+// splitPOSIXCommandLine splits line like Python's shlex.split: single quotes
+// are literal, and within double quotes a backslash only escapes " and \
+func splitPOSIXCommandLine(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case ' ', '\t', '\r', '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case '\\':
+			i++
+			if i == len(line) {
+				return nil, fmt.Errorf("no escaped character")
+			}
+			arg.WriteByte(line[i])
+			inArg = true
+		case '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("no closing quotation")
+			}
+			arg.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case '"':
+			for i++; ; i++ {
+				if i == len(line) {
+					return nil, fmt.Errorf("no closing quotation")
+				}
+				if line[i] == '"' {
+					break
+				}
+				if line[i] == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\') {
+					i++
+				}
+				arg.WriteByte(line[i])
+			}
+			inArg = true
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// splitWindowsCommandLine splits line like botocore's _windows_shell_split,
+// which follows the Microsoft C runtime: backslashes are literal except before
+// a double quote, where each pair becomes one backslash and an odd one escapes
+// the quote
+func splitWindowsCommandLine(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted, backslashes := false, false, 0
+	for _, c := range line {
+		switch {
+		case c == '\\':
+			backslashes++
+			continue
+		case c == '"':
+			arg.WriteString(strings.Repeat("\\", backslashes/2))
+			inArg = true
+			if backslashes%2 == 1 {
+				arg.WriteRune('"')
+			} else {
+				quoted = !quoted
+			}
+		case (c == ' ' || c == '\t') && !quoted:
+			arg.WriteString(strings.Repeat("\\", backslashes))
+			if inArg || backslashes > 0 {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteString(strings.Repeat("\\", backslashes))
+			arg.WriteRune(c)
+			inArg = true
+		}
+		backslashes = 0
+	}
+	if quoted {
+		return nil, fmt.Errorf("no closing quotation")
+	}
+	arg.WriteString(strings.Repeat("\\", backslashes))
+	if inArg || backslashes > 0 {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// ssoSession returns the cached SSO token and the SSO region of a profile
+func ssoSession(files *awsConfigFiles, settings map[string]string) (*ssoCacheEntry, string, error) {
+	key, region := settings["sso_start_url"], settings["sso_region"]
+	if session := settings["sso_session"]; session != "" {
+		sessionSettings, ok := files.config["sso-session "+session]
+		if !ok {
+			return nil, "", fmt.Errorf("sso-session %s not found", session)
+		}
+		key, region = session, sessionSettings["sso_region"]
+	}
+	if region == "" {
+		return nil, "", fmt.Errorf("incomplete SSO configuration: sso_region is required")
+	}
+
+	path, err := ssoCachePath(key)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("no cached SSO token, run 'eks-login' to log in: %w", err)
+	}
+	var entry ssoCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, "", fmt.Errorf("failed to parse SSO token cache: %w", err)
+	}
+	if expiry, err := parseSSOTime(entry.ExpiresAt); err != nil || time.Now().After(expiry) {
+		return nil, "", fmt.Errorf("SSO session has expired, run 'eks-login' to log in again")
+	}
+	return &entry, region, nil
+}
+
+// ssoRoleCredentials exchanges the cached SSO token of a profile for role credentials
+func (app *EKSLoginApp) ssoRoleCredentials(files *awsConfigFiles, settings map[string]string) (*AWSCredentials, error) {
+	if settings["sso_account_id"] == "" || settings["sso_role_name"] == "" {
+		return nil, fmt.Errorf("incomplete SSO configuration: sso_account_id and sso_role_name are required")
+	}
+	return app.cachedCredentials(ssoRoleCacheKey(settings), "sso", func() (*AWSCredentials, error) {
+		return app.fetchSSORoleCredentials(files, settings)
+	})
+}
+
+// fetchSSORoleCredentials calls the SSO portal for the role credentials of a profile
+func (app *EKSLoginApp) fetchSSORoleCredentials(files *awsConfigFiles, settings map[string]string) (*AWSCredentials, error) {
+	entry, region, err := ssoSession(files, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := url.URL{
+		Scheme: "https",
+		Host:   PartitionForRegion(region).Endpoint("portal.sso", region),
+		Path:   "/federation/credentials",
+		RawQuery: url.Values{
+			"account_id": {settings["sso_account_id"]},
+			"role_name":  {settings["sso_role_name"]},
+		}.Encode(),
+	}
+	req, err := http.NewRequestWithContext(app.context(), http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-amz-sso_bearer_token", entry.AccessToken)
+
+	body, err := app.doAWSRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSO role credentials: %w", err)
+	}
+
+	var response struct {
+		RoleCredentials struct {
+			AccessKeyID     string `json:"accessKeyId"`
+			SecretAccessKey string `json:"secretAccessKey"`
+			SessionToken    string `json:"sessionToken"`
+			Expiration      int64  `json:"expiration"`
+		} `json:"roleCredentials"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse SSO role credentials: %w", err)
+	}
+	role := response.RoleCredentials
+	return &AWSCredentials{
+		AccessKeyID:     role.AccessKeyID,
+		SecretAccessKey: role.SecretAccessKey,
+		SessionToken:    role.SessionToken,
+		Expiration:      time.UnixMilli(role.Expiration).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// doAWSRequest sends req and returns the response body, failing on non-2xx statuses
+func (app *EKSLoginApp) doAWSRequest(req *http.Request) ([]byte, error) {
+	client, err := app.HTTPClient(app.timeout())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		var stsError struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}
+		if xml.Unmarshal(body, &stsError) == nil && stsError.Code != "" {
+			return nil, fmt.Errorf("%s: %s", stsError.Code, stsError.Message)
+		}
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Host, resp.Status)
+	}
+	return body, nil
+}
+
+// stsAssumeRoleInput holds the parameters of an STS AssumeRole call
+type stsAssumeRoleInput struct {
+	RoleARN     string
+	SessionName string
+	ExternalID  string
+	Duration    string
+}
+
+// stsEndpoint returns the STS URL of region, honoring endpoint overrides and FIPS
+func (app *EKSLoginApp) stsEndpoint(region string) (*url.URL, error) {
+	overrides, err := app.endpointOverrides()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := overrides["sts"]
+	if endpoint == "" {
+		endpoint = overrides["default"]
+	}
+	if endpoint == "" {
+		service := "sts"
+		if app.fipsEnabled() {
+			service += "-fips"
+		}
+		endpoint = "https://" + PartitionForRegion(region).Endpoint(service, region) + "/"
+	}
+	return url.Parse(endpoint)
+}
+
+// stsAssumeRole assumes a role with source credentials by calling STS directly
+func (app *EKSLoginApp) stsAssumeRole(source *AWSCredentials, region string, input stsAssumeRoleInput) (*AWSCredentials, error) {
+	if region == "" {
+		return nil, fmt.Errorf("a region is required to assume role %s", input.RoleARN)
+	}
+	if input.SessionName == "" {
+		input.SessionName = "eks-login-" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	form := url.Values{
+		"Action":          {"AssumeRole"},
+		"Version":         {"2011-06-15"},
+		"RoleArn":         {input.RoleARN},
+		"RoleSessionName": {input.SessionName},
+	}
+	if input.ExternalID != "" {
+		form.Set("ExternalId", input.ExternalID)
+	}
+	if input.Duration != "" {
+		form.Set("DurationSeconds", input.Duration)
+	}
+	body := []byte(form.Encode())
+
+	endpoint, err := app.stsEndpoint(region)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(app.context(), http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	sigv4Signer{credentials: source, region: region, service: "sts", now: time.Now()}.Sign(req, body)
+
+	response, err := app.doAWSRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %w", input.RoleARN, err)
+	}
+
+	var result struct {
+		Credentials AWSCredentials `xml:"AssumeRoleResult>Credentials"`
+	}
+	if err := xml.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse assumed role credentials: %w", err)
+	}
+	return &result.Credentials, nil
+}
@@ -0,0 +1,319 @@
+package ekslogin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeAWSFiles points the shared config and credentials files at temporary
+// copies of config and credentials
+func writeAWSFiles(t *testing.T, config, credentials string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range map[string]string{"config": config, "credentials": credentials} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	for _, env := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN"} {
+		t.Setenv(env, "")
+	}
+}
+
+const sampleAWSConfig = `[default]
+region = us-west-2
+
+[profile dev]
+region = eu-west-1
+aws_access_key_id = AKIDCONFIG
+aws_secret_access_key = config-secret
+
+[profile deploy]
+role_arn = arn:aws:iam::123456789012:role/deploy
+source_profile = dev
+role_session_name = ci
+external_id = ext-1
+region = eu-central-1
+
+[profile chain]
+role_arn = arn:aws:iam::123456789012:role/chain
+source_profile = deploy
+
+[profile loop]
+role_arn = arn:aws:iam::123456789012:role/loop
+source_profile = loop
+
+[profile mfa]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = dev
+mfa_serial = arn:aws:iam::123456789012:mfa/me
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+
+[profile sso]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = Developer
+`
+
+const sampleAWSCredentials = `[dev]
+aws_access_key_id = AKIDCREDENTIALS
+aws_secret_access_key = credentials-secret
+
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = default-secret
+aws_session_token = default-token
+`
+
+func resolveProfile(t *testing.T, app *EKSLoginApp, profile string) (*AWSCredentials, error) {
+	t.Helper()
+	app.config.Profile = profile
+	return app.ResolveCredentials()
+}
+
+func TestResolveCredentialsStaticKeys(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+
+	creds, err := resolveProfile(t, app, "dev")
+	if err != nil {
+		t.Fatalf("ResolveCredentials(dev): %v", err)
+	}
+	// The credentials file takes precedence over the config file
+	if creds.AccessKeyID != "AKIDCREDENTIALS" || creds.SecretAccessKey != "credentials-secret" {
+		t.Errorf("dev credentials = %+v, want those of the credentials file", creds)
+	}
+
+	creds, err = resolveProfile(t, app, "")
+	if err != nil {
+		t.Fatalf("ResolveCredentials(default): %v", err)
+	}
+	if creds.AccessKeyID != "AKIDDEFAULT" || creds.SessionToken != "default-token" {
+		t.Errorf("default credentials = %+v", creds)
+	}
+}
+
+func TestResolveCredentialsPrefersEnvironmentWithoutProfile(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	creds, err := resolveProfile(t, app, "")
+	if err != nil {
+		t.Fatalf("ResolveCredentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDENV" {
+		t.Errorf("credentials = %+v, want the environment's", creds)
+	}
+}
+
+// stsServer answers AssumeRole calls, recording the form and the credential
+// of each request's signature
+func stsServer(t *testing.T, calls *[]url.Values, signers *[]string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		*calls = append(*calls, form)
+		credential := strings.TrimPrefix(strings.Split(r.Header.Get("Authorization"), ",")[0], sigv4Algorithm+" Credential=")
+		*signers = append(*signers, strings.SplitN(credential, "/", 2)[0])
+
+		role := strings.TrimPrefix(form.Get("RoleArn"), "arn:aws:iam::123456789012:role/")
+		io.WriteString(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>`+
+			`<AccessKeyId>ASIA`+strings.ToUpper(role)+`</AccessKeyId><SecretAccessKey>secret</SecretAccessKey>`+
+			`<SessionToken>token</SessionToken><Expiration>2030-01-01T00:00:00Z</Expiration>`+
+			`</Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestResolveCredentialsAssumesRoleChains(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	var calls []url.Values
+	var signers []string
+	app.settings.Endpoints = map[string]string{"sts": stsServer(t, &calls, &signers)}
+
+	creds, err := resolveProfile(t, app, "deploy")
+	if err != nil {
+		t.Fatalf("ResolveCredentials(deploy): %v", err)
+	}
+	if creds.AccessKeyID != "ASIADEPLOY" {
+		t.Errorf("deploy credentials = %+v", creds)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("%d AssumeRole calls, want 1", len(calls))
+	}
+	form := calls[0]
+	if form.Get("RoleArn") != "arn:aws:iam::123456789012:role/deploy" || form.Get("RoleSessionName") != "ci" || form.Get("ExternalId") != "ext-1" {
+		t.Errorf("AssumeRole form = %v", form)
+	}
+	if signers[0] != "AKIDCREDENTIALS" {
+		t.Errorf("AssumeRole signed by %s, want the source profile's keys", signers[0])
+	}
+
+	calls, signers = nil, nil
+	if creds, err = resolveProfile(t, app, "chain"); err != nil {
+		t.Fatalf("ResolveCredentials(chain): %v", err)
+	}
+	if creds.AccessKeyID != "ASIACHAIN" || len(signers) != 2 || signers[1] != "ASIADEPLOY" {
+		t.Errorf("chain credentials = %+v, signed by %v; want chain assumed with deploy's session", creds, signers)
+	}
+}
+
+func TestResolveCredentialsRejectsUnsupportedProfiles(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.profileCredentials(files, "mfa", 0); !errors.Is(err, errUnsupportedProfile) {
+		t.Errorf("mfa profile: %v, want errUnsupportedProfile", err)
+	}
+	if _, err := app.profileCredentials(files, "loop", 0); err == nil || !strings.Contains(err.Error(), "own source_profile") {
+		t.Errorf("self-sourcing profile without keys: %v", err)
+	}
+	if _, err := app.profileCredentials(files, "missing", 0); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing profile: %v", err)
+	}
+}
+
+func TestResolveCredentialsUsesCachedSSORoleCredentials(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := files.profile("sso")
+	cached := AWSCredentials{
+		AccessKeyID:     "ASIASSO",
+		SecretAccessKey: "sso-secret",
+		SessionToken:    "sso-token",
+		Expiration:      time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	}
+	if err := writeCLICache(ssoRoleCacheKey(settings), "sso", &cached); err != nil {
+		t.Fatal(err)
+	}
+
+	// A cache hit needs no SSO token and no call to the SSO portal
+	creds, err := resolveProfile(t, app, "sso")
+	if err != nil {
+		t.Fatalf("ResolveCredentials(sso): %v", err)
+	}
+	if creds.AccessKeyID != "ASIASSO" {
+		t.Errorf("sso credentials = %+v, want the cached ones", creds)
+	}
+}
+
+func TestResolveRegion(t *testing.T) {
+	writeAWSFiles(t, sampleAWSConfig, sampleAWSCredentials)
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	app.config.Region = ""
+
+	app.config.Profile = "dev"
+	if got := app.resolveRegion(); got != "eu-west-1" {
+		t.Errorf("region of dev = %q, want eu-west-1", got)
+	}
+	app.config.Profile = ""
+	if got := app.resolveRegion(); got != "us-west-2" {
+		t.Errorf("region of default = %q, want us-west-2", got)
+	}
+	t.Setenv("AWS_REGION", "ap-south-1")
+	if got := app.resolveRegion(); got != "ap-south-1" {
+		t.Errorf("region with AWS_REGION = %q, want ap-south-1", got)
+	}
+}
+
+// TestSplitCommandLine checks credential_process splitting against the
+// arguments Python's shlex.split and botocore's Windows splitter produce
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		split func(string) ([]string, error)
+		line  string
+		want  []string
+	}{
+		{splitPOSIXCommandLine, `/opt/bin/creds --profile "my profile"`, []string{"/opt/bin/creds", "--profile", "my profile"}},
+		{splitPOSIXCommandLine, `aws-vault export --format=json 'prod admin'`, []string{"aws-vault", "export", "--format=json", "prod admin"}},
+		{splitPOSIXCommandLine, `creds $HOME/x a\ b`, []string{"creds", "$HOME/x", "a b"}},
+		{splitPOSIXCommandLine, `say "a \"q\" \$x" ''`, []string{"say", `a "q" \$x`, ""}},
+		{splitPOSIXCommandLine, `c:\tools\creds.exe`, []string{"c:toolscreds.exe"}},
+		{splitPOSIXCommandLine, `a  ""  b`, []string{"a", "", "b"}},
+		{splitWindowsCommandLine, `C:\tools\creds.exe --name "a b"`, []string{`C:\tools\creds.exe`, "--name", "a b"}},
+		{splitWindowsCommandLine, `a\\\"b "c\\" d`, []string{`a\"b`, `c\`, "d"}},
+		{splitWindowsCommandLine, `x "" y`, []string{"x", "", "y"}},
+	}
+	for _, test := range tests {
+		got, err := test.split(test.line)
+		if err != nil {
+			t.Errorf("split(%q): %v", test.line, err)
+			continue
+		}
+		if strings.Join(got, "\x00") != strings.Join(test.want, "\x00") || len(got) != len(test.want) {
+			t.Errorf("split(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+
+	for _, line := range []string{`creds "open`, `creds 'open`, `creds \`} {
+		if _, err := splitPOSIXCommandLine(line); err == nil {
+			t.Errorf("splitPOSIXCommandLine(%q) succeeded, want an error", line)
+		}
+	}
+	if _, err := splitWindowsCommandLine(`creds "open`); err == nil {
+		t.Errorf("splitWindowsCommandLine with an open quote succeeded, want an error")
+	}
+}
+
+// commandRecorder records the command it runs and prints output
+type commandRecorder struct {
+	command Command
+	output  string
+}
+
+func (r *commandRecorder) Run(ctx context.Context, command Command) error {
+	r.command = command
+	io.WriteString(command.Stdout, r.output)
+	return nil
+}
+
+func TestProcessCredentialsRunsWithoutShell(t *testing.T) {
+	writeAWSFiles(t, `[profile proc]
+credential_process = /opt/bin/creds --account "prod admin" $HOME
+`, "")
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	recorder := &commandRecorder{output: `{"Version":1,"AccessKeyId":"AKIDPROC","SecretAccessKey":"s"}`}
+	app.SetExecutor(recorder)
+
+	creds, err := resolveProfile(t, app, "proc")
+	if err != nil {
+		t.Fatalf("ResolveCredentials(proc): %v", err)
+	}
+	if creds.AccessKeyID != "AKIDPROC" {
+		t.Errorf("credentials = %+v", creds)
+	}
+	// No shell expands $HOME, and the quoted words stay one argument
+	want := []string{"--account", "prod admin", "$HOME"}
+	if recorder.command.Name != "/opt/bin/creds" || strings.Join(recorder.command.Args, "|") != strings.Join(want, "|") {
+		t.Errorf("ran %s %q, want /opt/bin/creds %q", recorder.command.Name, recorder.command.Args, want)
+	}
+}
@@ -0,0 +1,331 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultBackupKeep is how many kubeconfig backups are retained by default
+const defaultBackupKeep = 20
+
+// backupTimeFormat names backups so they sort chronologically
+const backupTimeFormat = "20060102T150405.000Z"
+
+// backupSourceSuffix names the file next to a backup that records which
+// kubeconfig it was taken from
+const backupSourceSuffix = ".source"
+
+// BackupConfig controls the kubeconfig snapshots taken before modifications
+type BackupConfig struct {
+	// Dir overrides the backup directory
+	Dir string `yaml:"dir,omitempty"`
+	// Keep is how many backups to retain (default 20)
+	Keep int `yaml:"keep,omitempty"`
+}
+
+// KubeconfigBackup is a snapshot of a kubeconfig
+type KubeconfigBackup struct {
+	Path    string
+	Created time.Time
+	Size    int64
+	// Source is the kubeconfig the backup was taken from; empty for backups
+	// taken before sources were recorded
+	Source string
+}
+
+// backupDir returns the directory kubeconfig backups are stored in
+func (app *EKSLoginApp) backupDir() (string, error) {
+	if app.settings.Backups.Dir != "" {
+		return app.settings.Backups.Dir, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate backup directory: %w", err)
+	}
+	return filepath.Join(dir, "eks-login", "backups"), nil
+}
+
+// backupKeep returns how many backups to retain
+func (app *EKSLoginApp) backupKeep() int {
+	if app.settings.Backups.Keep > 0 {
+		return app.settings.Backups.Keep
+	}
+	return defaultBackupKeep
+}
+
+// BackupKubeconfig snapshots the kubeconfig before the first modification of
+// it in this run, and records the run for 'eks-login undo' when it exits. Runs
+// writing several kubeconfigs (split files, login-all) back up each of them.
+func (app *EKSLoginApp) BackupKubeconfig() {
+	path := KubeconfigPath()
+	if !app.lifecycle.markBackedUp(path) {
+		return
+	}
+	backup, err := app.snapshotFile(path)
+	if err != nil {
+		yellow.Printf("⚠️  Unable to back up kubeconfig: %v\n", err)
+		return
+	}
+	app.AddCleanup(func() { app.recordUndo(path, backup) })
+}
+
+// SnapshotKubeconfig copies the kubeconfig to a timestamped file in the backup
+// directory and prunes old backups that undo no longer needs. It returns ""
+// when there is no kubeconfig yet.
+func (app *EKSLoginApp) SnapshotKubeconfig() (string, error) {
+	return app.snapshotFile(KubeconfigPath())
+}
+
+// snapshotFile is SnapshotKubeconfig for the kubeconfig at path. The path is
+// recorded next to the backup, so a restore goes back to the same file.
+func (app *EKSLoginApp) snapshotFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	dir, err := app.backupDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	source, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve kubeconfig path: %w", err)
+	}
+
+	// Runs backing up several kubeconfigs can do so within a millisecond
+	var backup string
+	var file *os.File
+	for created := time.Now().UTC(); ; created = created.Add(time.Millisecond) {
+		backup = filepath.Join(dir, "kubeconfig-"+created.Format(backupTimeFormat))
+		file, err = os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.WriteFile(backup+backupSourceSuffix, []byte(source+"\n"), 0o600)
+	}
+	if err != nil {
+		os.Remove(backup)
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	app.pruneBackups()
+	return backup, nil
+}
+
+// pruneBackups removes the backups beyond the number to keep, except those
+// the undo journal still needs
+func (app *EKSLoginApp) pruneBackups() {
+	backups, err := app.ListBackups()
+	if err != nil {
+		return
+	}
+	records, err := app.UndoRecords()
+	if err != nil {
+		// Without the journal there is no telling which backups undo needs
+		return
+	}
+	referenced := make(map[string]bool, len(records))
+	for _, record := range records {
+		if record.Backup != "" {
+			referenced[filepath.Clean(record.Backup)] = true
+		}
+	}
+	for _, old := range backups[min(len(backups), app.backupKeep()):] {
+		if !referenced[filepath.Clean(old.Path)] {
+			os.Remove(old.Path)
+			os.Remove(old.Path + backupSourceSuffix)
+		}
+	}
+}
+
+// ListBackups returns the kubeconfig backups, newest first
+func (app *EKSLoginApp) ListBackups() ([]KubeconfigBackup, error) {
+	dir, err := app.backupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []KubeconfigBackup
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), "kubeconfig-")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		created, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		backups = append(backups, KubeconfigBackup{
+			Path:    path,
+			Created: created,
+			Size:    info.Size(),
+			Source:  backupSource(path),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups, nil
+}
+
+// backupSource returns the kubeconfig a backup was taken from, or "" if it
+// was not recorded
+func backupSource(backup string) string {
+	data, err := os.ReadFile(backup + backupSourceSuffix)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// printBackups lists the backups with their selection numbers
+func printBackups(backups []KubeconfigBackup) {
+	for i, item := range backupItems(backups) {
+		fmt.Printf("  %d. %s\n", i+1, item)
+	}
+}
+
+// backupItems describes each backup for listing and selection
+func backupItems(backups []KubeconfigBackup) []string {
+	items := make([]string, len(backups))
+	for i, backup := range backups {
+		items[i] = fmt.Sprintf("%s  (%s, %d bytes)",
+			backup.Created.Local().Format("2006-01-02 15:04:05"), filepath.Base(backup.Path), backup.Size)
+		if backup.Source != "" {
+			items[i] += "  of " + backup.Source
+		}
+	}
+	return items
+}
+
+// RestoreKubeconfig puts a backup back in place of the kubeconfig it was taken
+// from (the current kubeconfig for backups that do not record it). That file
+// is snapshotted first so the restore itself can be undone.
+func (app *EKSLoginApp) RestoreKubeconfig(backup string) error {
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if _, err := LoadKubeconfigData(data); err != nil {
+		return fmt.Errorf("backup %s is not a valid kubeconfig: %w", backup, err)
+	}
+
+	path := backupSource(backup)
+	if path == "" {
+		path = KubeconfigPath()
+	}
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	current, err := app.snapshotFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeKubeconfigFile(path, data); err != nil {
+		return err
+	}
+	app.recordUndo(path, current)
+
+	green.Printf("✓ Restored %s from %s\n", path, filepath.Base(backup))
+	if current != "" {
+		cyan.Printf("   Previous kubeconfig saved as %s\n", filepath.Base(current))
+	}
+	return nil
+}
+
+// newRestoreCmd creates the restore subcommand
+func newRestoreCmd(app *EKSLoginApp) *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "restore [backup]",
+		Short: "Restore kubeconfig from a backup taken before eks-login modified it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backups, err := app.ListBackups()
+			if err != nil {
+				return err
+			}
+
+			if list {
+				if len(backups) == 0 {
+					yellow.Println("No kubeconfig backups found")
+					return nil
+				}
+				printBackups(backups)
+				return nil
+			}
+
+			if len(args) == 1 {
+				backup := args[0]
+				if !strings.ContainsRune(backup, filepath.Separator) {
+					dir, err := app.backupDir()
+					if err != nil {
+						return err
+					}
+					backup = filepath.Join(dir, backup)
+				}
+				return app.RestoreKubeconfig(backup)
+			}
+
+			if len(backups) == 0 {
+				return fmt.Errorf("no kubeconfig backups found")
+			}
+
+			cyan.Println("\n💾 Kubeconfig backups:")
+			choice, err := app.Select("backup", backupItems(backups), -1)
+			if err != nil {
+				return err
+			}
+			return app.RestoreKubeconfig(backups[choice].Path)
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List available backups")
+	return cmd
+}
@@ -0,0 +1,183 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CacheClearOptions selects what 'eks-login cache clear' removes
+type CacheClearOptions struct {
+	// Profile and Regions limit clearing to the entries of a profile and
+	// regions; empty clears everything
+	Profile string
+	Regions []string
+	// Tokens also removes the cached SSO tokens, logging those profiles out,
+	// and the AWS CLI's cached role credentials
+	Tokens bool
+}
+
+// all reports whether no profile or region limits the clearing
+func (opts CacheClearOptions) all() bool {
+	return opts.Profile == "" && len(opts.Regions) == 0
+}
+
+// matches reports whether a "profile/region[/cluster]" state key is selected
+func (opts CacheClearOptions) matches(key string) bool {
+	parts := strings.SplitN(key, "/", 3)
+	if opts.Profile != "" && parts[0] != opts.Profile {
+		return false
+	}
+	if len(opts.Regions) == 0 {
+		return true
+	}
+	for _, region := range opts.Regions {
+		if len(parts) > 1 && parts[1] == region {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearCache forgets the remembered clusters and the rest of the state file,
+// selectively by profile and region, and with opts.Tokens the cached SSO
+// tokens and AWS CLI credentials
+func (app *EKSLoginApp) ClearCache(opts CacheClearOptions) error {
+	var clusters, used int
+	tokenFiles := map[string]string{}
+	err := app.UpdateState(func(state *State) {
+		for key := range state.LastClusters {
+			if opts.matches(key) {
+				delete(state.LastClusters, key)
+				clusters++
+			}
+		}
+		for key := range state.Used {
+			if opts.matches(key) {
+				delete(state.Used, key)
+				used++
+			}
+		}
+		// SSO sessions belong to profiles, not regions
+		if len(opts.Regions) == 0 {
+			for profile, path := range state.SSOTokens {
+				if opts.Profile == "" || profile == opts.Profile {
+					tokenFiles[profile] = path
+					delete(state.SSOTokens, profile)
+				}
+			}
+		}
+		if opts.all() {
+			state.AWSCLI = nil
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear state: %w", err)
+	}
+	green.Printf("✓ Forgot %d last cluster selection(s) and %d cluster usage record(s)\n", clusters, used)
+
+	if opts.all() {
+		if stamp, err := updateCheckStamp(); err == nil {
+			if err := os.Remove(stamp); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove update check stamp: %w", err)
+			}
+		}
+	}
+
+	if !opts.Tokens {
+		return nil
+	}
+	if len(opts.Regions) > 0 && opts.Profile == "" {
+		yellow.Println("⚠️  Tokens are cached per profile, not per region: pass --profile to remove them")
+		return nil
+	}
+	if opts.Profile != "" && tokenFiles[opts.Profile] == "" {
+		if path, err := app.SSOTokenPath(); err == nil {
+			tokenFiles[opts.Profile] = path
+		}
+	}
+	return clearTokenCaches(tokenFiles, opts.all())
+}
+
+// clearTokenCaches removes the SSO token files of profiles and, with cli,
+// the AWS CLI's cache of assumed role and SSO role credentials
+func clearTokenCaches(tokenFiles map[string]string, cli bool) error {
+	profiles := make([]string, 0, len(tokenFiles))
+	for profile := range tokenFiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	removed := map[string]bool{}
+	for _, profile := range profiles {
+		path := tokenFiles[profile]
+		if removed[path] {
+			continue
+		}
+		removed[path] = true
+		if err := os.Remove(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to remove SSO token of profile %s: %w", profile, err)
+		}
+		green.Printf("✓ Removed the SSO token of profile %s (%s)\n", profile, path)
+	}
+
+	if !cli {
+		return nil
+	}
+	dir, err := cliCacheDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove AWS CLI credential cache: %w", err)
+	}
+	green.Printf("✓ Removed the AWS CLI credential cache (%s)\n", dir)
+	return nil
+}
+
+// newCacheCmd creates the cache subcommand and its clear child
+func newCacheCmd(app *EKSLoginApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage what eks-login remembers between runs",
+	}
+
+	var tokens bool
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Forget remembered clusters and, with --tokens, cached credentials",
+		Long: `Forget the clusters remembered per profile and region, when each cluster was
+last used, and the other state eks-login keeps in its cache directory: a clean
+slate after accounts, clusters or profiles were reorganized.
+
+--profile and --region limit clearing to those entries. --tokens also removes
+the cached SSO tokens of the profiles, which logs them out, and, when clearing
+everything, the AWS CLI's cached role credentials.`,
+		Example: `  eks-login cache clear
+  eks-login cache clear --profile prod
+  eks-login cache clear --region eu-west-1,eu-central-1
+  eks-login cache clear --profile prod --tokens`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.ClearCache(CacheClearOptions{
+				Profile: app.config.Profile,
+				Regions: app.regions(),
+				Tokens:  tokens,
+			})
+		},
+	}
+	clearCmd.Flags().BoolVar(&tokens, "tokens", false, "Also remove cached SSO tokens (logging out) and AWS CLI credentials")
+
+	cmd.AddCommand(clearCmd)
+	return cmd
+}
@@ -0,0 +1,79 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/fatih/color"
+)
+
+// ciEnvVars are set by common CI systems
+var ciEnvVars = []string{
+	"CI",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"BUILDKITE",
+	"CIRCLECI",
+	"JENKINS_URL",
+	"TF_BUILD",
+	"CODEBUILD_BUILD_ID",
+}
+
+// DetectCI reports whether eks-login runs in a CI environment. Piped input is
+// not CI: the prompts fall back to a numbered list that reads it.
+func DetectCI() bool {
+	for _, name := range ciEnvVars {
+		if value := os.Getenv(name); value != "" && value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableCIMode disables prompts and color
+func (app *EKSLoginApp) EnableCIMode() {
+	app.config.CI = true
+	app.config.Interactive = false
+	color.NoColor = true
+}
+
+// requireExplicitTarget fails when CI mode would otherwise have to prompt for the target
+func (app *EKSLoginApp) requireExplicitTarget() error {
+	if !app.config.CI {
+		return nil
+	}
+	// --everywhere infers the profile from the cluster
+	if app.config.Profile == "" && !app.config.Everywhere && app.config.WebIdentityRole == "" {
+		return usageError("--profile is required in CI mode")
+	}
+	if app.config.Cluster == "" {
+		return usageError("--cluster is required in CI mode")
+	}
+	return nil
+}
+
+// Failure is the structured error written to stderr in CI mode
+type Failure struct {
+	Status  string `json:"status"`
+	Error   string `json:"error"`
+	Reason  string `json:"reason"`
+	Code    int    `json:"code"`
+	Command string `json:"command"`
+	Profile string `json:"profile,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// PrintFailure writes err and its exit code as a JSON object on stderr
+func (app *EKSLoginApp) PrintFailure(command string, err error, code int, reason string) {
+	json.NewEncoder(os.Stderr).Encode(Failure{
+		Status:  "error",
+		Error:   err.Error(),
+		Reason:  reason,
+		Code:    code,
+		Command: command,
+		Profile: app.config.Profile,
+		Region:  app.config.Region,
+		Cluster: app.config.Cluster,
+	})
+}
@@ -0,0 +1,192 @@
+package ekslogin
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cliCacheExpiryWindow is how long before their expiry botocore stops using
+// cached credentials
+const cliCacheExpiryWindow = 15 * time.Minute
+
+// cliCacheEntry is a credentials file of the AWS CLI and botocore cache
+type cliCacheEntry struct {
+	ProviderType string         `json:"ProviderType,omitempty"`
+	Credentials  AWSCredentials `json:"Credentials"`
+}
+
+// cliCacheDir returns the directory the AWS CLI and botocore cache assumed
+// role and SSO role credentials in
+func cliCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".aws", "cli", "cache"), nil
+}
+
+// botocoreCacheKey returns the cache file name botocore derives from the
+// parameters of a credentials call: the SHA-1 of their JSON as Python's
+// json.dumps writes it with sorted keys, compact for SSO and spaced otherwise
+func botocoreCacheKey(args map[string]interface{}, compact bool) string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	comma, colon := ", ", ": "
+	if compact {
+		comma, colon = ",", ":"
+	}
+	var b strings.Builder
+	b.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(comma)
+		}
+		b.WriteString(pythonJSON(key) + colon + pythonJSON(args[key]))
+	}
+	b.WriteString("}")
+
+	sum := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// pythonJSON encodes value like Python's json module: no HTML escaping, and
+// non-ASCII characters as \u escapes
+func pythonJSON(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+
+	var b strings.Builder
+	for _, r := range strings.TrimSuffix(buf.String(), "\n") {
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case r > 0xffff:
+			r -= 0x10000
+			fmt.Fprintf(&b, `\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.String()
+}
+
+// assumeRoleCacheKey returns botocore's cache key of an AssumeRole call, or
+// "" if it cannot be cached. Like botocore, the key leaves out the session
+// name, so profiles with role_session_name share the AWS CLI's cache files.
+func assumeRoleCacheKey(input stsAssumeRoleInput) string {
+	args := map[string]interface{}{"RoleArn": input.RoleARN}
+	if input.ExternalID != "" {
+		args["ExternalId"] = input.ExternalID
+	}
+	if input.Duration != "" {
+		seconds, err := strconv.Atoi(input.Duration)
+		if err != nil {
+			return ""
+		}
+		args["DurationSeconds"] = seconds
+	}
+	return botocoreCacheKey(args, false)
+}
+
+// ssoRoleCacheKey returns botocore's cache key of the SSO role credentials of
+// a profile
+func ssoRoleCacheKey(settings map[string]string) string {
+	args := map[string]interface{}{
+		"accountId": settings["sso_account_id"],
+		"roleName":  settings["sso_role_name"],
+	}
+	if session := settings["sso_session"]; session != "" {
+		args["sessionName"] = session
+	} else {
+		args["startUrl"] = settings["sso_start_url"]
+	}
+	return botocoreCacheKey(args, true)
+}
+
+// readCLICache returns the credentials cached under key, unless they expire
+// within botocore's expiry window
+func readCLICache(key string) *AWSCredentials {
+	dir, err := cliCacheDir()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	var entry cliCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Credentials.AccessKeyID == "" {
+		return nil
+	}
+	expiry, err := parseSSOTime(entry.Credentials.Expiration)
+	if err != nil || time.Until(expiry) < cliCacheExpiryWindow {
+		return nil
+	}
+	return &entry.Credentials
+}
+
+// writeCLICache caches credentials under key for the AWS CLI, botocore and
+// later runs
+func writeCLICache(key, providerType string, creds *AWSCredentials) error {
+	dir, err := cliCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cliCacheEntry{ProviderType: providerType, Credentials: *creds})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, key+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, key+".json"))
+}
+
+// cachedCredentials returns the credentials cached under key in the AWS CLI's
+// credential cache, or fetches and caches them, so that eks-login, the AWS CLI
+// and boto scripts share assumed role and SSO role sessions. Calls to
+// overridden endpoints are not cached, and caching is best effort: the
+// credentials are returned even if they cannot be written.
+func (app *EKSLoginApp) cachedCredentials(key, providerType string, fetch func() (*AWSCredentials, error)) (*AWSCredentials, error) {
+	if overrides, err := app.endpointOverrides(); err != nil || len(overrides) > 0 || key == "" {
+		return fetch()
+	}
+	if creds := readCLICache(key); creds != nil {
+		return creds, nil
+	}
+	creds, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseSSOTime(creds.Expiration); err == nil {
+		writeCLICache(key, providerType, creds)
+	}
+	return creds, nil
+}
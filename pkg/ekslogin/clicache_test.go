@@ -0,0 +1,39 @@
+package ekslogin
+
+import "testing"
+
+// The expected keys were produced by botocore's _create_cache_key algorithms
+// (json.dumps with sorted keys, then SHA-1) for the same inputs
+func TestAssumeRoleCacheKey(t *testing.T) {
+	tests := []struct {
+		input stsAssumeRoleInput
+		want  string
+	}{
+		{stsAssumeRoleInput{RoleARN: "arn:aws:iam::123456789012:role/deploy"}, "6a9d9ba39800a1773d9a3bd50c4a0db301ff5832"},
+		// botocore drops RoleSessionName before hashing
+		{stsAssumeRoleInput{RoleARN: "arn:aws:iam::123456789012:role/deploy", SessionName: "ci"}, "6a9d9ba39800a1773d9a3bd50c4a0db301ff5832"},
+		{stsAssumeRoleInput{RoleARN: "arn:aws:iam::123456789012:role/deploy", ExternalID: "ext-1", Duration: "3600"}, "14419d5915feda6deb78d66a5c77405ab9d15489"},
+		{stsAssumeRoleInput{RoleARN: "arn:aws:iam::123456789012:role/déploy"}, "d25052f148aedff0aad16a4cdb4c24323e2f55a2"},
+		{stsAssumeRoleInput{RoleARN: "arn:aws:iam::123456789012:role/deploy", Duration: "1h"}, ""},
+	}
+	for _, test := range tests {
+		if got := assumeRoleCacheKey(test.input); got != test.want {
+			t.Errorf("assumeRoleCacheKey(%+v) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestSSORoleCacheKey(t *testing.T) {
+	tests := []struct {
+		settings map[string]string
+		want     string
+	}{
+		{map[string]string{"sso_session": "corp", "sso_account_id": "123456789012", "sso_role_name": "Developer"}, "5ff7ef65433a1f661637cbf499bb9f479b3d3f64"},
+		{map[string]string{"sso_start_url": "https://corp.awsapps.com/start", "sso_account_id": "123456789012", "sso_role_name": "Developer"}, "fbaf96944ed0cb32ff9bf6052be415d1c9af1033"},
+	}
+	for _, test := range tests {
+		if got := ssoRoleCacheKey(test.settings); got != test.want {
+			t.Errorf("ssoRoleCacheKey(%v) = %q, want %q", test.settings, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ClusterDetails is the subset of eks describe-cluster output used by eks-login
+type ClusterDetails struct {
+	Name                 string            `json:"name"`
+	Arn                  string            `json:"arn"`
+	CreatedAt            string            `json:"createdAt"`
+	Version              string            `json:"version"`
+	Endpoint             string            `json:"endpoint"`
+	RoleArn              string            `json:"roleArn"`
+	Status               string            `json:"status"`
+	PlatformVersion      string            `json:"platformVersion"`
+	Tags                 map[string]string `json:"tags"`
+	CertificateAuthority struct {
+		Data string `json:"data"`
+	} `json:"certificateAuthority"`
+	ResourcesVpcConfig struct {
+		VpcID                  string   `json:"vpcId"`
+		SubnetIDs              []string `json:"subnetIds"`
+		SecurityGroupIDs       []string `json:"securityGroupIds"`
+		ClusterSecurityGroupID string   `json:"clusterSecurityGroupId"`
+		EndpointPublicAccess   bool     `json:"endpointPublicAccess"`
+		EndpointPrivateAccess  bool     `json:"endpointPrivateAccess"`
+		PublicAccessCidrs      []string `json:"publicAccessCidrs"`
+	} `json:"resourcesVpcConfig"`
+	Logging struct {
+		ClusterLogging []struct {
+			Types   []string `json:"types"`
+			Enabled bool     `json:"enabled"`
+		} `json:"clusterLogging"`
+	} `json:"logging"`
+	Identity struct {
+		OIDC struct {
+			Issuer string `json:"issuer"`
+		} `json:"oidc"`
+	} `json:"identity"`
+	AccessConfig struct {
+		AuthenticationMode string `json:"authenticationMode"`
+	} `json:"accessConfig"`
+}
+
+// DescribeClusterResponse represents the response from eks describe-cluster
+type DescribeClusterResponse struct {
+	Cluster ClusterDetails `json:"cluster"`
+}
+
+// DescribeCluster retrieves the details of the selected cluster
+func (app *EKSLoginApp) DescribeCluster() (*ClusterDetails, error) {
+	output, err := app.AWS("eks", "describe-cluster",
+		"--name", app.config.Cluster,
+		"--region", app.config.Region,
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster %s: %w", app.config.Cluster, err)
+	}
+
+	var response DescribeClusterResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster details: %w", err)
+	}
+
+	return &response.Cluster, nil
+}
@@ -0,0 +1,163 @@
+package ekslogin
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewRootCommand builds the eks-login command tree around app
+func NewRootCommand(app *EKSLoginApp) *cobra.Command {
+	var rootCmd = &cobra.Command{
+		Use:   "eks-login [alias | [profile/]cluster]",
+		Short: "🚀 EKS Login Helper - Streamline your AWS EKS authentication",
+		Long: `EKS Login Helper automates the process of logging into AWS SSO,
+listing available EKS clusters, and updating your kubeconfig.
+
+Examples:
+  eks-login                           # Interactive mode
+  eks-login --profile my-profile      # Use specific profile
+  eks-login --profile my-profile --region us-east-1 --cluster my-cluster
+  eks-login my-cluster                # Profile and region from your past logins
+  eks-login my-profile/my-cluster
+  eks-login prod-api                  # Alias from the config file`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: app.completeTargets,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			app.ctx = cmd.Context()
+			app.config.RegionSet = cmd.Flags().Changed("region")
+			if regions := app.regions(); len(regions) > 0 {
+				app.config.Region = regions[0]
+			}
+			if app.config.CI || (!cmd.Flags().Changed("ci") && DetectCI()) {
+				app.EnableCIMode()
+			}
+			return app.LoadSettings()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if err := app.ApplyTargetArg(cmd, args[0]); err != nil {
+					return err
+				}
+			}
+			return app.Run()
+		},
+	}
+
+	// Flags
+	rootCmd.PersistentFlags().StringVar(&app.config.ConfigFile, "config", DefaultConfigPath(), "Path to the eks-login config file")
+	rootCmd.PersistentFlags().BoolVar(&app.config.NoProjectConfig, "no-project-config", false, "Ignore .eks-login.yaml files in the working directory and its parents")
+	rootCmd.PersistentFlags().StringVarP(&app.config.Profile, "profile", "p", "", "AWS profile to use")
+	rootCmd.PersistentFlags().StringVar(&app.config.ProfileFilter, "profile-filter", "", "Only offer profiles matching these comma-separated globs, e.g. 'company-prod-*'")
+	rootCmd.PersistentFlags().StringSliceVarP(&app.config.Regions, "region", "r", []string{app.config.DefaultRegion}, "AWS region; repeat or comma-separate to discover clusters in several regions")
+	rootCmd.PersistentFlags().BoolVar(&app.config.FIPS, "fips", false, "Use FIPS endpoints for all AWS calls")
+	rootCmd.PersistentFlags().StringArrayVar(&app.config.EndpointURLs, "endpoint-url", nil, "Override AWS endpoints: URL for all services or service=URL (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&app.config.CABundle, "ca-bundle", "", "CA bundle to trust for AWS and cluster connections (e.g. a corporate proxy CA)")
+	rootCmd.PersistentFlags().IntVar(&app.config.MaxAttempts, "max-attempts", 0, "Maximum attempts for throttled AWS calls (default 5)")
+	rootCmd.PersistentFlags().IntVar(&app.config.Concurrency, "concurrency", 0, "Maximum simultaneous AWS calls when scanning several regions, profiles or accounts (default 8)")
+	rootCmd.PersistentFlags().DurationVar(&app.config.Timeout, "timeout", 0, "Timeout for each AWS/kubectl operation (default 2m)")
+	rootCmd.PersistentFlags().DurationVar(&app.config.LoginTimeout, "login-timeout", 0, "Timeout for the interactive SSO login (default 10m)")
+	rootCmd.PersistentFlags().BoolVar(&app.config.CI, "ci", false, "CI mode: no prompts or color, explicit target, JSON errors on stderr (auto-detected)")
+	rootCmd.PersistentFlags().BoolVar(&app.config.Accessible, "accessible", false, "Screen reader friendly output: no spinners, menus or decorative symbols (or EKS_LOGIN_ACCESSIBLE=1)")
+	rootCmd.PersistentFlags().StringVarP(&app.config.Cluster, "cluster", "c", "", "EKS cluster name")
+	rootCmd.Flags().StringVarP(&app.config.Namespace, "namespace", "n", "", "Default namespace for the kubeconfig context")
+	rootCmd.Flags().BoolVar(&app.config.SelectNamespace, "select-namespace", false, "Pick the context's default namespace interactively after login")
+	rootCmd.Flags().BoolVar(&app.config.SkipSSO, "skip-sso", false, "Skip SSO login (assume already logged in)")
+	rootCmd.Flags().BoolVar(&app.config.ECR, "ecr", false, "Also log docker in to the account's ECR registry")
+	rootCmd.Flags().BoolVar(&app.config.LaunchK9s, "k9s", false, "Launch k9s (or the configured launch command) after login")
+	rootCmd.Flags().BoolVar(&app.config.VerifyWithKubectl, "verify-with-kubectl", false, "Verify the connection with kubectl cluster-info instead of the API directly")
+	rootCmd.Flags().BoolVar(&app.config.HealthCheck, "health", false, "Check node readiness and API latency after login and show a one-line health summary")
+	rootCmd.Flags().BoolVar(&app.config.RBACCheck, "rbac-check", false, "Summarize your RBAC permissions after login")
+	rootCmd.Flags().StringVar(&app.config.Sort, "sort", "", "Order the cluster list by name, version, status or recent")
+	rootCmd.Flags().BoolVar(&app.config.SelectRole, "select-role", false, "Pick which of your SSO roles (permission sets) in the profile's account to log in with")
+	rootCmd.Flags().StringVar(&app.config.WebIdentityRole, "web-identity-role", "", "In CI, assume this role with the job's OIDC token (GitHub Actions, GitLab CI) instead of using a profile")
+	rootCmd.Flags().StringVar(&app.config.WebIdentityTokenFile, "web-identity-token-file", "", "File holding the OIDC token for --web-identity-role (default AWS_WEB_IDENTITY_TOKEN_FILE, or fetched from the CI system)")
+	rootCmd.Flags().StringVar(&app.config.SSORole, "sso-role", "", "Log in with this SSO role (permission set) of the profile's account instead of the profile's own")
+	rootCmd.Flags().BoolVar(&app.config.SplitKubeconfig, "split-kubeconfig", false, "Write the cluster to its own file in ~/.kube/configs instead of merging it into the kubeconfig")
+	rootCmd.Flags().StringVar(&app.config.UserAlias, "user-alias", "", "Name of the kubeconfig user entry; may use {cluster}, {profile}, {region}, {account}, {role} and {arn} (default the cluster ARN)")
+	rootCmd.Flags().StringVar(&app.config.ProxyURL, "proxy-url", "", "Proxy (http, https or socks5 URL) kubectl uses to reach the cluster, written into its kubeconfig entry")
+	rootCmd.Flags().StringVar(&app.config.STSRegion, "sts-region", "", "Sign EKS tokens with the regional STS endpoint of this region instead of the cluster's (e.g. the one closest to you)")
+	rootCmd.Flags().StringVar(&app.config.TokenExec, "token-exec", "", "Command the kubeconfig calls for tokens: aws (aws eks get-token) or eks-login (no AWS CLI needed)")
+	rootCmd.Flags().BoolVar(&app.config.Reuse, "reuse", false, "Use the cluster picked last time with this profile without prompting")
+	rootCmd.Flags().StringVar(&app.config.ConfirmCluster, "confirm-cluster", "", "Confirm a protected cluster non-interactively by passing its name")
+	rootCmd.Flags().BoolVar(&app.config.Force, "force", false, "Write the kubeconfig without asking, even over a context of another cluster or profile or after --diff")
+	rootCmd.Flags().BoolVar(&app.config.ReadOnly, "read-only", false, "Also create a read-only context impersonating the configured view-only identity and make it current")
+	addOrgFlags(rootCmd, &app.config.OrgRole)
+	rootCmd.Flags().DurationVar(&app.config.RoleDuration, "duration", 0, "Session duration of assumed roles (--org-role, --web-identity-role), 15m to 12h and up to the role's maximum (default 1h)")
+	rootCmd.Flags().BoolVar(&app.config.Everywhere, "everywhere", false, "Pick the cluster from all (--profile-filter matching) profiles and their regions, inferring its profile")
+	rootCmd.Flags().BoolVar(&app.config.Diff, "diff", false, "Show a diff of the kubeconfig changes and ask before writing them")
+	rootCmd.Flags().BoolVar(&app.config.DryRun, "dry-run", false, "Print the commands and kubeconfig entries a login would run and write, without doing it")
+	rootCmd.Flags().BoolVar(&app.config.Interactive, "interactive", true, "Enable interactive mode")
+
+	rootCmd.AddCommand(newVersionCmd(app))
+	rootCmd.AddCommand(newConfigCmd(app))
+	rootCmd.AddCommand(newConsoleCmd(app))
+	rootCmd.AddCommand(newDaemonCmd(app))
+	rootCmd.AddCommand(newECRCmd(app))
+	rootCmd.AddCommand(newDoctorCmd(app))
+	rootCmd.AddCommand(newExportCmd(app))
+	rootCmd.AddCommand(newExportCredsCmd(app))
+	rootCmd.AddCommand(newAddonsCmd(app))
+	rootCmd.AddCommand(newAliasCmd(app))
+	rootCmd.AddCommand(newAuditCmd(app))
+	rootCmd.AddCommand(newDescribeCmd(app))
+	rootCmd.AddCommand(newGrantAccessCmd(app))
+	rootCmd.AddCommand(newInventoryCmd(app))
+	rootCmd.AddCommand(newLoginAllCmd(app))
+	rootCmd.AddCommand(newLogoutCmd(app))
+	rootCmd.AddCommand(newNodegroupsCmd(app))
+	rootCmd.AddCommand(newOIDCCmd(app))
+	rootCmd.AddCommand(newPromptCmd(app))
+	rootCmd.AddCommand(newRefreshCmd(app))
+	rootCmd.AddCommand(newRefreshAllCmd(app))
+	rootCmd.AddCommand(newRestoreCmd(app))
+	rootCmd.AddCommand(newUndoCmd(app))
+	rootCmd.AddCommand(newSelfUpdateCmd(app))
+	rootCmd.AddCommand(newServeCmd(app))
+	rootCmd.AddCommand(newStatusCmd(app))
+	rootCmd.AddCommand(newTmuxStatusCmd(app))
+	rootCmd.AddCommand(newTokenCmd(app))
+	rootCmd.AddCommand(newTunnelCmd(app))
+	rootCmd.AddCommand(newKubeconfigPathCmd(app))
+	rootCmd.AddCommand(newSetupCmd(app))
+	rootCmd.AddCommand(newCacheCmd(app))
+	rootCmd.AddCommand(newUseCmd(app))
+	rootCmd.AddCommand(newValidateCmd(app))
+	addExternalCommands(rootCmd, app)
+
+	if IsKubectlPlugin() {
+		usePluginMode(rootCmd)
+	}
+
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError("%v (see '%s --help')", err, cmd.CommandPath())
+	})
+	return rootCmd
+}
+
+// Main runs the eks-login command line and returns its exit code
+func Main() int {
+	app := NewEKSLoginApp()
+	rootCmd := NewRootCommand(app)
+
+	done := make(chan struct{})
+	ctx := app.HandleSignals(done)
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	close(done)
+
+	if app.lifecycle.ExitCode() != 0 {
+		app.ExitInterrupted()
+	}
+	app.RunCleanups()
+
+	if err != nil {
+		code, reason := exitCode(err)
+		if app.config.CI {
+			app.PrintFailure(cmd.CommandPath(), err, code, reason)
+		} else {
+			red.Printf("Error: %v\n", err)
+		}
+		return code
+	}
+	return ExitOK
+}
@@ -0,0 +1,37 @@
+package ekslogin
+
+import "sync"
+
+// defaultConcurrency limits simultaneous AWS calls unless --concurrency or
+// default.concurrency is set
+const defaultConcurrency = 8
+
+// concurrency returns how many AWS calls scans of several regions, profiles
+// or accounts may make at once
+func (app *EKSLoginApp) concurrency() int {
+	switch {
+	case app.config.Concurrency > 0:
+		return app.config.Concurrency
+	case app.settings.Default.Concurrency > 0:
+		return app.settings.Default.Concurrency
+	default:
+		return defaultConcurrency
+	}
+}
+
+// forEach calls fn for 0..n-1, running at most app.concurrency() calls at once
+func (app *EKSLoginApp) forEach(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, app.concurrency())
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,400 @@
+package ekslogin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultsConfig holds the values used when the matching flag is not given
+type DefaultsConfig struct {
+	Profile   string `yaml:"profile,omitempty"`
+	Region    string `yaml:"region,omitempty"`
+	Cluster   string `yaml:"cluster,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+	// ProfileFilter restricts the profile picker, like --profile-filter
+	ProfileFilter string `yaml:"profile_filter,omitempty"`
+	// Sort orders the cluster list, like --sort
+	Sort string `yaml:"sort,omitempty"`
+	// TokenExec is the command kubeconfigs call for tokens, like --token-exec
+	TokenExec string `yaml:"token_exec,omitempty"`
+	// HealthCheck checks cluster health after every login, like --health
+	HealthCheck bool `yaml:"health_check,omitempty"`
+	// UserAlias names kubeconfig user entries, like --user-alias
+	UserAlias string `yaml:"user_alias,omitempty"`
+	// Concurrency limits simultaneous AWS calls in scans, like --concurrency
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// Accessible prints screen reader friendly output, like --accessible
+	Accessible bool `yaml:"accessible,omitempty"`
+	// RoleDuration is the session duration of assumed roles, like --duration
+	RoleDuration time.Duration `yaml:"role_duration,omitempty"`
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// yamlName returns the YAML key of a struct field, or "" if it is not serialized
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// settingType resolves a dotted key such as "default.profile" to the Go type it
+// is stored as. Map-valued settings take one extra segment for the map key.
+func settingType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(Settings{})
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		switch t.Kind() {
+		case reflect.Struct:
+			found := false
+			for j := 0; j < t.NumField(); j++ {
+				if yamlName(t.Field(j)) == part {
+					t = t.Field(j).Type
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown config key %q", strings.Join(parts[:i+1], "."))
+			}
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+
+	switch {
+	case t == durationType:
+		return t, nil
+	case t.Kind() == reflect.String, t.Kind() == reflect.Bool, t.Kind() == reflect.Int:
+		return t, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		return t, nil
+	default:
+		return nil, fmt.Errorf("%q is not a single value; edit the config file directly", key)
+	}
+}
+
+// valueNode validates value for t and converts it to a YAML node
+func valueNode(t reflect.Type, key, value string) (*yaml.Node, error) {
+	switch {
+	case t == durationType:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("%s must be a duration such as 30s or 2m: %w", key, err)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case t.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", key)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(parsed)}, nil
+	case t.Kind() == reflect.Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("%s must be a whole number", key)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}, nil
+	case t.Kind() == reflect.Slice:
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return list, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+}
+
+// loadConfigDocument reads the config file as a YAML node tree, preserving comments
+func loadConfigDocument(path string) (*yaml.Node, error) {
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s is not a YAML mapping", path)
+	}
+	return doc, nil
+}
+
+// saveConfigDocument validates the document against Settings and writes it
+func saveConfigDocument(path string, doc *yaml.Node) error {
+	setConfigVersion(doc.Content[0])
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var settings Settings
+	if err := yaml.Unmarshal(buf.Bytes(), &settings); err != nil {
+		return fmt.Errorf("refusing to write an invalid config: %w", err)
+	}
+	if issues := ValidateConfig(buf.Bytes()); len(issues) > 0 {
+		return fmt.Errorf("refusing to write an invalid config: %w", &ConfigError{Path: path, Issues: issues})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node of key in mapping, optionally creating it
+func mappingValue(mapping *yaml.Node, key string, create bool) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	if !create {
+		return nil
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// SetConfigValue validates and stores value under key in the config file
+func SetConfigValue(path, key, value string) error {
+	t, err := settingType(key)
+	if err != nil {
+		return err
+	}
+	node, err := valueNode(t, key, value)
+	if err != nil {
+		return err
+	}
+
+	doc, err := loadConfigDocument(path)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	mapping := doc.Content[0]
+	for _, part := range parts[:len(parts)-1] {
+		mapping = mappingValue(mapping, part, true)
+		if mapping.Kind != yaml.MappingNode {
+			return fmt.Errorf("cannot set %s: %s is not a mapping in the config file", key, part)
+		}
+	}
+
+	leaf := parts[len(parts)-1]
+	if existing := mappingValue(mapping, leaf, false); existing != nil {
+		*existing = *node
+	} else {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: leaf}, node)
+	}
+
+	return saveConfigDocument(path, doc)
+}
+
+// UnsetConfigValue removes key from the config file
+func UnsetConfigValue(path, key string) error {
+	if _, err := settingType(key); err != nil {
+		return err
+	}
+
+	doc, err := loadConfigDocument(path)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	mapping := doc.Content[0]
+	for _, part := range parts[:len(parts)-1] {
+		if mapping = mappingValue(mapping, part, false); mapping == nil || mapping.Kind != yaml.MappingNode {
+			return nil
+		}
+	}
+
+	leaf := parts[len(parts)-1]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == leaf {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return saveConfigDocument(path, doc)
+		}
+	}
+	return nil
+}
+
+// flattenSettings collects the non-empty single-value settings as dotted keys
+func flattenSettings(prefix string, value reflect.Value, out map[string]string) {
+	switch {
+	case value.Type() == durationType:
+		if value.Int() != 0 {
+			out[prefix] = time.Duration(value.Int()).String()
+		}
+	case value.Kind() == reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if name := yamlName(value.Type().Field(i)); name != "" {
+				flattenSettings(joinKey(prefix, name), value.Field(i), out)
+			}
+		}
+	case value.Kind() == reflect.Map:
+		for _, key := range value.MapKeys() {
+			flattenSettings(joinKey(prefix, key.String()), value.MapIndex(key), out)
+		}
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		if value.Len() > 0 {
+			items := make([]string, value.Len())
+			for i := range items {
+				items[i] = value.Index(i).String()
+			}
+			out[prefix] = strings.Join(items, ",")
+		}
+	case value.Kind() == reflect.Slice:
+		if value.Len() > 0 {
+			out[prefix] = fmt.Sprintf("(%d entries)", value.Len())
+		}
+	case !value.IsZero():
+		out[prefix] = fmt.Sprint(value.Interface())
+	}
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// newConfigCmd creates the config subcommand and its get/set/unset/list children
+func newConfigCmd(app *EKSLoginApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change settings in the eks-login config file",
+		Long: `Read and change settings in the eks-login config file without editing YAML.
+
+Keys are dotted paths such as default.profile, timeout or endpoints.eks. List
+values are given comma-separated. Values are validated before the file is written.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := settingType(args[0]); err != nil {
+				return err
+			}
+			values := make(map[string]string)
+			flattenSettings("", reflect.ValueOf(*app.settings), values)
+			if value, ok := values[args[0]]; ok {
+				fmt.Println(value)
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Validate and store a setting",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := SetConfigValue(app.config.ConfigFile, args[0], args[1]); err != nil {
+				return withExitCode(ExitUsage, "usage", err)
+			}
+			green.Printf("✓ %s = %s\n", args[0], args[1])
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := UnsetConfigValue(app.config.ConfigFile, args[0]); err != nil {
+				return withExitCode(ExitUsage, "usage", err)
+			}
+			green.Printf("✓ %s unset\n", args[0])
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the settings in the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values := make(map[string]string)
+			flattenSettings("", reflect.ValueOf(*app.settings), values)
+
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			cyan.Printf("# %s\n", app.config.ConfigFile)
+			for _, key := range keys {
+				fmt.Printf("%s=%s\n", key, values[key])
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Print the location of the config file",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(app.config.ConfigFile)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for unknown keys, invalid values and conflicts",
+		Long: `Check the config file, and the project config in effect, for unknown keys,
+values of the wrong type or outside the accepted choices, and settings that
+contradict each other. Every command does this when it loads the config; this
+one only reports the result.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Loading the settings before any command already validated them
+			green.Printf("✓ %s is valid\n", app.config.ConfigFile)
+			if app.settings.ProjectFile != "" {
+				green.Printf("✓ %s is valid\n", app.settings.ProjectFile)
+			}
+		},
+	})
+
+	return cmd
+}
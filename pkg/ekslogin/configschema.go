@@ -0,0 +1,358 @@
+package ekslogin
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// regionPattern matches AWS region names such as eu-west-1 or us-gov-west-1
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// ConfigIssue is one problem found in a config file
+type ConfigIssue struct {
+	Line    int
+	Key     string
+	Message string
+}
+
+func (i ConfigIssue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Key, i.Message)
+}
+
+// ConfigError lists every problem of a config file at once, so they can all
+// be fixed in one go
+type ConfigError struct {
+	Path   string
+	Issues []ConfigIssue
+}
+
+func (e *ConfigError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = "  " + issue.String()
+	}
+	return fmt.Sprintf("invalid config file %s:\n%s", e.Path, strings.Join(lines, "\n"))
+}
+
+// configSchema collects the issues of a config document while walking it
+// along the Settings type, remembering each key's node for later checks
+type configSchema struct {
+	issues []ConfigIssue
+	nodes  map[string]*yaml.Node
+	keys   []string
+}
+
+// ValidateConfig checks a config file against Settings: unknown keys, values
+// of the wrong type, invalid choices and settings that contradict each other.
+// Syntax errors are left to the YAML parser.
+func ValidateConfig(data []byte) []ConfigIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+
+	schema := &configSchema{nodes: map[string]*yaml.Node{}, keys: settingKeys("", reflect.TypeOf(Settings{}))}
+	schema.walk(doc.Content[0], reflect.TypeOf(Settings{}), "")
+	schema.checkValues()
+	schema.checkConflicts()
+
+	sort.SliceStable(schema.issues, func(i, j int) bool {
+		return schema.issues[i].Line < schema.issues[j].Line
+	})
+	return schema.issues
+}
+
+// settingKeys lists the dotted keys of the structs in t, for suggestions
+func settingKeys(prefix string, t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		name := yamlName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		key := joinKey(prefix, name)
+		keys = append(keys, key)
+		if field := t.Field(i).Type; field.Kind() == reflect.Struct && field != durationType {
+			keys = append(keys, settingKeys(key, field)...)
+		}
+	}
+	return keys
+}
+
+func (s *configSchema) add(node *yaml.Node, key, format string, args ...interface{}) {
+	s.issues = append(s.issues, ConfigIssue{Line: node.Line, Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// walk checks node against t, the type key is stored as
+func (s *configSchema) walk(node *yaml.Node, t reflect.Type, key string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+	s.nodes[key] = node
+
+	switch {
+	case t == durationType || t.Kind() == reflect.String || t.Kind() == reflect.Bool || t.Kind() == reflect.Int:
+		if err := node.Decode(reflect.New(t).Interface()); err != nil || node.Kind != yaml.ScalarNode {
+			s.add(node, key, "%s", typeHint(t))
+		}
+	case t.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			s.add(node, key, "must be a mapping of settings")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i], node.Content[i+1]
+			field, ok := fieldByYAMLName(t, name.Value)
+			if !ok {
+				s.add(name, joinKey(key, name.Value), "unknown key%s", s.suggest(joinKey(key, name.Value), t))
+				continue
+			}
+			s.walk(value, field.Type, joinKey(key, name.Value))
+		}
+	case t.Kind() == reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			s.add(node, key, "must be a mapping")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			s.walk(node.Content[i+1], t.Elem(), joinKey(key, node.Content[i].Value))
+		}
+	case t.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			s.add(node, key, "must be a list")
+			return
+		}
+		for i, item := range node.Content {
+			s.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", key, i))
+		}
+	}
+}
+
+// fieldByYAMLName finds the field of struct t stored under name
+func fieldByYAMLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// typeHint describes the values a setting of type t accepts
+func typeHint(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "must be a duration such as 30s or 2m"
+	case t.Kind() == reflect.Bool:
+		return "must be true or false"
+	case t.Kind() == reflect.Int:
+		return "must be a whole number"
+	default:
+		return "must be a single value"
+	}
+}
+
+// suggest offers the known key closest to an unknown one, comparing both the
+// siblings in struct t and every dotted key, so "defualt_region" finds
+// "default.region"
+func (s *configSchema) suggest(key string, t reflect.Type) string {
+	prefix, leaf := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		prefix, leaf = key[:i], key[i+1:]
+	}
+
+	best, distance := "", 3
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlName(t.Field(i)); name != "" {
+			if d := editDistance(leaf, name); d < distance {
+				best, distance = joinKey(prefix, name), d
+			}
+		}
+	}
+	flat := strings.ReplaceAll(key, ".", "_")
+	for _, candidate := range s.keys {
+		if d := editDistance(flat, strings.ReplaceAll(candidate, ".", "_")); d < distance {
+			best, distance = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// value returns the scalar stored under key and its node, if set
+func (s *configSchema) value(key string) (string, *yaml.Node) {
+	node := s.nodes[key]
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return "", nil
+	}
+	return node.Value, node
+}
+
+// matching lists the keys set under prefix whose final segment is field,
+// e.g. every presets.*.region
+func (s *configSchema) matching(prefix, field string) []string {
+	var keys []string
+	for key, node := range s.nodes {
+		if node.Kind == yaml.ScalarNode && strings.HasPrefix(key, prefix+".") && (field == "" || strings.HasSuffix(key, "."+field)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkChoice reports a value of key that is not one of choices
+func (s *configSchema) checkChoice(key string, choices []string) {
+	value, node := s.value(key)
+	if node == nil || value == "" {
+		return
+	}
+	for _, choice := range choices {
+		if value == choice {
+			return
+		}
+	}
+	s.add(node, key, "invalid value %q (use one of %s)", value, strings.Join(choices, ", "))
+}
+
+// checkURL reports a value of key that is not an absolute URL with one of schemes
+func (s *configSchema) checkURL(key string, schemes ...string) {
+	value, node := s.value(key)
+	if node == nil || value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	if err == nil && parsed.Host != "" {
+		for _, scheme := range schemes {
+			if parsed.Scheme == scheme {
+				return
+			}
+		}
+	}
+	s.add(node, key, "invalid URL %q (the scheme must be one of %s)", value, strings.Join(schemes, ", "))
+}
+
+// checkRange reports a number outside low..high (high 0 means unbounded)
+func (s *configSchema) checkRange(key string, low, high int) {
+	node := s.nodes[key]
+	var value int
+	if node == nil || node.Decode(&value) != nil {
+		return
+	}
+	if value < low || (high > 0 && value > high) {
+		if high > 0 {
+			s.add(node, key, "must be between %d and %d", low, high)
+		} else {
+			s.add(node, key, "must be %d or more", low)
+		}
+	}
+}
+
+// checkValues validates choices, URLs, regions and numbers
+func (s *configSchema) checkValues() {
+	s.checkChoice("default.sort", clusterSortModes)
+	s.checkChoice("default.token_exec", tokenExecModes)
+	s.checkChoice("tunnel.via", []string{tunnelViaSSM, tunnelViaSSH})
+	s.checkChoice("theme.preset", themePresetNames())
+	for _, role := range []string{"success", "warning", "error", "info", "highlight"} {
+		if value, node := s.value("theme." + role); node != nil && value != "" {
+			if _, err := parseThemeColor(value); err != nil {
+				s.add(node, "theme."+role, "%v", err)
+			}
+		}
+	}
+
+	s.checkURL("proxy.url", "http", "https", "socks5")
+	for _, key := range s.matching("proxy.clusters", "") {
+		s.checkURL(key, "http", "https", "socks5")
+	}
+	for _, key := range s.matching("endpoints", "") {
+		s.checkURL(key, "http", "https")
+	}
+	s.checkURL("protected.webhook", "http", "https")
+
+	regions := []string{"default.region", "sts.region"}
+	regions = append(regions, s.matching("presets", "region")...)
+	regions = append(regions, s.matching("aliases", "region")...)
+	for _, key := range regions {
+		if value, node := s.value(key); node != nil && value != "" && !regionPattern.MatchString(value) {
+			s.add(node, key, "invalid AWS region %q, e.g. eu-west-1", value)
+		}
+	}
+
+	if value, node := s.value("default.role_duration"); node != nil && value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			if err := checkRoleDuration(duration); err != nil {
+				s.add(node, "default.role_duration", "%v", err)
+			}
+		}
+	}
+
+	s.checkRange("default.concurrency", 0, 0)
+	s.checkRange("retry.max_attempts", 0, 0)
+	s.checkRange("backups.keep", 0, 0)
+	s.checkRange("tunnel.local_port", 0, 65535)
+	s.checkRange("tunnel.ssh.port", 0, 65535)
+	for _, key := range []string{"timeout", "retry.base_delay", "tmux.warn_before", "daemon.interval", "daemon.notify_before"} {
+		if value, node := s.value(key); node != nil && strings.HasPrefix(value, "-") {
+			s.add(node, key, "must not be negative")
+		}
+	}
+
+	for _, stage := range []string{"hooks.pre_login", "hooks.pre_kubeconfig"} {
+		for key, node := range s.nodes {
+			if strings.HasPrefix(key, stage+"[") && !strings.Contains(key[len(stage):], ".") && node.Kind == yaml.MappingNode {
+				if command, _ := s.value(key + ".command"); command == "" {
+					s.add(node, key, "hook has no command")
+				}
+			}
+		}
+	}
+}
+
+// checkConflicts reports settings that contradict each other
+func (s *configSchema) checkConflicts() {
+	if disabled, node := s.value("audit.disabled"); node != nil && disabled == "true" {
+		if path, pathNode := s.value("audit.path"); pathNode != nil && path != "" {
+			s.add(pathNode, "audit.path", "has no effect while audit.disabled is true; remove one of them")
+		}
+	}
+	if protected, node := s.value("read_only.protected"); node != nil && protected == "true" {
+		if as, _ := s.value("read_only.as"); as == "" {
+			s.add(node, "read_only.protected", "needs read_only.as, the user read-only contexts impersonate")
+		}
+	}
+}
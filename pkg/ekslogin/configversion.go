@@ -0,0 +1,149 @@
+package ekslogin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configMigrations upgrade a config document one version at a time: the entry
+// at index i turns a version i document into a version i+1 one, and reports
+// whether it changed anything. Changes to the layout of presets, aliases or any
+// other setting append a migration here instead of breaking the files users
+// already have.
+var configMigrations = []func(mapping *yaml.Node) (bool, error){
+	// 0 → 1: files without a version have the version 1 layout
+	func(*yaml.Node) (bool, error) { return false, nil },
+}
+
+// currentConfigVersion is the config file format this build reads and writes
+var currentConfigVersion = len(configMigrations)
+
+// configVersion returns the version of a config document's top-level mapping;
+// files written before versioning have none and are version 0
+func configVersion(mapping *yaml.Node) (int, error) {
+	node := mappingValue(mapping, "version", false)
+	if node == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("line %d: version: must be a whole number", node.Line)
+	}
+	return version, nil
+}
+
+// setConfigVersion records the current version first in the mapping
+func setConfigVersion(mapping *yaml.Node) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(currentConfigVersion)}
+	if existing := mappingValue(mapping, "version", false); existing != nil {
+		*existing = *value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	mapping.Content = append([]*yaml.Node{key, value}, mapping.Content...)
+}
+
+// migrateConfig upgrades config data to the current version in memory. It
+// returns the data unchanged when it is current, when no migration changes it
+// (only the version would differ), or when it is empty or not valid YAML (left
+// for the parser to report), and the version the data had.
+func migrateConfig(path string, data []byte) ([]byte, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, currentConfigVersion, nil
+	}
+	mapping := doc.Content[0]
+
+	version, err := configVersion(mapping)
+	if err != nil {
+		return nil, 0, withExitCode(ExitUsage, "invalid_config", fmt.Errorf("invalid config file %s: %w", path, err))
+	}
+	if version > currentConfigVersion {
+		return nil, version, withExitCode(ExitUsage, "invalid_config", fmt.Errorf(
+			"config file %s has version %d, newer than the version %d this eks-login reads; upgrade eks-login",
+			path, version, currentConfigVersion))
+	}
+	if version == currentConfigVersion {
+		return data, version, nil
+	}
+
+	changed := false
+	for v := version; v < currentConfigVersion; v++ {
+		migrated, err := configMigrations[v](mapping)
+		if err != nil {
+			return nil, version, fmt.Errorf("failed to migrate config file %s from version %d: %w", path, v, err)
+		}
+		changed = changed || migrated
+	}
+	if !changed {
+		return data, version, nil
+	}
+	setConfigVersion(mapping)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, version, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	return buf.Bytes(), version, nil
+}
+
+// migrateConfigFile upgrades the config data read from path to the current
+// version and returns the migrated data. The file itself is only rewritten when
+// a migration changed the document, keeping the original next to it as
+// <path>.v<version>.bak; if it cannot be rewritten (a read-only or managed
+// config), the migration is used in memory and loading goes on.
+func migrateConfigFile(path string, data []byte) ([]byte, error) {
+	migrated, version, err := migrateConfig(path, data)
+	if err != nil || bytes.Equal(migrated, data) {
+		return migrated, err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := writeMigratedConfig(path, backup, data, migrated); err != nil {
+		yellow.Fprintf(os.Stderr, "⚠️  Unable to save the migrated config file %s (using it migrated in memory): %v\n", path, err)
+		return migrated, nil
+	}
+
+	yellow.Fprintf(os.Stderr, "⬆️  Migrated config file %s from version %d to %d (previous file kept as %s)\n",
+		path, version, currentConfigVersion, backup)
+	return migrated, nil
+}
+
+// writeMigratedConfig replaces the config file at path with migrated under its
+// lock, unless another run changed it since it was read, after copying the
+// original data to backup
+func writeMigratedConfig(path, backup string, data, migrated []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if current, err := os.ReadFile(path); err != nil {
+		return err
+	} else if !bytes.Equal(current, data) {
+		return fmt.Errorf("it was changed while being migrated")
+	}
+	if err := os.WriteFile(backup, data, 0o600); err != nil {
+		return fmt.Errorf("failed to back up config file: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, migrated, 0o600); err != nil {
+		return fmt.Errorf("failed to write migrated config file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write migrated config file: %w", err)
+	}
+	return nil
+}
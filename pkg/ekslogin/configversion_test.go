@@ -0,0 +1,104 @@
+package ekslogin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// withRenamingMigration adds a migration 1 → 2 that renames the top-level key
+// old_region to default_region, for the duration of the test
+func withRenamingMigration(t *testing.T) {
+	t.Helper()
+	migrations, version := configMigrations, currentConfigVersion
+	configMigrations = append(configMigrations[:len(configMigrations):len(configMigrations)], func(mapping *yaml.Node) (bool, error) {
+		for i := 0; i < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == "old_region" {
+				mapping.Content[i].Value = "default_region"
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	currentConfigVersion = len(configMigrations)
+	t.Cleanup(func() { configMigrations, currentConfigVersion = migrations, version })
+}
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMigrateConfigLeavesUnchangedFilesAlone(t *testing.T) {
+	data := "# my settings\ndefault_region:   eu-west-1\n"
+	path := writeConfig(t, data)
+
+	migrated, err := migrateConfigFile(path, []byte(data))
+	if err != nil {
+		t.Fatalf("migrateConfigFile: %v", err)
+	}
+	if string(migrated) != data {
+		t.Errorf("migrated = %q, want the data unchanged", migrated)
+	}
+	if current, _ := os.ReadFile(path); string(current) != data {
+		t.Errorf("file was rewritten to %q", current)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("a backup was written for a file that needed no migration")
+	}
+}
+
+func TestMigrateConfigRewritesChangedFiles(t *testing.T) {
+	withRenamingMigration(t)
+	data := "version: 1\nold_region: eu-west-1\n"
+	path := writeConfig(t, data)
+
+	migrated, err := migrateConfigFile(path, []byte(data))
+	if err != nil {
+		t.Fatalf("migrateConfigFile: %v", err)
+	}
+	if !strings.Contains(string(migrated), "default_region: eu-west-1") || !strings.Contains(string(migrated), "version: 2") {
+		t.Errorf("migrated = %q, want the key renamed and version 2", migrated)
+	}
+	if current, _ := os.ReadFile(path); string(current) != string(migrated) {
+		t.Errorf("file = %q, want the migrated data", current)
+	}
+	if backup, _ := os.ReadFile(path + ".v1.bak"); string(backup) != data {
+		t.Errorf("backup = %q, want the original data", backup)
+	}
+}
+
+func TestMigrateConfigInMemoryWhenUnwritable(t *testing.T) {
+	withRenamingMigration(t)
+	data := "version: 1\nold_region: eu-west-1\n"
+	path := writeConfig(t, data)
+	// A directory in the way of the backup makes the write-back fail, even as root
+	if err := os.Mkdir(path+".v1.bak", 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := migrateConfigFile(path, []byte(data))
+	if err != nil {
+		t.Fatalf("migrateConfigFile on an unwritable config: %v", err)
+	}
+	if !strings.Contains(string(migrated), "default_region: eu-west-1") {
+		t.Errorf("migrated = %q, want the migration applied in memory", migrated)
+	}
+	if current, _ := os.ReadFile(path); string(current) != data {
+		t.Errorf("unwritable file was changed to %q", current)
+	}
+}
+
+func TestMigrateConfigRefusesNewerVersions(t *testing.T) {
+	data := []byte("version: 99\n")
+	if _, _, err := migrateConfig("config.yaml", data); err == nil || !strings.Contains(err.Error(), "upgrade eks-login") {
+		t.Errorf("migrateConfig = %v, want an error asking to upgrade", err)
+	}
+}
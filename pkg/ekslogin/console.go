@@ -0,0 +1,143 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ConsoleURL returns the EKS console page of the selected cluster
+func (app *EKSLoginApp) ConsoleURL() string {
+	path := fmt.Sprintf("/eks/home?region=%s#/clusters/%s", app.config.Region, url.PathEscape(app.config.Cluster))
+	return app.partition().ConsoleURL(app.config.Region, path)
+}
+
+// FederatedSignInURL exchanges the profile credentials for a console sign-in URL
+func (app *EKSLoginApp) FederatedSignInURL(destination string) (string, error) {
+	creds, err := app.ExportCredentials()
+	if err != nil {
+		return "", err
+	}
+	if creds.SessionToken == "" {
+		return "", fmt.Errorf("profile %s does not use temporary credentials; console federation requires a session token", app.config.Profile)
+	}
+
+	session, err := json.Marshal(map[string]string{
+		"sessionId":    creds.AccessKeyID,
+		"sessionKey":   creds.SecretAccessKey,
+		"sessionToken": creds.SessionToken,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"Action":  {"getSigninToken"},
+		"Session": {string(session)},
+	}
+	federationEndpoint := app.partition().SigninEndpoint
+	client, err := app.HTTPClient(15 * time.Second)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(app.context(), http.MethodGet, federationEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request sign-in token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request sign-in token: %s", resp.Status)
+	}
+
+	var token struct {
+		SigninToken string `json:"SigninToken"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse sign-in token: %w", err)
+	}
+
+	login := url.Values{
+		"Action":      {"login"},
+		"Issuer":      {"eks-login"},
+		"Destination": {destination},
+		"SigninToken": {token.SigninToken},
+	}
+	return federationEndpoint + "?" + login.Encode(), nil
+}
+
+// browserCommand builds the command that opens target in the default browser
+func browserCommand(target string) Command {
+	switch runtime.GOOS {
+	case "darwin":
+		return Command{Name: "open", Args: []string{target}}
+	case "windows":
+		return Command{Name: "rundll32", Args: []string{"url.dll,FileProtocolHandler", target}}
+	default:
+		return Command{Name: "xdg-open", Args: []string{target}}
+	}
+}
+
+// OpenBrowser opens target in the default browser. The opener hands the URL
+// to the browser and exits; the browser gets our environment, not the
+// profile's credentials.
+func (app *EKSLoginApp) OpenBrowser(target string) error {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	command := browserCommand(target)
+	command.Env = os.Environ()
+	return app.run(ctx, command)
+}
+
+// OpenConsole opens the AWS console page of the selected cluster
+func (app *EKSLoginApp) OpenConsole(printOnly bool) error {
+	if err := app.SelectTarget(); err != nil {
+		return err
+	}
+
+	blue.Println("🌐 Generating console sign-in URL...")
+	signInURL, err := app.FederatedSignInURL(app.ConsoleURL())
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fmt.Println(signInURL)
+		return nil
+	}
+
+	if err := app.OpenBrowser(signInURL); err != nil {
+		yellow.Println("⚠️  Unable to open a browser, use this URL instead:")
+		fmt.Println(signInURL)
+		return nil
+	}
+
+	green.Printf("✓ Opened console for cluster: %s\n", app.config.Cluster)
+	return nil
+}
+
+func newConsoleCmd(app *EKSLoginApp) *cobra.Command {
+	var printOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "console",
+		Short: "Open the AWS console page for the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.OpenConsole(printOnly)
+		},
+	}
+
+	cmd.Flags().BoolVar(&printOnly, "print", false, "Print the sign-in URL instead of opening a browser")
+	return cmd
+}
@@ -0,0 +1,76 @@
+package ekslogin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// containerCredentials is the response format of the ECS container credentials endpoint
+type containerCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+	Expiration      string `json:"Expiration"`
+}
+
+// containerHandler serves the profile's credentials in the ECS container
+// credentials format to callers presenting the authorization token
+func (app *EKSLoginApp) containerHandler(cache *credentialCache, authToken string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /credentials", func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(authToken)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid authorization token"))
+			return
+		}
+
+		creds, err := cache.Credentials(app, app.config.Profile)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, containerCredentials{
+			AccessKeyID:     creds.AccessKeyID,
+			SecretAccessKey: creds.SecretAccessKey,
+			Token:           creds.SessionToken,
+			Expiration:      creds.Expiration,
+		})
+	})
+	return mux
+}
+
+// ServeContainerCredentials serves the selected profile's credentials over the
+// ECS container credentials interface on a loopback address until interrupted
+func (app *EKSLoginApp) ServeContainerCredentials(address string) error {
+	if app.config.Profile == "" {
+		return usageError("--profile is required with --ecs")
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return usageError("invalid --listen address %q: %v", address, err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return usageError("--listen must be a loopback address such as 127.0.0.1:9911, got %q", address)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate authorization token: %w", err)
+	}
+	authToken := hex.EncodeToString(secret)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	defer listener.Close()
+
+	blue.Printf("🔌 Serving credentials of profile %s for SDKs and containers. Use:\n", app.config.Profile)
+	fmt.Printf("export AWS_CONTAINER_CREDENTIALS_FULL_URI=http://%s/credentials\n", listener.Addr())
+	fmt.Printf("export AWS_CONTAINER_AUTHORIZATION_TOKEN=%s\n", authToken)
+	return app.serveUntilDone(listener, app.containerHandler(newCredentialCache(), authToken))
+}
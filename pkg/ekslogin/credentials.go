@@ -0,0 +1,78 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AWSCredentials holds temporary credentials resolved for a profile
+type AWSCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId" xml:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey" xml:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken,omitempty" xml:"SessionToken"`
+	Expiration      string `json:"Expiration,omitempty" xml:"Expiration"`
+}
+
+// Env returns the environment variables that make the AWS CLI use these credentials
+func (c *AWSCredentials) Env() []string {
+	env := []string{
+		"AWS_ACCESS_KEY_ID=" + c.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + c.SecretAccessKey,
+	}
+	if c.SessionToken != "" {
+		env = append(env, "AWS_SESSION_TOKEN="+c.SessionToken)
+	}
+	return env
+}
+
+// ExportCredentials resolves the credentials of the selected profile via the
+// AWS CLI, or in-process with AWS CLI v1, which cannot export credentials
+func (app *EKSLoginApp) ExportCredentials() (*AWSCredentials, error) {
+	if app.awsV1() {
+		return app.ResolveCredentials()
+	}
+	output, err := app.AWS("configure", "export-credentials",
+		"--format", "process")
+	if err != nil {
+		return nil, fmt.Errorf("failed to export credentials for profile %s: %w", app.config.Profile, err)
+	}
+
+	var creds AWSCredentials
+	if err := json.Unmarshal([]byte(output), &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	return &creds, nil
+}
+
+// CallerIdentity represents the response from sts get-caller-identity
+type CallerIdentity struct {
+	UserID  string `json:"UserId"`
+	Account string `json:"Account"`
+	Arn     string `json:"Arn"`
+}
+
+// GetCallerIdentity returns the identity behind the selected profile
+func (app *EKSLoginApp) GetCallerIdentity() (*CallerIdentity, error) {
+	output, err := app.AWS("sts", "get-caller-identity",
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine caller identity: %w", err)
+	}
+
+	var identity CallerIdentity
+	if err := json.Unmarshal([]byte(output), &identity); err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+// GetAccountID returns the AWS account ID of the selected profile
+func (app *EKSLoginApp) GetAccountID() (string, error) {
+	identity, err := app.GetCallerIdentity()
+	if err != nil {
+		return "", err
+	}
+	return identity.Account, nil
+}
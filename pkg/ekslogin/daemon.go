@@ -0,0 +1,320 @@
+package ekslogin
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Defaults of the daemon options
+const (
+	defaultDaemonInterval     = time.Minute
+	defaultDaemonNotifyBefore = 10 * time.Minute
+)
+
+// DaemonConfig is the daemon section of the config file. A running daemon
+// reads it again on SIGHUP; flags take precedence over it.
+type DaemonConfig struct {
+	// Profiles limits the watched sessions to these profiles (default: every
+	// profile with an eks-login context)
+	Profiles     []string      `yaml:"profiles,omitempty"`
+	Interval     time.Duration `yaml:"interval,omitempty"`
+	NotifyBefore time.Duration `yaml:"notify_before,omitempty"`
+	// NoNotify only logs expiring sessions, without desktop notifications
+	NoNotify bool `yaml:"no_notify,omitempty"`
+}
+
+// DaemonOptions configures the session watcher
+type DaemonOptions struct {
+	Interval     time.Duration
+	NotifyBefore time.Duration
+	Notify       bool
+	// MetricsAddress enables /metrics and token refreshes when set
+	MetricsAddress string
+	// Profiles limits the watched sessions; empty watches every profile with
+	// an eks-login context
+	Profiles []string
+}
+
+// daemonFlags are the daemon options given on the command line
+type daemonFlags struct {
+	options  DaemonOptions
+	noNotify bool
+	changed  func(name string) bool
+}
+
+// resolve merges the flags that were given over settings
+func (f *daemonFlags) resolve(app *EKSLoginApp) DaemonOptions {
+	config := app.settings.Daemon
+	options := DaemonOptions{
+		Interval:       cmp.Or(config.Interval, defaultDaemonInterval),
+		NotifyBefore:   cmp.Or(config.NotifyBefore, defaultDaemonNotifyBefore),
+		Notify:         !config.NoNotify,
+		MetricsAddress: f.options.MetricsAddress,
+		Profiles:       config.Profiles,
+	}
+	if f.changed("interval") {
+		options.Interval = f.options.Interval
+	}
+	if f.changed("notify-before") {
+		options.NotifyBefore = f.options.NotifyBefore
+	}
+	if f.changed("no-notify") {
+		options.Notify = !f.noNotify
+	}
+	if f.changed("profile") {
+		options.Profiles = []string{app.config.Profile}
+	} else if len(options.Profiles) == 0 && app.settings.Default.Profile != "" {
+		options.Profiles = []string{app.settings.Default.Profile}
+	}
+	return options
+}
+
+// watchedContexts returns the metadata of the eks-login contexts in kubeconfig,
+// limited to the profiles of options when given
+func (app *EKSLoginApp) watchedContexts(options DaemonOptions) ([]*LoginMetadata, error) {
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var contexts []*LoginMetadata
+	for _, context := range kubeconfig.Contexts {
+		metadata := context.Context.Metadata()
+		if metadata == nil || metadata.Profile == "" {
+			continue
+		}
+		if len(options.Profiles) > 0 && !slices.Contains(options.Profiles, metadata.Profile) {
+			continue
+		}
+		contexts = append(contexts, metadata)
+	}
+	return contexts, nil
+}
+
+// watchedProfiles returns the profiles of options, or every profile of the
+// given eks-login contexts
+func (app *EKSLoginApp) watchedProfiles(options DaemonOptions, contexts []*LoginMetadata) []string {
+	if len(options.Profiles) > 0 {
+		return options.Profiles
+	}
+
+	seen := make(map[string]bool)
+	var profiles []string
+	for _, metadata := range contexts {
+		if !seen[metadata.Profile] {
+			seen[metadata.Profile] = true
+			profiles = append(profiles, metadata.Profile)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// sessionWatcher remembers the session expiries seen and announced so far
+type sessionWatcher struct {
+	options  DaemonOptions
+	metrics  *daemonMetrics
+	expiries map[string]time.Time
+	notified map[string]time.Time
+	failed   bool
+}
+
+// checkSessions warns about every watched SSO session that expires within the notice period
+func (app *EKSLoginApp) checkSessions(watcher *sessionWatcher) error {
+	contexts, err := app.watchedContexts(watcher.options)
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range app.watchedProfiles(watcher.options, contexts) {
+		expiry, err := app.forTarget(profile, app.config.Region, "").SSOSessionExpiry()
+		if err != nil {
+			watcher.metrics.Failure(profile, "no_session")
+			watcher.metrics.ClearExpiry(profile)
+			continue
+		}
+		if last, ok := watcher.expiries[profile]; ok && expiry.After(last) {
+			watcher.metrics.Login(profile)
+		}
+		watcher.expiries[profile] = expiry
+
+		remaining := time.Until(expiry)
+		watcher.metrics.SetExpiry(profile, remaining.Seconds())
+		if remaining <= 0 {
+			watcher.metrics.Failure(profile, "session_expired")
+		}
+		if remaining > watcher.options.NotifyBefore || watcher.notified[profile].Equal(expiry) {
+			continue
+		}
+		watcher.notified[profile] = expiry
+
+		title := fmt.Sprintf("eks-login: %s session expiring", profile)
+		message := fmt.Sprintf("SSO session expires in %s. Refresh with: aws sso login --profile %s", formatRemaining(remaining), profile)
+		if remaining <= 0 {
+			title = fmt.Sprintf("eks-login: %s session expired", profile)
+			message = fmt.Sprintf("SSO session has expired. Log in again with: aws sso login --profile %s", profile)
+		}
+		yellow.Printf("[%s] ⚠️  %s\n", time.Now().Format("15:04"), message)
+
+		if watcher.options.Notify {
+			if err := app.Notify(title, message); err != nil {
+				watcher.metrics.Failure(profile, "notify_failed")
+				if !watcher.failed {
+					watcher.failed = true
+					yellow.Printf("⚠️  %v\n", err)
+				}
+			}
+		}
+	}
+
+	if watcher.metrics != nil {
+		app.refreshTokens(watcher, contexts)
+	}
+	return nil
+}
+
+// refreshTokens requests an EKS token for every watched context, so broken
+// authentication shows up in the metrics before anyone runs kubectl
+func (app *EKSLoginApp) refreshTokens(watcher *sessionWatcher, contexts []*LoginMetadata) {
+	for _, metadata := range contexts {
+		if expiry, ok := watcher.expiries[metadata.Profile]; !ok || time.Until(expiry) <= 0 {
+			continue
+		}
+		target := app.forTarget(metadata.Profile, metadata.Region, metadata.Cluster)
+		target.config.RoleARN = metadata.RoleARN
+		target.config.RoleDuration, _ = time.ParseDuration(metadata.RoleDuration)
+		if _, err := target.GetClusterToken(); err != nil {
+			watcher.metrics.Failure(metadata.Profile, "token_failed")
+			continue
+		}
+		watcher.metrics.Refresh(metadata.Profile, metadata.Cluster)
+	}
+}
+
+// reloadDaemon reads the config file again and applies the new options to
+// watcher. An invalid config file keeps the previous settings.
+func (app *EKSLoginApp) reloadDaemon(flags *daemonFlags, watcher *sessionWatcher) {
+	settings, err := LoadSettings(app.config.ConfigFile)
+	if err == nil {
+		previous := app.settings
+		app.settings = settings
+		if err = app.loadProjectSettings(); err != nil {
+			app.settings = previous
+		}
+	}
+	if err != nil {
+		yellow.Printf("[%s] ⚠️  Keeping the previous config: %v\n", time.Now().Format("15:04"), err)
+		return
+	}
+
+	options := flags.resolve(app)
+	if options.Interval <= 0 {
+		yellow.Printf("[%s] ⚠️  Keeping the previous config: daemon.interval must be positive\n", time.Now().Format("15:04"))
+		return
+	}
+	// Forget the sessions of profiles no longer watched
+	if len(options.Profiles) > 0 {
+		for profile := range watcher.expiries {
+			if !slices.Contains(options.Profiles, profile) {
+				delete(watcher.expiries, profile)
+				delete(watcher.notified, profile)
+				watcher.metrics.ClearExpiry(profile)
+			}
+		}
+	}
+	watcher.options = options
+	blue.Printf("[%s] 🔄 Reloaded config: %s\n", time.Now().Format("15:04"), describeDaemon(options))
+}
+
+// describeDaemon summarizes what the daemon watches
+func describeDaemon(options DaemonOptions) string {
+	profiles := "all eks-login profiles"
+	if len(options.Profiles) > 0 {
+		profiles = strings.Join(options.Profiles, ", ")
+	}
+	notice := fmt.Sprintf("notifying %s before expiry", options.NotifyBefore)
+	if !options.Notify {
+		notice = fmt.Sprintf("logging %s before expiry", options.NotifyBefore)
+	}
+	return fmt.Sprintf("watching %s every %s (%s)", profiles, options.Interval, notice)
+}
+
+// RunDaemon watches the SSO sessions of the eks-login contexts until interrupted,
+// sending a desktop notification shortly before each one expires. SIGHUP
+// reloads the config file.
+func (app *EKSLoginApp) RunDaemon(flags *daemonFlags) error {
+	options := flags.resolve(app)
+	if options.Interval <= 0 {
+		return usageError("--interval must be positive")
+	}
+	watcher := &sessionWatcher{
+		options:  options,
+		expiries: make(map[string]time.Time),
+		notified: make(map[string]time.Time),
+	}
+	if options.MetricsAddress != "" {
+		watcher.metrics = newDaemonMetrics()
+		if err := app.ServeMetrics(options.MetricsAddress, watcher.metrics); err != nil {
+			return err
+		}
+	}
+
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	blue.Printf("👀 SSO session watcher: %s\n", describeDaemon(options))
+	for {
+		if err := app.checkSessions(watcher); err != nil {
+			yellow.Printf("⚠️  %v\n", err)
+		}
+
+		select {
+		case <-app.context().Done():
+			return nil
+		case <-hangups:
+			app.reloadDaemon(flags, watcher)
+		case <-time.After(watcher.options.Interval):
+		}
+	}
+}
+
+// newDaemonCmd creates the daemon subcommand
+func newDaemonCmd(app *EKSLoginApp) *cobra.Command {
+	flags := &daemonFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Watch SSO sessions and notify before they expire",
+		Long: `Watch the SSO sessions of every profile with an eks-login context (or only
+--profile) and send a desktop notification shortly before one expires, with
+the command that refreshes it.
+
+With --metrics-address, it also requests an EKS token for every watched
+context each interval and serves Prometheus metrics on /metrics: logins,
+token refreshes, failures by class, and seconds until each session expires.
+
+The profiles to watch and the notification settings can also be set in the
+daemon section of the config file; flags take precedence. Send the daemon
+SIGHUP to read the config file again without a restart.
+
+Use 'eks-login daemon install' to start it automatically on login.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.RunDaemon(flags)
+		},
+	}
+
+	addDaemonFlags(cmd, flags)
+	cmd.AddCommand(newDaemonInstallCmd(app))
+	cmd.AddCommand(newDaemonUninstallCmd(app))
+	return cmd
+}
@@ -0,0 +1,106 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// statusToStderr sends colored status messages to stderr so stdout stays machine-readable
+func statusToStderr() {
+	color.Output = statusOutput(os.Stderr)
+}
+
+// printJSON writes value to stdout as indented JSON
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// ShowClusterDetails prints the details of the selected cluster
+func (app *EKSLoginApp) ShowClusterDetails(output string) error {
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return err
+	}
+
+	return printOutput(output, details, func() error {
+		app.printClusterDetails(details)
+		return nil
+	})
+}
+
+// printClusterDetails prints the details of a cluster for people
+func (app *EKSLoginApp) printClusterDetails(details *ClusterDetails) {
+	vpc := details.ResourcesVpcConfig
+
+	cyan.Printf("\n🎯 %s\n", details.Name)
+	fmt.Printf("ARN:              %s\n", details.Arn)
+	fmt.Printf("Status:           %s\n", details.Status)
+	fmt.Printf("Kubernetes:       %s\n", details.Version)
+	fmt.Printf("Platform:         %s\n", details.PlatformVersion)
+	fmt.Printf("Created:          %s\n", details.CreatedAt)
+	fmt.Printf("Endpoint:         %s\n", details.Endpoint)
+	fmt.Printf("Public access:    %t", vpc.EndpointPublicAccess)
+	if vpc.EndpointPublicAccess && len(vpc.PublicAccessCidrs) > 0 {
+		fmt.Printf(" (%s)", strings.Join(vpc.PublicAccessCidrs, ", "))
+	}
+	fmt.Println()
+	fmt.Printf("Private access:   %t\n", vpc.EndpointPrivateAccess)
+	fmt.Printf("Auth mode:        %s\n", details.AccessConfig.AuthenticationMode)
+	fmt.Printf("VPC:              %s\n", vpc.VpcID)
+	fmt.Printf("Subnets:          %s\n", strings.Join(vpc.SubnetIDs, ", "))
+	fmt.Printf("Security group:   %s\n", vpc.ClusterSecurityGroupID)
+
+	var enabledLogs []string
+	for _, logging := range details.Logging.ClusterLogging {
+		if logging.Enabled {
+			enabledLogs = append(enabledLogs, logging.Types...)
+		}
+	}
+	if len(enabledLogs) == 0 {
+		enabledLogs = []string{"disabled"}
+	}
+	fmt.Printf("Logging:          %s\n", strings.Join(enabledLogs, ", "))
+
+	if info, err := app.GetOIDCInfo(details); err == nil && info.Issuer != "" {
+		printOIDCInfo(info)
+	}
+
+	if len(details.Tags) > 0 {
+		keys := make([]string, 0, len(details.Tags))
+		for key := range details.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Println("Tags:")
+		for _, key := range keys {
+			fmt.Printf("  %s = %s\n", key, details.Tags[key])
+		}
+	}
+}
+
+func newDescribeCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Show details of the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.ShowClusterDetails(output)
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	return cmd
+}
@@ -0,0 +1,134 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is how many unchanged lines surround each change in a diff
+const diffContext = 3
+
+// diffLine is one line of a diff: ' ' unchanged, '-' removed or '+' added
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// diffLines compares a and b line by line. Common leading and trailing lines
+// are skipped before the longest common subsequence of the rest is computed,
+// which keeps the table small for the localized edits of a kubeconfig.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			lines = append(lines, diffLine{' ', midA[i]})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', midA[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', midB[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return lines
+}
+
+// unifiedDiff renders the changes from a to b as unified diff hunks, or
+// returns nil when they are equal
+func unifiedDiff(a, b []string) []string {
+	lines := diffLines(a, b)
+
+	var hunks []string
+	for start := 0; start < len(lines); {
+		// find the next change and the end of its hunk
+		first := start
+		for first < len(lines) && lines[first].Op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last := first
+		for k := first; k < len(lines) && k-last <= 2*diffContext; k++ {
+			if lines[k].Op != ' ' {
+				last = k
+			}
+		}
+		from, to := max(first-diffContext, start), min(last+diffContext+1, len(lines))
+
+		// line numbers of the hunk in a and b
+		lineA, lineB := 1, 1
+		for _, line := range lines[:from] {
+			if line.Op != '+' {
+				lineA++
+			}
+			if line.Op != '-' {
+				lineB++
+			}
+		}
+		var countA, countB int
+		body := make([]string, 0, to-from)
+		for _, line := range lines[from:to] {
+			if line.Op != '+' {
+				countA++
+			}
+			if line.Op != '-' {
+				countB++
+			}
+			body = append(body, string(line.Op)+line.Text)
+		}
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+		hunks = append(hunks, fmt.Sprintf("@@ -%d,%d +%d,%d @@", lineA, countA, lineB, countB))
+		hunks = append(hunks, body...)
+		start = to
+	}
+	return hunks
+}
+
+// splitLines splits text into lines without the trailing empty one
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,31 @@
+// Package ekslogin implements eks-login: AWS SSO login, EKS cluster discovery
+// and kubeconfig management. The eks-login binary is a thin wrapper around Main.
+//
+// The login flow can be embedded step by step:
+//
+//	app := ekslogin.NewEKSLoginApp()
+//	app.SetContext(ctx)
+//	app.Config().Profile = "my-sso"
+//	app.Config().Interactive = false
+//	if err := app.LoadSettings(); err != nil { ... }
+//
+//	// Profiles and auth: resolve the profile and make sure its SSO session is valid
+//	if err := app.Authenticate(); err != nil { ... }
+//
+//	// Clusters: list them, or let SelectCluster prompt
+//	clusters, err := app.ListEKSClusters()
+//	app.Config().Cluster = clusters[0]
+//
+//	// Kubeconfig: write the context, then inspect it
+//	if err := app.UpdateKubeconfig(); err != nil { ... }
+//	kubeconfig, err := ekslogin.LoadKubeconfig(ekslogin.KubeconfigPath())
+//
+// External commands (aws, kubectl, docker, hooks) go through an Executor and
+// questions through a Prompter. SetExecutor and SetPrompter replace them, e.g.
+// with fakes that return canned aws CLI output and answers, so the selection
+// and flow logic runs without an AWS account or a terminal.
+//
+// Run executes the whole flow as the eks-login command does, and
+// NewRootCommand returns the complete cobra command tree for reuse in
+// another CLI.
+package ekslogin
@@ -0,0 +1,263 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var awsVersionPattern = regexp.MustCompile(`aws-cli/(\d+)\.(\d+)\.(\d+)`)
+
+// DoctorResult is the outcome of a single diagnostic check
+type DoctorResult struct {
+	Name   string
+	OK     bool
+	Detail string
+	Fix    string
+}
+
+// AWSCLIVersion returns the installed AWS CLI version string and its major version
+func (app *EKSLoginApp) AWSCLIVersion() (string, int, error) {
+	output, err := app.Execute("aws", "--version")
+	if err != nil {
+		return "", 0, err
+	}
+
+	match := awsVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return "", 0, fmt.Errorf("unrecognized aws --version output: %s", output)
+	}
+
+	var major int
+	fmt.Sscanf(match[1], "%d", &major)
+	return strings.Join(match[1:], "."), major, nil
+}
+
+func (app *EKSLoginApp) checkAWSCLI() DoctorResult {
+	result := DoctorResult{Name: "AWS CLI"}
+
+	if _, err := exec.LookPath("aws"); err != nil {
+		result.Detail = "not found in PATH"
+		result.Fix = "Install AWS CLI v2: https://docs.aws.amazon.com/cli/latest/userguide/install-cliv2.html"
+		return result
+	}
+
+	version, major, err := app.AWSCLIVersion()
+	if err != nil {
+		result.Detail = err.Error()
+		result.Fix = "Reinstall AWS CLI v2"
+		return result
+	}
+
+	result.Detail = "v" + version
+	if major < 2 {
+		result.Fix = "AWS CLI v2 is required for SSO; upgrade from v1"
+		return result
+	}
+
+	result.OK = true
+	return result
+}
+
+func (app *EKSLoginApp) checkKubectl() DoctorResult {
+	result := DoctorResult{Name: "kubectl"}
+
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		result.Detail = "not found in PATH"
+		result.Fix = "Install kubectl: https://kubernetes.io/docs/tasks/tools/install-kubectl/"
+		return result
+	}
+
+	version, err := app.KubectlClientVersion()
+	if err != nil {
+		result.Detail = err.Error()
+		result.Fix = "Reinstall kubectl"
+		return result
+	}
+
+	result.OK = true
+	result.Detail = version.GitVersion
+	return result
+}
+
+func (app *EKSLoginApp) checkSSOConfig() DoctorResult {
+	result := DoctorResult{Name: "SSO configuration"}
+
+	if app.config.Profile == "" {
+		result.OK = true
+		result.Detail = "skipped (no --profile given)"
+		return result
+	}
+
+	get := func(key string) string {
+		value, _ := app.AWS("configure", "get", key)
+		return value
+	}
+
+	startURL := get("sso_start_url")
+	if session := get("sso_session"); session != "" && startURL == "" {
+		startURL = get("sso-session." + session + ".sso_start_url")
+		if startURL == "" {
+			startURL = "sso-session " + session
+		}
+	}
+
+	var missing []string
+	if startURL == "" {
+		missing = append(missing, "sso_start_url/sso_session")
+	}
+	for _, key := range []string{"sso_account_id", "sso_role_name"} {
+		if get(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		result.Detail = fmt.Sprintf("profile %s is missing %s", app.config.Profile, strings.Join(missing, ", "))
+		result.Fix = fmt.Sprintf("Run: aws configure sso --profile %s", app.config.Profile)
+		return result
+	}
+
+	result.OK = true
+	result.Detail = fmt.Sprintf("profile %s uses %s", app.config.Profile, startURL)
+	return result
+}
+
+func (app *EKSLoginApp) checkNetwork() DoctorResult {
+	result := DoctorResult{Name: "Network"}
+
+	client, err := app.HTTPClient(5 * time.Second)
+	if err != nil {
+		result.Detail = err.Error()
+		result.Fix = "Check the --ca-bundle / ca_bundle setting"
+		return result
+	}
+
+	hosts := []string{
+		app.serviceEndpoint("sts"),
+		app.serviceEndpoint("eks"),
+	}
+
+	// Any HTTP response proves the endpoint is reachable through proxies and TLS
+	var unreachable []string
+	for _, host := range hosts {
+		req, err := http.NewRequestWithContext(app.context(), http.MethodGet, "https://"+host+"/", nil)
+		if err != nil {
+			unreachable = append(unreachable, host)
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			unreachable = append(unreachable, host)
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	if len(unreachable) > 0 {
+		result.Detail = "cannot reach " + strings.Join(unreachable, ", ")
+		result.Fix = "Check your internet/VPN connection, HTTPS_PROXY/NO_PROXY and --ca-bundle"
+		return result
+	}
+
+	result.OK = true
+	result.Detail = "AWS endpoints reachable in " + app.config.Region
+	if proxy := os.Getenv("HTTPS_PROXY"); proxy != "" {
+		result.Detail += " via " + proxy
+	}
+	return result
+}
+
+func (app *EKSLoginApp) checkKubeconfig() DoctorResult {
+	path := KubeconfigPath()
+	result := DoctorResult{Name: "Kubeconfig"}
+
+	// Diagnostics change nothing: a missing directory is reported, and the
+	// first login creates it
+	dir := filepath.Dir(path)
+	if info, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		result.OK = true
+		result.Detail = fmt.Sprintf("%s does not exist yet; the first login creates it", dir)
+		return result
+	} else if err != nil {
+		result.Detail = fmt.Sprintf("cannot access %s: %v", dir, err)
+		result.Fix = fmt.Sprintf("Fix permissions on %s", dir)
+		return result
+	} else if !info.IsDir() {
+		result.Detail = fmt.Sprintf("%s is not a directory", dir)
+		result.Fix = fmt.Sprintf("Move %s out of the way or set KUBECONFIG", dir)
+		return result
+	}
+
+	probe, err := os.CreateTemp(dir, ".eks-login-doctor-*")
+	if err != nil {
+		result.Detail = fmt.Sprintf("%s is not writable", dir)
+		result.Fix = fmt.Sprintf("Fix permissions on %s", dir)
+		return result
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	if file, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		file.Close()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		result.Detail = fmt.Sprintf("%s is not writable", path)
+		result.Fix = fmt.Sprintf("Fix permissions on %s", path)
+		return result
+	}
+
+	result.OK = true
+	result.Detail = path + " is writable"
+	return result
+}
+
+// Doctor runs all diagnostics and prints actionable fixes
+func (app *EKSLoginApp) Doctor() error {
+	blue.Println("🩺 Running diagnostics...")
+
+	results := []DoctorResult{
+		app.checkAWSCLI(),
+		app.checkKubectl(),
+		app.checkSSOConfig(),
+		app.checkNetwork(),
+		app.checkKubeconfig(),
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.OK {
+			green.Printf("  ✓ %s: %s\n", result.Name, result.Detail)
+			continue
+		}
+		failed++
+		red.Printf("  ✗ %s: %s\n", result.Name, result.Detail)
+		if result.Fix != "" {
+			fmt.Printf("    → %s\n", result.Fix)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+
+	green.Println("\n✓ Everything looks good!")
+	return nil
+}
+
+func newDoctorCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the local setup and print fixes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.Doctor()
+		},
+	}
+}
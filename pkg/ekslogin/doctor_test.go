@@ -0,0 +1,21 @@
+package ekslogin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckKubeconfigReportsMissingDirectory(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	dir := filepath.Join(t.TempDir(), ".kube")
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "config"))
+
+	result := app.checkKubeconfig()
+	if !result.OK {
+		t.Errorf("checkKubeconfig = %+v, want a missing directory to pass", result)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("checkKubeconfig created %s", dir)
+	}
+}
@@ -0,0 +1,136 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// shellQuote quotes a word for a POSIX shell when needed
+func shellQuote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n\"'\\$`|&;<>()*?[]{}~#!") {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// printDryRunCommand prints a command a dry run does not execute
+func printDryRunCommand(name string, args ...string) {
+	words := []string{name}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	fmt.Printf("  $ %s\n", strings.Join(words, " "))
+}
+
+// plannedKubeconfig returns the kubeconfig entries a login would merge into
+// kubeconfig for the selected cluster, without the certificate authority
+func (app *EKSLoginApp) plannedKubeconfig(tokenExec string) (*Kubeconfig, error) {
+	kubeconfig, err := app.plannedEntries(tokenExec)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig.Clusters[0].Cluster.CertificateAuthorityData = "<certificate authority of the cluster>"
+	return kubeconfig, nil
+}
+
+// plannedEntries returns the kubeconfig entries a login would merge into
+// kubeconfig for the selected cluster
+func (app *EKSLoginApp) plannedEntries(tokenExec string) (*Kubeconfig, error) {
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, err := app.kubeconfigEntries(details, tokenExec)
+	if err != nil {
+		return nil, err
+	}
+	name := kubeconfig.CurrentContext
+	context := *kubeconfig.Context(name)
+	exec := kubeconfig.User(context.User).Exec
+
+	if readOnly := app.settings.ReadOnly; app.wantsReadOnly() && readOnly.As != "" {
+		suffix := readOnly.Suffix
+		if suffix == "" {
+			suffix = defaultReadOnlySuffix
+		}
+		kubeconfig.SetUser(context.User+suffix, KubeUser{Exec: exec, As: readOnly.As, AsGroups: readOnly.AsGroups})
+		readOnlyContext := context
+		readOnlyContext.User = context.User + suffix
+		kubeconfig.SetContext(name+suffix, readOnlyContext)
+		kubeconfig.CurrentContext = name + suffix
+	}
+	return kubeconfig, nil
+}
+
+// DryRun prints the commands a login to the selected cluster would execute and
+// the kubeconfig entries it would write, without running or changing anything.
+// Read-only AWS calls that resolve the target still run.
+func (app *EKSLoginApp) DryRun() error {
+	tokenExec, err := app.tokenExec()
+	if err != nil {
+		return err
+	}
+
+	yellow.Printf("\n🧪 Dry run for %s (profile: %s, region: %s): nothing is executed or written\n", app.config.Cluster, app.config.Profile, app.config.Region)
+	if err := app.RunHooks("pre-kubeconfig", app.settings.Hooks.PreKubeconfig); err != nil {
+		return err
+	}
+	if app.IsProtected() {
+		yellow.Printf("🚨 %s is a protected cluster: the login would ask for confirmation\n", app.config.Cluster)
+		if app.settings.Protected.Webhook != "" {
+			yellow.Println("📣 The login would be reported to the protected cluster webhook")
+		}
+	}
+
+	blue.Println("\n📋 Commands that would run:")
+	printDryRunCommand("aws", app.updateKubeconfigArgs()...)
+	if app.config.ECR {
+		registry, err := app.ECRRegistry()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  $ aws %s | docker login --username AWS --password-stdin %s\n",
+			strings.Join(app.awsArgs("ecr", "get-login-password", "--region", app.config.Region), " "), registry)
+	}
+	if app.config.LaunchK9s || app.settings.Launch.Enabled {
+		command := app.settings.Launch.Command
+		if command == "" {
+			command = defaultLaunchCommand
+		}
+		fmt.Printf("  $ %s\n", command)
+	}
+
+	kubeconfig, err := app.plannedKubeconfig(tokenExec)
+	if err != nil {
+		return err
+	}
+	path, err := app.splitKubeconfigPath()
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		blue.Printf("\n📝 Entries that would be written to %s:\n", path)
+	} else {
+		blue.Printf("\n📝 Entries that would be merged into %s:\n", KubeconfigPath())
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(kubeconfig)
+}
+
+// dryRunClusters runs DryRun for each picked cluster
+func (app *EKSLoginApp) dryRunClusters() error {
+	if len(app.config.Clusters) <= 1 {
+		return app.DryRun()
+	}
+	for _, target := range app.config.Clusters {
+		if err := app.forTarget(app.config.Profile, target.Region, target.Cluster).DryRun(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
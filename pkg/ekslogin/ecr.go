@@ -0,0 +1,70 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ECRRegistry returns the ECR registry host of the selected account and region
+func (app *EKSLoginApp) ECRRegistry() (string, error) {
+	account, err := app.GetAccountID()
+	if err != nil {
+		return "", err
+	}
+	return account + "." + app.partition().Endpoint("dkr.ecr", app.config.Region), nil
+}
+
+// LoginECR performs docker login against the account's ECR registry
+func (app *EKSLoginApp) LoginECR() error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("required dependency 'docker' not found in PATH")
+	}
+
+	registry, err := app.ECRRegistry()
+	if err != nil {
+		return err
+	}
+
+	blue.Printf("🐳 Logging in to ECR registry: %s\n", registry)
+
+	password, err := app.AWS("ecr", "get-login-password",
+		"--region", app.config.Region)
+	if err != nil {
+		return fmt.Errorf("failed to get ECR login password: %w", err)
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	err = app.run(ctx, Command{
+		Name:   "docker",
+		Args:   []string{"login", "--username", "AWS", "--password-stdin", registry},
+		Env:    os.Environ(),
+		Stdin:  strings.NewReader(password),
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("docker login failed: %w", timeoutError(ctx, "docker login", app.timeout(), err))
+	}
+
+	green.Println("✓ ECR login successful")
+	return nil
+}
+
+func newECRCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ecr",
+		Short: "Log docker in to the account's ECR registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.Authenticate(); err != nil {
+				return err
+			}
+			return app.LoginECR()
+		},
+	}
+}
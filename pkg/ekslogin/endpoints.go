@@ -0,0 +1,63 @@
+package ekslogin
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// parseEndpointOverrides parses --endpoint-url values of the form URL or service=URL.
+// A bare URL applies to every service and is stored under the "default" key.
+func parseEndpointOverrides(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, value := range values {
+		service, endpoint := "default", value
+		if key, rest, ok := strings.Cut(value, "="); ok && !strings.Contains(key, "://") {
+			service, endpoint = strings.ToLower(strings.TrimSpace(key)), rest
+		}
+
+		if parsed, err := url.Parse(endpoint); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint URL for %s: %q", service, endpoint)
+		}
+		overrides[service] = endpoint
+	}
+	return overrides, nil
+}
+
+// endpointEnv converts endpoint overrides to the AWS_ENDPOINT_URL* variables understood by the AWS CLI
+func endpointEnv(overrides map[string]string) []string {
+	services := make([]string, 0, len(overrides))
+	for service := range overrides {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	env := make([]string, 0, len(services))
+	for _, service := range services {
+		name := "AWS_ENDPOINT_URL"
+		if service != "default" {
+			name += "_" + strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(service))
+		}
+		env = append(env, name+"="+overrides[service])
+	}
+	return env
+}
+
+// endpointOverrides merges the configured endpoints with the --endpoint-url flags
+func (app *EKSLoginApp) endpointOverrides() (map[string]string, error) {
+	overrides := make(map[string]string, len(app.settings.Endpoints))
+	for service, endpoint := range app.settings.Endpoints {
+		overrides[strings.ToLower(service)] = endpoint
+	}
+
+	flags, err := parseEndpointOverrides(app.config.EndpointURLs)
+	if err != nil {
+		return nil, err
+	}
+	for service, endpoint := range flags {
+		overrides[service] = endpoint
+	}
+
+	return overrides, nil
+}
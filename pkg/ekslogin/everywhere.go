@@ -0,0 +1,148 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListEverywhere lists the clusters of every matching profile, in the --region
+// regions or else each profile's own region. Profiles whose session cannot be
+// established or whose clusters cannot be listed are skipped with a warning.
+// Sessions are checked one by one, as they may prompt for a login; the
+// regions are then listed up to --concurrency at once, reporting progress.
+func (app *EKSLoginApp) ListEverywhere() ([]InventoryEntry, error) {
+	profiles, err := app.FilteredProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, withExitCode(ExitNoProfiles, "no_profiles", fmt.Errorf("no AWS profiles found. Please configure AWS CLI first"))
+	}
+
+	blue.Printf("🌍 Discovering clusters in %d profile(s)...\n", len(profiles))
+
+	var targets []*EKSLoginApp
+	var skipped []scanFailure
+	for _, profile := range profiles {
+		if err := app.forTarget(profile.Name, profile.Region, "").ensureSession(); err != nil {
+			yellow.Printf("⚠️  Skipping profile %s: %v\n", profile.Name, err)
+			skipped = append(skipped, scanFailure{Target: profile.Name, Err: err})
+			continue
+		}
+
+		regions := app.regions()
+		if len(regions) == 0 {
+			regions = []string{profile.Region}
+		}
+		for _, region := range regions {
+			targets = append(targets, app.forTarget(profile.Name, region, ""))
+		}
+	}
+
+	found, errs := app.scanTargets(targets, skipped)
+
+	var entries []InventoryEntry
+	for i, target := range targets {
+		if errs[i] != nil {
+			continue
+		}
+		for _, cluster := range found[i] {
+			entries = append(entries, InventoryEntry{Profile: target.config.Profile, Region: target.config.Region, Cluster: cluster})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Cluster < entries[j].Cluster
+	})
+	return entries, nil
+}
+
+// scanTargets lists the clusters of each profile/region target, up to
+// --concurrency at once, reporting progress and summarizing the failed
+// targets along with those skipped before the scan
+func (app *EKSLoginApp) scanTargets(targets []*EKSLoginApp, skipped []scanFailure) ([][]string, []error) {
+	blue.Printf("📋 Listing clusters in %d profile/region pair(s)...\n", len(targets))
+	progress := NewScanProgress(len(targets))
+	for _, failure := range skipped {
+		progress.Skip(failure.Target, failure.Err)
+	}
+
+	found := make([][]string, len(targets))
+	errs := make([]error, len(targets))
+	app.forEach(len(targets), func(i int) {
+		found[i], errs[i] = targets[i].listClusters()
+		progress.Done(targets[i].config.Profile+"/"+targets[i].config.Region, len(found[i]), errs[i])
+	})
+	progress.Summary()
+	return found, errs
+}
+
+// SelectEverywhere picks the cluster from all matching profiles and regions
+// and selects the profile and region it belongs to. A --cluster value narrows
+// the list to the clusters of that name, or else those it is a (case-insensitive)
+// prefix of.
+func (app *EKSLoginApp) SelectEverywhere() error {
+	entries, err := app.ListEverywhere()
+	if err != nil {
+		return err
+	}
+
+	if name := app.config.Cluster; name != "" {
+		var exact, prefixed []InventoryEntry
+		for _, entry := range entries {
+			if entry.Cluster == name {
+				exact = append(exact, entry)
+			} else if strings.HasPrefix(strings.ToLower(entry.Cluster), strings.ToLower(name)) {
+				prefixed = append(prefixed, entry)
+			}
+		}
+		entries = exact
+		if len(exact) == 0 {
+			entries = prefixed
+		}
+	}
+
+	if len(entries) == 0 {
+		if app.config.Cluster != "" {
+			return withExitCode(ExitClusterNotFound, "cluster_not_found", fmt.Errorf("cluster %s not found in any profile", app.config.Cluster))
+		}
+		return withExitCode(ExitNoClusters, "no_clusters", fmt.Errorf("no EKS clusters found in any profile"))
+	}
+
+	choice := 0
+	if len(entries) > 1 {
+		// Preselect the cluster logged in to most recently
+		state := app.LoadState()
+		last := -1
+		for i, entry := range entries {
+			used, ok := state.Used[entry.Profile+"/"+entry.Region+"/"+entry.Cluster]
+			if ok && (last < 0 || used.After(state.Used[entries[last].Profile+"/"+entries[last].Region+"/"+entries[last].Cluster])) {
+				last = i
+			}
+		}
+
+		items := make([]string, len(entries))
+		for i, entry := range entries {
+			items[i] = fmt.Sprintf("%s (%s, %s)", entry.Cluster, entry.Profile, entry.Region)
+		}
+		if !app.config.Interactive {
+			return usageError("several clusters match, pick one with --profile and --cluster: %s", strings.Join(items, ", "))
+		}
+
+		blue.Println("\n🎯 Available EKS Clusters in all profiles:")
+		choice, err = app.Select("cluster", items, last)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry := entries[choice]
+	app.config.Profile = entry.Profile
+	app.config.Cluster = entry.Cluster
+	app.config.Region = entry.Region
+	app.config.Regions = []string{entry.Region}
+	app.config.RegionSet = true
+	cyan.Printf("🎯 Using cluster %s (profile: %s, region: %s)\n", entry.Cluster, entry.Profile, entry.Region)
+	return nil
+}
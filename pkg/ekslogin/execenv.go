@@ -0,0 +1,104 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// execEnv returns the environment the exec credential plugin needs to reach
+// the same AWS profile as this login, whatever the environment kubectl runs
+// in: AWS_PROFILE (or the web identity role and token file), AWS_REGION and,
+// when set now, the AWS config and credentials file locations
+func (app *EKSLoginApp) execEnv() []ExecEnvVar {
+	var env []ExecEnvVar
+	if app.webIdentity != nil {
+		env = append(env, app.webIdentity.Env()...)
+	} else if app.config.Profile != "" {
+		env = append(env, ExecEnvVar{Name: "AWS_PROFILE", Value: app.config.Profile})
+	}
+	if app.config.Region != "" {
+		env = append(env, ExecEnvVar{Name: "AWS_REGION", Value: app.config.Region})
+	}
+	env = append(env, app.stsExecEnv()...)
+	for _, name := range []string{"AWS_CONFIG_FILE", "AWS_SHARED_CREDENTIALS_FILE"} {
+		path := os.Getenv(name)
+		if path == "" {
+			continue
+		}
+		// kubectl may run from another directory
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		env = append(env, ExecEnvVar{Name: name, Value: path})
+	}
+	return env
+}
+
+// replacedExecEnv returns the variables a previous login may have left in the
+// exec block that would select other credentials than this login's
+func (app *EKSLoginApp) replacedExecEnv() []string {
+	if app.webIdentity != nil {
+		return []string{"AWS_PROFILE"}
+	}
+	return []string{"AWS_ROLE_ARN", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_SESSION_NAME"}
+}
+
+// withoutExecEnv returns env without the variables of the given names
+func withoutExecEnv(env []ExecEnvVar, names ...string) []ExecEnvVar {
+	var kept []ExecEnvVar
+	for _, v := range env {
+		if !slices.Contains(names, v.Name) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// mergeExecEnv sets vars in env, replacing variables of the same name and
+// keeping any others
+func mergeExecEnv(env, vars []ExecEnvVar) []ExecEnvVar {
+	merged := append([]ExecEnvVar(nil), env...)
+	for _, v := range vars {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == v.Name {
+				merged[i].Value = v.Value
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// SetExecEnv adds the login's AWS environment to the exec block of the
+// current context's user, and points aws eks get-token at the STS region.
+// The caller saves the kubeconfig.
+func (app *EKSLoginApp) SetExecEnv(kubeconfig *Kubeconfig) error {
+	path := KubeconfigPath()
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, path)
+	}
+	user := kubeconfig.User(context.User)
+	if user == nil {
+		return fmt.Errorf("user %q not found in %s", context.User, path)
+	}
+	if user.Exec == nil {
+		return nil
+	}
+
+	user.Exec.Env = mergeExecEnv(withoutExecEnv(user.Exec.Env, app.replacedExecEnv()...), app.execEnv())
+	if user.Exec.Command == "aws" {
+		region, err := app.stsRegion()
+		if err != nil {
+			return err
+		}
+		user.Exec.Args = withRegionArg(user.Exec.Args, region)
+	}
+	return nil
+}
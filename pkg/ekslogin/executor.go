@@ -0,0 +1,97 @@
+package ekslogin
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// Command describes an external command for an Executor
+type Command struct {
+	Name string
+	Args []string
+	// Env is the complete environment, or nil to inherit ours
+	Env    []string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Isolate runs the command in its own process group so that cancelling it
+	// also stops any processes it spawned. Interactive commands must not set it.
+	Isolate bool
+}
+
+// Executor runs external commands (aws, kubectl, docker, hooks). Tests and
+// embedders can substitute a fake with SetExecutor.
+type Executor interface {
+	// Run runs cmd to completion; a non-zero exit status is an *exec.ExitError
+	Run(ctx context.Context, cmd Command) error
+}
+
+// processExecutor runs commands as real child processes
+type processExecutor struct{}
+
+func (processExecutor) Run(ctx context.Context, command Command) error {
+	cmd := exec.CommandContext(ctx, command.Name, command.Args...)
+	cmd.Env = command.Env
+	cmd.Stdin = command.Stdin
+	cmd.Stdout = command.Stdout
+	cmd.Stderr = command.Stderr
+	if command.Isolate {
+		setProcessGroup(cmd)
+	}
+	return cmd.Run()
+}
+
+// Prompter asks the user for input. Tests and embedders can substitute a fake
+// with SetPrompter.
+type Prompter interface {
+	// Select asks for one item, or several when multi is set, and returns their
+	// indexes. A non-negative preselected index is the default answer.
+	Select(label string, items []string, preselected int, multi bool) ([]int, error)
+	// ReadLine reads one line of free-form input
+	ReadLine() (string, error)
+}
+
+// terminalPrompter prompts on the terminal: an arrow-key menu where supported,
+// a numbered list otherwise or when plain is set
+type terminalPrompter struct {
+	stdin *bufio.Reader
+	plain bool
+}
+
+func newTerminalPrompter() *terminalPrompter {
+	return &terminalPrompter{stdin: bufio.NewReader(os.Stdin)}
+}
+
+func (p *terminalPrompter) Select(label string, items []string, preselected int, multi bool) ([]int, error) {
+	if !p.plain && menuSupported() {
+		return p.selectMenu(label, items, preselected, multi)
+	}
+	return p.selectNumbered(label, items, preselected, multi)
+}
+
+func (p *terminalPrompter) ReadLine() (string, error) {
+	// the answer's Enter ends the prompt's line on the terminal
+	defer inputRead()
+	return p.stdin.ReadString('\n')
+}
+
+// SetExecutor replaces the runner of external commands
+func (app *EKSLoginApp) SetExecutor(executor Executor) {
+	app.executor = executor
+}
+
+// SetPrompter replaces the source of interactive answers
+func (app *EKSLoginApp) SetPrompter(prompter Prompter) {
+	app.prompter = prompter
+}
+
+// run runs an external command through the app's executor, bounded by ctx
+func (app *EKSLoginApp) run(ctx context.Context, command Command) error {
+	if command.Env == nil {
+		command.Env = app.commandEnv()
+	}
+	return app.executor.Run(ctx, command)
+}
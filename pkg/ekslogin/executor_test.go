@@ -0,0 +1,144 @@
+package ekslogin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeExecutor answers commands from canned outputs keyed by a prefix of
+// "name args..." and records every command it was asked to run
+type fakeExecutor struct {
+	outputs  map[string]string
+	commands []string
+}
+
+func (f *fakeExecutor) Run(ctx context.Context, command Command) error {
+	line := strings.Join(append([]string{command.Name}, command.Args...), " ")
+	f.commands = append(f.commands, line)
+	for prefix, output := range f.outputs {
+		if strings.HasPrefix(line, prefix) {
+			if command.Stdout != nil {
+				io.WriteString(command.Stdout, output)
+			}
+			return nil
+		}
+	}
+	return errors.New("unexpected command: " + line)
+}
+
+// ran reports whether a command starting with prefix was run
+func (f *fakeExecutor) ran(prefix string) bool {
+	for _, line := range f.commands {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fakePrompter gives scripted answers
+type fakePrompter struct {
+	choice int
+	line   string
+	labels []string
+}
+
+func (p *fakePrompter) Select(label string, items []string, preselected int, multi bool) ([]int, error) {
+	p.labels = append(p.labels, label)
+	return []int{p.choice}, nil
+}
+
+func (p *fakePrompter) ReadLine() (string, error) {
+	return p.line + "\n", nil
+}
+
+// newTestApp returns an app whose commands and prompts are faked and whose
+// files live in a temporary home
+func newTestApp(t *testing.T, executor *fakeExecutor, prompter *fakePrompter) *EKSLoginApp {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home+"/.config")
+	t.Setenv("XDG_CACHE_HOME", home+"/.cache")
+	t.Setenv("AWS_PROFILE", "")
+
+	app := NewEKSLoginApp()
+	app.SetExecutor(executor)
+	app.SetPrompter(prompter)
+	app.config.Profile = "dev"
+	app.config.Region = "eu-west-1"
+	app.config.RegionSet = true
+	return app
+}
+
+func TestSelectTargetPicksClusterWithFakes(t *testing.T) {
+	executor := &fakeExecutor{outputs: map[string]string{
+		"aws sts get-caller-identity": `{"UserId":"x","Account":"123456789012","Arn":"arn:aws:sts::123456789012:assumed-role/Dev/alice"}`,
+		"aws eks list-clusters":       `{"clusters":["alpha","beta"]}`,
+	}}
+	prompter := &fakePrompter{choice: 1}
+	app := newTestApp(t, executor, prompter)
+
+	if err := app.SelectTarget(); err != nil {
+		t.Fatalf("SelectTarget: %v", err)
+	}
+	if app.config.Cluster != "beta" {
+		t.Errorf("cluster = %q, want beta", app.config.Cluster)
+	}
+	if !executor.ran("aws sts get-caller-identity --profile dev") {
+		t.Errorf("SSO session was not checked with the profile; ran %q", executor.commands)
+	}
+	if executor.ran("aws sso login") {
+		t.Errorf("logged in although the session was valid")
+	}
+	if len(prompter.labels) != 1 || prompter.labels[0] != "cluster" {
+		t.Errorf("prompts = %q, want one cluster prompt", prompter.labels)
+	}
+}
+
+func TestSelectTargetLogsInWhenSessionExpired(t *testing.T) {
+	executor := &fakeExecutor{outputs: map[string]string{
+		"aws --version":         "aws-cli/2.15.0 Python/3.11.6 Linux/6 exe/x86_64",
+		"aws sso login":         "",
+		"aws eks list-clusters": `{"clusters":["alpha"]}`,
+	}}
+	app := newTestApp(t, executor, &fakePrompter{})
+	app.settings.Retry.MaxAttempts = 1
+
+	if err := app.SelectTarget(); err != nil {
+		t.Fatalf("SelectTarget: %v", err)
+	}
+	if !executor.ran("aws sso login --profile dev") {
+		t.Errorf("expired session did not trigger aws sso login; ran %q", executor.commands)
+	}
+	if app.config.Cluster != "alpha" {
+		t.Errorf("cluster = %q, want the only cluster alpha", app.config.Cluster)
+	}
+}
+
+func TestConfirmProtectedRejectsWrongName(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{line: "prod-eu"})
+	app.settings.Protected.Clusters = []string{"prod-*"}
+	app.config.Cluster = "prod-us"
+
+	err := app.ConfirmProtected()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitNotConfirmed {
+		t.Fatalf("ConfirmProtected = %v, want exit code %d", err, ExitNotConfirmed)
+	}
+}
+
+func TestNotifyRunsThroughExecutor(t *testing.T) {
+	executor := &fakeExecutor{outputs: map[string]string{"": ""}}
+	app := newTestApp(t, executor, &fakePrompter{})
+
+	if err := app.Notify("eks-login", "session expires soon"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(executor.commands) != 1 || !strings.Contains(executor.commands[0], "session expires soon") {
+		t.Errorf("commands = %q, want one notification command", executor.commands)
+	}
+}
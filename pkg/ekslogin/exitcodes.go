@@ -0,0 +1,60 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes returned for each class of failure. They are part of the CLI's
+// interface: wrapper scripts branch on them, so existing values never change.
+const (
+	ExitOK                = 0
+	ExitFailure           = 1  // unclassified failure
+	ExitUsage             = 2  // invalid flags or missing input
+	ExitDependencyMissing = 3  // aws (or another required tool) not in PATH
+	ExitSSOLoginFailed    = 4  // SSO session invalid and login failed or impossible, or the web identity role could not be assumed
+	ExitNoProfiles        = 5  // no AWS profiles configured
+	ExitNoClusters        = 6  // no EKS clusters visible to the profile
+	ExitClusterNotFound   = 7  // the requested cluster does not exist
+	ExitKubeconfigFailed  = 8  // kubeconfig could not be written
+	ExitHookAborted       = 9  // a configured hook rejected the login
+	ExitNotConfirmed      = 10 // a protected cluster or context overwrite was not confirmed
+	ExitAborted           = 11 // the user aborted a prompt
+)
+
+// ExitError attaches an exit code and a machine-readable reason to an error
+type ExitError struct {
+	Code   int
+	Reason string
+	Err    error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode classifies err; nil stays nil
+func withExitCode(code int, reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Reason: reason, Err: err}
+}
+
+// usageError classifies an invalid invocation
+func usageError(format string, args ...interface{}) error {
+	return withExitCode(ExitUsage, "usage", fmt.Errorf(format, args...))
+}
+
+// exitCode returns the exit code and reason of err
+func exitCode(err error) (int, string) {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code, exitErr.Reason
+	}
+	return ExitFailure, "error"
+}
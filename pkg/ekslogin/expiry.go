@@ -0,0 +1,137 @@
+package ekslogin
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ssoCacheEntry is a token file written by aws sso login under ~/.aws/sso/cache
+type ssoCacheEntry struct {
+	StartURL    string `json:"startUrl"`
+	AccessToken string `json:"accessToken"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+// ssoTimeLayouts are the expiresAt formats written by AWS CLI versions
+var ssoTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05UTC"}
+
+// parseSSOTime parses an expiresAt value of the SSO token cache
+func parseSSOTime(value string) (time.Time, error) {
+	for _, layout := range ssoTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized expiry %q", value)
+}
+
+// SSOTokenPath returns the token file aws sso login writes for the selected
+// profile, named after its sso_session or, for legacy profiles, its sso_start_url
+func (app *EKSLoginApp) SSOTokenPath() (string, error) {
+	key, _ := app.AWS("configure", "get", "sso_session")
+	if key == "" {
+		key, _ = app.AWS("configure", "get", "sso_start_url")
+	}
+	if key == "" {
+		return "", fmt.Errorf("profile %s does not use SSO", app.config.Profile)
+	}
+	return ssoCachePath(key)
+}
+
+// ssoCachePath returns the token cache file of an sso_session name or start URL
+func ssoCachePath(key string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(home, ".aws", "sso", "cache", hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// readSSOExpiry returns the expiry of the SSO token cached at path
+func readSSOExpiry(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no cached SSO token: %w", err)
+	}
+	var entry ssoCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse SSO token cache: %w", err)
+	}
+	return parseSSOTime(entry.ExpiresAt)
+}
+
+// SSOSessionExpiry returns when the SSO session of the selected profile expires
+func (app *EKSLoginApp) SSOSessionExpiry() (time.Time, error) {
+	path, err := app.SSOTokenPath()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return readSSOExpiry(path)
+}
+
+// TokenExpiry returns when a freshly issued EKS token of the selected cluster expires
+func (app *EKSLoginApp) TokenExpiry() (time.Time, error) {
+	credential, err := app.GetClusterToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, credential.Status.ExpirationTimestamp)
+}
+
+// formatRemaining renders a duration in hours and minutes, e.g. "6h42m"
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// describeExpiry renders an expiry time relative to now, e.g. "expires in 6h42m (17:30)",
+// highlighted when less than warnWithin remains
+func describeExpiry(t time.Time, warnWithin time.Duration) string {
+	remaining := time.Until(t)
+	local := t.Local().Format("15:04")
+	if remaining <= 0 {
+		return red.Sprintf("expired %s ago (%s)", formatRemaining(-remaining), local)
+	}
+	text := fmt.Sprintf("expires in %s (%s)", formatRemaining(remaining), local)
+	if remaining < warnWithin {
+		return yellow.Sprint(text)
+	}
+	return text
+}
+
+// SessionExpiry returns when the SSO session and the EKS token of the selected
+// cluster expire; either is nil when it cannot be determined
+func (app *EKSLoginApp) SessionExpiry() (session, token *time.Time) {
+	// A web identity login has no SSO session
+	if app.webIdentity == nil {
+		if expiry, err := app.SSOSessionExpiry(); err == nil {
+			session = &expiry
+		}
+	}
+	if app.config.Cluster == "" {
+		return session, nil
+	}
+	if expiry, err := app.TokenExpiry(); err == nil {
+		token = &expiry
+	}
+	return session, token
+}
+
+// printExpiry prints the expiry lines of the summary and status
+func printExpiry(session, token *time.Time) {
+	if session != nil {
+		fmt.Printf("SSO session: %s\n", describeExpiry(*session, time.Hour))
+	}
+	if token != nil {
+		fmt.Printf("EKS token: %s, renewed by kubectl while the session lasts\n", describeExpiry(*token, 0))
+	}
+}
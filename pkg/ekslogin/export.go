@@ -0,0 +1,124 @@
+package ekslogin
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fileReferences maps kubeconfig fields that point at files to their inline counterparts
+var fileReferences = map[string]string{
+	"client-certificate": "client-certificate-data",
+	"client-key":         "client-key-data",
+}
+
+// Minify returns a standalone kubeconfig holding only the named context and
+// the cluster and user it references, with referenced files inlined.
+func (k *Kubeconfig) Minify(name string) (*Kubeconfig, error) {
+	context := k.Context(name)
+	if context == nil {
+		return nil, fmt.Errorf("context %q not found", name)
+	}
+	cluster := k.Cluster(context.Cluster)
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster %q of context %q not found", context.Cluster, name)
+	}
+	user := k.User(context.User)
+	if user == nil {
+		return nil, fmt.Errorf("user %q of context %q not found", context.User, name)
+	}
+
+	flatCluster := *cluster
+	if flatCluster.CertificateAuthority != "" {
+		data, err := os.ReadFile(flatCluster.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inline certificate authority: %w", err)
+		}
+		flatCluster.CertificateAuthorityData = base64.StdEncoding.EncodeToString(data)
+		flatCluster.CertificateAuthority = ""
+	}
+
+	flatUser := *user
+	flatUser.Extra = make(map[string]interface{}, len(user.Extra))
+	for key, value := range user.Extra {
+		inline, ok := fileReferences[key]
+		path, isPath := value.(string)
+		if !ok || !isPath {
+			flatUser.Extra[key] = value
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inline %s: %w", key, err)
+		}
+		flatUser.Extra[inline] = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return &Kubeconfig{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		Preferences:    map[string]interface{}{},
+		Clusters:       []NamedKubeCluster{{Name: context.Cluster, Cluster: flatCluster}},
+		Contexts:       []NamedKubeContext{{Name: name, Context: *context}},
+		Users:          []NamedKubeUser{{Name: context.User, User: flatUser}},
+		CurrentContext: name,
+	}, nil
+}
+
+// Export writes a standalone kubeconfig for one context to output, or stdout when output is empty
+func (app *EKSLoginApp) Export(contextName, output string) error {
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return err
+	}
+
+	if contextName == "" {
+		contextName = kubeconfig.CurrentContext
+	}
+	if contextName == "" {
+		return fmt.Errorf("no current context; pass --context")
+	}
+
+	minified, err := kubeconfig.Minify(contextName)
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		if err := minified.Save(output); err != nil {
+			return err
+		}
+		green.Printf("✓ Exported context %s to %s\n", contextName, output)
+		return nil
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(minified)
+}
+
+// newExportCmd creates the export subcommand
+func newExportCmd(app *EKSLoginApp) *cobra.Command {
+	var contextName, output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write a standalone kubeconfig containing a single context",
+		Long: `Write a standalone kubeconfig containing only one context, its cluster and its
+user, with referenced certificate files inlined (like kubectl config view --minify --flatten).
+
+The exported user still authenticates through "aws eks get-token", so the
+recipient needs AWS credentials that are mapped into the cluster.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.Export(contextName, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&contextName, "context", "", "Context to export (defaults to the current context)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+	return cmd
+}
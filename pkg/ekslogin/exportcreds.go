@@ -0,0 +1,126 @@
+package ekslogin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// credentialsSource names where exported credentials came from, for the
+// comment written with them
+func (app *EKSLoginApp) credentialsSource() string {
+	if app.webIdentity != nil {
+		return "role " + app.webIdentity.RoleARN
+	}
+	return "profile " + app.config.Profile
+}
+
+// ExportCredentialsToProfile writes the temporary credentials of the selected
+// profile to a named profile of the shared credentials file, for tools that
+// cannot use SSO or credential_process. A profile holding long-term keys is
+// only replaced with --force.
+func (app *EKSLoginApp) ExportCredentialsToProfile(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if name == app.config.Profile {
+		return usageError("--to-profile %s would replace the profile the credentials come from", name)
+	}
+	path := awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	if path == "" {
+		return fmt.Errorf("unable to locate the AWS credentials file")
+	}
+	existing, err := readINI(path)
+	if err != nil {
+		return err
+	}
+	if values, ok := existing[name]; ok && values["aws_access_key_id"] != "" && values["aws_session_token"] == "" && !app.config.Force {
+		return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf(
+			"profile %s in %s holds long-term access keys; pass --force to replace them", name, path))
+	}
+
+	if err := app.Authenticate(); err != nil {
+		return err
+	}
+
+	// A web identity login already holds the role's credentials
+	creds := app.credentials
+	if creds == nil {
+		spinner := app.StartSpinner("Resolving credentials")
+		creds, err = app.ExportCredentials()
+		spinner.Stop()
+	}
+	if err != nil {
+		return err
+	}
+	if creds.SessionToken == "" {
+		return fmt.Errorf("%s does not use temporary credentials; there is nothing to export", app.credentialsSource())
+	}
+
+	expiry := "an unknown time"
+	if expires, err := time.Parse(time.RFC3339, creds.Expiration); err == nil {
+		expiry = expires.UTC().Format(time.RFC3339)
+	}
+	values := map[string]string{
+		"aws_access_key_id":     creds.AccessKeyID,
+		"aws_secret_access_key": creds.SecretAccessKey,
+		"aws_session_token":     creds.SessionToken,
+	}
+	comment := fmt.Sprintf("Temporary credentials of %s written by eks-login; they expire at %s", app.credentialsSource(), expiry)
+	if err := setINISection(path, name, values, comment); err != nil {
+		return err
+	}
+
+	green.Printf("✓ Wrote the temporary credentials of %s to profile %s in %s\n", app.credentialsSource(), name, path)
+	if expires, err := time.Parse(time.RFC3339, creds.Expiration); err == nil {
+		cyan.Printf("   They expire at %s (in %s); run the command again to renew them\n",
+			expires.Local().Format("2006-01-02 15:04:05"), time.Until(expires).Round(time.Minute))
+	}
+	cyan.Printf("   Use them with: export AWS_PROFILE=%s\n", name)
+	return nil
+}
+
+// validateProfileName rejects profile names that do not fit in a section header
+func validateProfileName(name string) error {
+	if name == "" {
+		return usageError("--to-profile is required")
+	}
+	for _, r := range name {
+		if r == '[' || r == ']' || r == '\n' || r == '\r' || r == ' ' || r == '\t' {
+			return usageError("invalid profile name %q", name)
+		}
+	}
+	return nil
+}
+
+// newExportCredsCmd creates the export-creds subcommand
+func newExportCredsCmd(app *EKSLoginApp) *cobra.Command {
+	var toProfile string
+
+	cmd := &cobra.Command{
+		Use:   "export-creds",
+		Short: "Write temporary AWS credentials to a named profile",
+		Long: `Write the temporary credentials of --profile (logging in to SSO first if
+needed) to a named profile of the shared credentials file (~/.aws/credentials,
+or AWS_SHARED_CREDENTIALS_FILE), for tools that cannot use SSO profiles or
+credential_process. A comment in the profile records when the credentials
+expire; run the command again to renew them.
+
+The profile is replaced on every run. A profile holding long-term access keys
+is only replaced with --force.`,
+		Example: `  eks-login export-creds --profile prod-sso --to-profile temp-prod
+  AWS_PROFILE=temp-prod terraform plan`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := app.ExportCredentialsToProfile(toProfile)
+			app.Audit("export-creds", err)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&toProfile, "to-profile", "", "Profile of the shared credentials file to write the credentials to")
+	cmd.Flags().BoolVar(&app.config.Force, "force", false, "Replace the profile even if it holds long-term access keys")
+	cmd.RegisterFlagCompletionFunc("to-profile", cobra.NoFileCompletions)
+	return cmd
+}
@@ -0,0 +1,147 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// externalPrefix names the executables on PATH exposed as subcommands, like
+// kubectl and git plugins: eks-login-foo runs as 'eks-login foo'
+const externalPrefix = "eks-login-"
+
+// externalCommandName returns the subcommand an executable on PATH provides, or ""
+func externalCommandName(dir string, entry os.DirEntry) string {
+	name := entry.Name()
+	if !strings.HasPrefix(name, externalPrefix) || entry.IsDir() {
+		return ""
+	}
+	name = strings.TrimPrefix(name, externalPrefix)
+
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return ""
+		}
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+		return ""
+	}
+	return name
+}
+
+// ExternalCommands returns the eks-login-* executables on PATH by subcommand
+// name. The first one on PATH wins, as for any command.
+func ExternalCommands() map[string]string {
+	commands := make(map[string]string)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := externalCommandName(dir, entry)
+			if name == "" {
+				continue
+			}
+			if _, found := commands[name]; !found {
+				commands[name] = filepath.Join(dir, entry.Name())
+			}
+		}
+	}
+	return commands
+}
+
+// externalEnv returns the environment of an external command: the profile,
+// region and cluster from the flags and config file, or else from the current
+// kubeconfig context
+func (app *EKSLoginApp) externalEnv() []string {
+	target := app
+	currentContext := ""
+	if kubeconfig, err := LoadKubeconfig(KubeconfigPath()); err == nil {
+		currentContext = kubeconfig.CurrentContext
+		if app.config.Profile == "" && currentContext != "" {
+			if contextTarget, err := app.contextTarget(kubeconfig, currentContext); err == nil {
+				target = contextTarget
+			}
+		}
+	}
+
+	env := target.hookEnv()
+	if target.config.Namespace != "" {
+		env = append(env, "EKS_LOGIN_NAMESPACE="+target.config.Namespace)
+	}
+	if currentContext != "" {
+		env = append(env, "EKS_LOGIN_CONTEXT="+currentContext)
+	}
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, "EKS_LOGIN_BIN="+executable)
+	}
+	return env
+}
+
+// RunExternal runs an external command with args, passing its exit status through
+func (app *EKSLoginApp) RunExternal(name, path string, args []string) error {
+	err := app.run(app.context(), Command{
+		Name:   path,
+		Args:   args,
+		Env:    app.externalEnv(),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return withExitCode(exitErr.ExitCode(), "external_command_failed", fmt.Errorf("%s exited with status %d", externalPrefix+name, exitErr.ExitCode()))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %w", path, err)
+	}
+	return nil
+}
+
+// addExternalCommands registers the eks-login-* executables on PATH that do not
+// shadow a built-in command. Their arguments and flags are passed on untouched.
+func addExternalCommands(rootCmd *cobra.Command, app *EKSLoginApp) {
+	builtins := map[string]bool{"help": true, "completion": true}
+	for _, cmd := range rootCmd.Commands() {
+		builtins[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			builtins[alias] = true
+		}
+	}
+
+	commands := ExternalCommands()
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		if !builtins[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		name, path := name, commands[name]
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              "Plugin: " + path,
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return app.RunExternal(name, path, args)
+			},
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxNotReadyListed caps the NotReady nodes named in the health summary
+const maxNotReadyListed = 3
+
+// NodeList is the subset of the Kubernetes /api/v1/nodes response eks-login reads
+type NodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// ClusterHealth summarizes the state of a cluster right after login
+type ClusterHealth struct {
+	Latency  time.Duration
+	Nodes    int
+	NotReady []string
+	// NodesErr is set when the nodes could not be listed, e.g. without RBAC permission
+	NodesErr error
+}
+
+// Nodes lists the cluster's nodes
+func (c *ClusterClient) Nodes() (*NodeList, error) {
+	body, _, err := c.Get("/api/v1/nodes")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes NodeList
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse node list: %w", err)
+	}
+	return &nodes, nil
+}
+
+// Health checks node readiness; latency is the measured API round trip
+func (c *ClusterClient) Health(latency time.Duration) *ClusterHealth {
+	health := &ClusterHealth{Latency: latency}
+
+	nodes, err := c.Nodes()
+	if err != nil {
+		health.NodesErr = err
+		return health
+	}
+
+	health.Nodes = len(nodes.Items)
+	for _, node := range nodes.Items {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == "Ready" {
+				ready = condition.Status == "True"
+			}
+		}
+		if !ready {
+			health.NotReady = append(health.NotReady, node.Metadata.Name)
+		}
+	}
+	return health
+}
+
+// Summary renders the health as one line
+func (h *ClusterHealth) Summary() string {
+	parts := []string{fmt.Sprintf("API latency %s", h.Latency.Round(time.Millisecond))}
+
+	var apiErr *APIError
+	switch {
+	case errors.As(h.NodesErr, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		parts = append(parts, "nodes not visible (no permission to list nodes)")
+	case h.NodesErr != nil:
+		parts = append(parts, fmt.Sprintf("nodes unknown (%v)", h.NodesErr))
+	case h.Nodes == 0:
+		parts = append(parts, "no nodes")
+	default:
+		nodes := fmt.Sprintf("%d/%d nodes Ready", h.Nodes-len(h.NotReady), h.Nodes)
+		if len(h.NotReady) > 0 {
+			names := h.NotReady
+			if len(names) > maxNotReadyListed {
+				names = append(names[:maxNotReadyListed:maxNotReadyListed], fmt.Sprintf("+%d more", len(h.NotReady)-maxNotReadyListed))
+			}
+			nodes += fmt.Sprintf(" (NotReady: %s)", strings.Join(names, ", "))
+		}
+		parts = append(parts, nodes)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Healthy reports whether every node is Ready
+func (h *ClusterHealth) Healthy() bool {
+	return h.NodesErr == nil && h.Nodes > 0 && len(h.NotReady) == 0
+}
+
+// wantsHealthCheck reports whether the post-login health check is enabled
+func (app *EKSLoginApp) wantsHealthCheck() bool {
+	return app.config.HealthCheck || app.settings.Default.HealthCheck
+}
+
+// Print prints the health summary line, highlighted when something is wrong
+func (h *ClusterHealth) Print() {
+	if h.Healthy() {
+		green.Printf("Health: %s\n", h.Summary())
+	} else {
+		yellow.Printf("Health: %s\n", h.Summary())
+	}
+}
+
+// checkHealth checks the health of the connected cluster for the login summary
+func (app *EKSLoginApp) checkHealth(client *ClusterClient, latency time.Duration) {
+	spinner := app.StartSpinner("Checking node readiness")
+	app.health = client.Health(latency)
+	spinner.Stop()
+}
+
+// CheckHealth connects to the selected cluster and checks its health for the login summary
+func (app *EKSLoginApp) CheckHealth() {
+	client, err := app.NewClusterClient()
+	if err != nil {
+		yellow.Printf("⚠️  Unable to check cluster health: %v\n", err)
+		return
+	}
+	_, latency, err := client.ServerVersion()
+	if err != nil {
+		yellow.Printf("⚠️  Unable to check cluster health: %v\n", err)
+		return
+	}
+	app.checkHealth(client, latency)
+}
@@ -0,0 +1,153 @@
+package ekslogin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Hook is a user-defined command run at a fixed point of the login flow.
+// A non-zero exit status aborts the login.
+type Hook struct {
+	Name     string   `yaml:"name"`
+	Command  string   `yaml:"command"`
+	Message  string   `yaml:"message,omitempty"`
+	Profiles []string `yaml:"profiles,omitempty"`
+	Clusters []string `yaml:"clusters,omitempty"`
+}
+
+// HooksConfig groups hooks by the stage they run in
+type HooksConfig struct {
+	// PreLogin hooks run once the profile is known, before SSO login
+	PreLogin []Hook `yaml:"pre_login,omitempty"`
+	// PreKubeconfig hooks run once the cluster is known, before kubeconfig is written
+	PreKubeconfig []Hook `yaml:"pre_kubeconfig,omitempty"`
+}
+
+// matchesAny reports whether value matches one of the glob patterns.
+// An empty pattern list matches everything.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mostSpecificMatch returns the value of the glob in globs that matches value
+// most specifically: the one with the most literal characters, so "prod-eu-*"
+// wins over "prod-*" and "*" whatever their order. Equally specific globs are
+// tried in alphabetical order.
+func mostSpecificMatch(globs map[string]string, value string) (string, bool) {
+	best, bestLiterals := "", -1
+	for pattern := range globs {
+		if ok, _ := path.Match(pattern, value); !ok {
+			continue
+		}
+		literals := globLiterals(pattern)
+		if literals > bestLiterals || literals == bestLiterals && pattern < best {
+			best, bestLiterals = pattern, literals
+		}
+	}
+	if bestLiterals < 0 {
+		return "", false
+	}
+	return globs[best], true
+}
+
+// globLiterals counts the characters of a glob that match only themselves
+func globLiterals(pattern string) int {
+	literals := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?':
+		case '[':
+			// A character class matches one of several characters
+			if end := strings.IndexByte(pattern[i:], ']'); end > 0 {
+				i += end
+			}
+		case '\\':
+			i++
+			literals++
+		default:
+			literals++
+		}
+	}
+	return literals
+}
+
+// shellCommand builds a command that runs script through the platform shell
+func shellCommand(script string) Command {
+	if runtime.GOOS == "windows" {
+		return Command{Name: "cmd", Args: []string{"/C", script}}
+	}
+	return Command{Name: "sh", Args: []string{"-c", script}}
+}
+
+// hookEnv returns the environment passed to hooks and other child tools: that
+// of the AWS CLI calls, so web-identity and organization role credentials
+// apply, plus the selection
+func (app *EKSLoginApp) hookEnv() []string {
+	env := app.commandEnv()
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(env,
+		"EKS_LOGIN_PROFILE="+app.config.Profile,
+		"EKS_LOGIN_REGION="+app.config.Region,
+		"EKS_LOGIN_CLUSTER="+app.config.Cluster,
+	)
+}
+
+// RunHooks executes the hooks of a stage that apply to the current selection
+func (app *EKSLoginApp) RunHooks(stage string, hooks []Hook) error {
+	for _, hook := range hooks {
+		if !matchesAny(hook.Profiles, app.config.Profile) {
+			continue
+		}
+		if !matchesAny(hook.Clusters, app.config.Cluster) {
+			continue
+		}
+
+		name := hook.Name
+		if name == "" {
+			name = hook.Command
+		}
+		cmd := shellCommand(hook.Command)
+		if app.config.DryRun {
+			blue.Printf("🪝 Would run %s hook: %s\n", stage, name)
+			printDryRunCommand(cmd.Name, cmd.Args...)
+			continue
+		}
+		blue.Printf("🪝 Running %s hook: %s\n", stage, name)
+
+		ctx, cancel := app.withTimeout(app.timeout())
+		var output bytes.Buffer
+		cmd.Env = app.hookEnv()
+		cmd.Stdout, cmd.Stderr = &output, &output
+		err := app.run(ctx, cmd)
+		cancel()
+		if err != nil {
+			err = timeoutError(ctx, "hook", app.timeout(), err)
+			message := hook.Message
+			if message == "" {
+				message = strings.TrimSpace(output.String())
+			}
+			if message == "" {
+				message = err.Error()
+			}
+			return withExitCode(ExitHookAborted, "hook_aborted", fmt.Errorf("%s hook '%s' aborted the login: %s", stage, name, message))
+		}
+
+		green.Printf("  ✓ %s passed\n", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package ekslogin
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRunHooksPassesCommandCredentials(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	recorder := &commandRecorder{}
+	app.SetExecutor(recorder)
+	app.config.Cluster = "prod"
+	app.credentials = &AWSCredentials{AccessKeyID: "AKIDROLE", SecretAccessKey: "secret", SessionToken: "token"}
+
+	if err := app.RunHooks("pre-kubeconfig", []Hook{{Name: "check", Command: "true"}}); err != nil {
+		t.Fatalf("RunHooks: %v", err)
+	}
+	for _, want := range []string{
+		"AWS_ACCESS_KEY_ID=AKIDROLE",
+		"AWS_SESSION_TOKEN=token",
+		"EKS_LOGIN_PROFILE=dev",
+		"EKS_LOGIN_CLUSTER=prod",
+	} {
+		if !slices.Contains(recorder.command.Env, want) {
+			t.Errorf("hook environment lacks %s", want)
+		}
+	}
+}
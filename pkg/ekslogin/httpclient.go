@@ -0,0 +1,51 @@
+package ekslogin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// caBundlePath returns the custom CA bundle from --ca-bundle or the config file
+func (app *EKSLoginApp) caBundlePath() string {
+	if app.config.CABundle != "" {
+		return app.config.CABundle
+	}
+	return app.settings.CABundle
+}
+
+// HTTPClient returns an HTTP client that honors HTTPS_PROXY/NO_PROXY and trusts the
+// system roots, the configured CA bundle and any extra PEM certificates
+func (app *EKSLoginApp) HTTPClient(timeout time.Duration, extraPEM ...[]byte) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if path := app.caBundlePath(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("CA bundle %s contains no certificates", path)
+		}
+	}
+
+	for _, pem := range extraPEM {
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("certificate data contains no certificates")
+		}
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}, nil
+}
@@ -0,0 +1,177 @@
+package ekslogin
+
+import (
+	"encoding/csv"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// InventoryEntry describes one cluster found while scanning profiles and regions
+type InventoryEntry struct {
+	Profile         string `json:"profile"`
+	Account         string `json:"account"`
+	AccountName     string `json:"accountName,omitempty"`
+	RoleARN         string `json:"roleArn,omitempty"`
+	Region          string `json:"region"`
+	Cluster         string `json:"cluster"`
+	Version         string `json:"version"`
+	PlatformVersion string `json:"platformVersion"`
+	Status          string `json:"status"`
+}
+
+// forTarget returns a copy of the app pointed at another profile, region and cluster
+func (app *EKSLoginApp) forTarget(profile, region, cluster string) *EKSLoginApp {
+	config := *app.config
+	config.Profile = profile
+	config.Region = region
+	config.Cluster = cluster
+
+	clone := *app
+	clone.config = &config
+	return &clone
+}
+
+// ensureSession logs in to SSO when the profile's session is not valid
+func (app *EKSLoginApp) ensureSession() error {
+	if valid, _ := app.CheckSSOSession(); valid {
+		return nil
+	}
+	return app.LoginSSO()
+}
+
+// Inventory lists the clusters of every profile in the given regions.
+// An empty region list scans each profile's default region. Sessions are
+// checked one by one, as they may prompt for a login; regions and clusters
+// are then listed, reporting progress, and described up to --concurrency at once.
+func (app *EKSLoginApp) Inventory(regions []string) ([]InventoryEntry, error) {
+	profiles, err := app.FilteredProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*EKSLoginApp
+	var accounts []string
+	var skipped []scanFailure
+	for _, profile := range profiles {
+		target := app.forTarget(profile.Name, profile.Region, "")
+		if err := target.ensureSession(); err != nil {
+			yellow.Printf("⚠️  Skipping profile %s: %v\n", profile.Name, err)
+			skipped = append(skipped, scanFailure{Target: profile.Name, Err: err})
+			continue
+		}
+
+		account, err := target.GetAccountID()
+		if err != nil {
+			yellow.Printf("⚠️  Skipping profile %s: %v\n", profile.Name, err)
+			skipped = append(skipped, scanFailure{Target: profile.Name, Err: err})
+			continue
+		}
+
+		profileRegions := regions
+		if len(profileRegions) == 0 {
+			profileRegions = []string{profile.Region}
+		}
+		for _, region := range profileRegions {
+			targets = append(targets, app.forTarget(profile.Name, region, ""))
+			accounts = append(accounts, account)
+		}
+	}
+
+	found, errs := app.scanTargets(targets, skipped)
+
+	var entries []InventoryEntry
+	var clusters []*EKSLoginApp
+	for i, target := range targets {
+		if errs[i] != nil {
+			continue
+		}
+		for _, cluster := range found[i] {
+			entries = append(entries, InventoryEntry{
+				Profile: target.config.Profile,
+				Account: accounts[i],
+				Region:  target.config.Region,
+				Cluster: cluster,
+			})
+			clusters = append(clusters, app.forTarget(target.config.Profile, target.config.Region, cluster))
+		}
+	}
+
+	app.describeEntries(entries, clusters)
+	return entries, nil
+}
+
+// describeEntries fills in the version and status of each entry, describing
+// the cluster of targets[i] for entries[i]
+func (app *EKSLoginApp) describeEntries(entries []InventoryEntry, targets []*EKSLoginApp) {
+	app.forEach(len(entries), func(i int) {
+		if details, err := targets[i].DescribeCluster(); err == nil {
+			entries[i].Version = details.Version
+			entries[i].PlatformVersion = details.PlatformVersion
+			entries[i].Status = details.Status
+		}
+	})
+}
+
+// writeInventory renders inventory entries as a table, YAML, JSON or CSV
+func writeInventory(entries []InventoryEntry, output string) error {
+	switch output {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"profile", "account", "account_name", "region", "cluster", "version", "platform_version", "status"})
+		for _, e := range entries {
+			w.Write([]string{e.Profile, e.Account, e.AccountName, e.Region, e.Cluster, e.Version, e.PlatformVersion, e.Status})
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return printOutput(output, entries, func() error {
+			table := NewTable("", "PROFILE", "ACCOUNT", "REGION", "CLUSTER", "VERSION", "STATUS")
+			for _, e := range entries {
+				table.Row(e.Profile, e.Account, e.Region, e.Cluster, e.Version, e.Status)
+			}
+			return table.Write(os.Stdout)
+		})
+	}
+}
+
+func newInventoryCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+	var regions []string
+	var orgRole string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export the clusters of all configured profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.CheckDependencies(); err != nil {
+				return err
+			}
+
+			if len(regions) == 0 {
+				regions = app.regions()
+			}
+
+			var entries []InventoryEntry
+			var err error
+			if orgRole != "" {
+				if err := app.Authenticate(); err != nil {
+					return err
+				}
+				entries, err = app.DiscoverOrgClusters(orgRole, regions)
+			} else {
+				entries, err = app.Inventory(regions)
+			}
+			if err != nil {
+				return err
+			}
+			return writeInventory(entries, output)
+		},
+	}
+
+	addOutputFlag(cmd, &output, "csv")
+	cmd.Flags().StringSliceVar(&regions, "regions", nil, "Regions to scan (default: each profile's region)")
+	cmd.Flags().MarkDeprecated("regions", "use --region, which can be repeated or comma-separated")
+	addOrgFlags(cmd, &orgRole)
+	return cmd
+}
@@ -0,0 +1,215 @@
+package ekslogin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// credentialRefreshMargin is how long before expiry cached credentials are renewed
+const credentialRefreshMargin = 5 * time.Minute
+
+// credentialCache keeps tokens and credentials until shortly before they expire,
+// so local clients can poll without spawning the AWS CLI each time
+type credentialCache struct {
+	mu          sync.Mutex
+	tokens      map[string]*ExecCredential
+	credentials map[string]*AWSCredentials
+}
+
+func newCredentialCache() *credentialCache {
+	return &credentialCache{
+		tokens:      make(map[string]*ExecCredential),
+		credentials: make(map[string]*AWSCredentials),
+	}
+}
+
+// fresh reports whether a credential expiring at expiration can still be handed out
+func fresh(expiration string) bool {
+	expiry, err := time.Parse(time.RFC3339, expiration)
+	return err == nil && time.Until(expiry) > credentialRefreshMargin
+}
+
+// Token returns an EKS token for the cluster, generating one when needed
+func (c *credentialCache) Token(app *EKSLoginApp, profile, region, cluster string) (*ExecCredential, error) {
+	key := profile + "/" + region + "/" + cluster
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if token := c.tokens[key]; token != nil && fresh(token.Status.ExpirationTimestamp) {
+		return token, nil
+	}
+	token, err := app.forTarget(profile, region, cluster).GetClusterToken()
+	if err != nil {
+		return nil, err
+	}
+	c.tokens[key] = token
+	return token, nil
+}
+
+// Credentials returns temporary AWS credentials of the profile, exporting them when needed
+func (c *credentialCache) Credentials(app *EKSLoginApp, profile string) (*AWSCredentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if creds := c.credentials[profile]; creds != nil && fresh(creds.Expiration) {
+		return creds, nil
+	}
+	creds, err := app.forTarget(profile, app.config.Region, "").ExportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	c.credentials[profile] = creds
+	return creds, nil
+}
+
+// writeJSON writes value as the JSON body of a response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes an error response in the shape {"error": "..."}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// ipcHandler serves tokens and credentials to local clients:
+//
+//	GET /token?cluster=X[&profile=P][&region=R]  ExecCredential, as printed by aws eks get-token
+//	GET /credentials[?profile=P]                 credentials in credential_process format
+func (app *EKSLoginApp) ipcHandler(cache *credentialCache) http.Handler {
+	profileOf := func(r *http.Request) (string, error) {
+		profile := r.URL.Query().Get("profile")
+		if profile == "" {
+			profile = app.config.Profile
+		}
+		if profile == "" {
+			return "", errors.New("missing profile parameter")
+		}
+		return profile, nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /token", func(w http.ResponseWriter, r *http.Request) {
+		profile, err := profileOf(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		cluster := r.URL.Query().Get("cluster")
+		if cluster == "" {
+			writeError(w, http.StatusBadRequest, errors.New("missing cluster parameter"))
+			return
+		}
+		region := r.URL.Query().Get("region")
+		if region == "" {
+			region = app.config.Region
+		}
+
+		token, err := cache.Token(app, profile, region, cluster)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, token)
+	})
+	mux.HandleFunc("GET /credentials", func(w http.ResponseWriter, r *http.Request) {
+		profile, err := profileOf(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		creds, err := cache.Credentials(app, profile)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, struct {
+			Version int `json:"Version"`
+			*AWSCredentials
+		}{1, creds})
+	})
+	return mux
+}
+
+// serveUntilDone serves handler on listener until the app's context is cancelled
+func (app *EKSLoginApp) serveUntilDone(listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-app.context().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), interruptGrace)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	err := server.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		<-done
+		return nil
+	}
+	return err
+}
+
+// ServeIPC serves tokens and credentials on a local socket until interrupted
+func (app *EKSLoginApp) ServeIPC(address string) error {
+	listener, err := listenIPC(address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	blue.Printf("🔌 Serving tokens and credentials on %s\n", address)
+	return app.serveUntilDone(listener, app.ipcHandler(newCredentialCache()))
+}
+
+// newServeCmd creates the serve subcommand
+func newServeCmd(app *EKSLoginApp) *cobra.Command {
+	var address, listen string
+	var ecs bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve EKS tokens and AWS credentials to local tools over a socket",
+		Long: fmt.Sprintf(`Serve EKS tokens and AWS credentials over HTTP on a local socket only
+the current user can open (a named pipe on Windows), so editors, Terraform
+wrappers and internal CLIs can reuse eks-login's SSO sessions:
+
+  GET /token?cluster=X[&profile=P][&region=R]   ExecCredential (like aws eks get-token)
+  GET /credentials[?profile=P]                  credentials in credential_process format
+
+--profile and --region supply the defaults. Results are cached until shortly
+before they expire.
+
+Default socket: %s
+
+With --ecs, the credentials of --profile are instead served on a loopback TCP
+address in the ECS container credentials format, for SDKs and containers that
+read AWS_CONTAINER_CREDENTIALS_FULL_URI and AWS_CONTAINER_AUTHORIZATION_TOKEN.`, defaultIPCAddress()),
+		Example: `  curl --unix-socket ~/.cache/eks-login/eks-login.sock 'http://localhost/token?cluster=dev&profile=my-sso'
+  eks-login serve --ecs --profile my-sso --listen 127.0.0.1:9911`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ecs {
+				return app.ServeContainerCredentials(listen)
+			}
+			return app.ServeIPC(address)
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "socket", defaultIPCAddress(), "Socket path (pipe name on Windows) to listen on")
+	cmd.Flags().BoolVar(&ecs, "ecs", false, "Serve --profile's credentials over the ECS container credentials interface instead")
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:0", "Loopback address of the --ecs endpoint (port 0 picks a free one)")
+	return cmd
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package ekslogin
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// pipeSecurity grants the pipe's owner (the current user) exclusive access
+const pipeSecurity = "D:P(A;;GA;;;OW)"
+
+// defaultIPCAddress returns the default pipe name of 'eks-login serve'
+func defaultIPCAddress() string {
+	return `\\.\pipe\eks-login-` + os.Getenv("USERNAME")
+}
+
+// listenIPC listens on a named pipe that only the current user can connect to
+func listenIPC(address string) (net.Listener, error) {
+	listener, err := winio.ListenPipe(address, &winio.PipeConfig{SecurityDescriptor: pipeSecurity})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return listener, nil
+}
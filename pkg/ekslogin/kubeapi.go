@@ -0,0 +1,171 @@
+package ekslogin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ExecCredential represents the token returned by eks get-token
+type ExecCredential struct {
+	Kind       string   `json:"kind,omitempty"`
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Spec       struct{} `json:"spec"`
+	Status     struct {
+		Token               string `json:"token"`
+		ExpirationTimestamp string `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+// ServerVersion represents the response of the Kubernetes /version endpoint
+type ServerVersion struct {
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	GitVersion string `json:"gitVersion"`
+	Platform   string `json:"platform"`
+}
+
+// APIError is returned when the API server answers with a non-200 status
+type APIError struct {
+	Path       string
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GET %s: %s", e.Path, e.Status)
+}
+
+// ClusterClient is a minimal Kubernetes API client authenticated with an EKS
+// token. eks-login only needs a few GET requests, so it uses net/http rather
+// than client-go and its dependency tree; like kubectl, it trusts the CA of
+// the cluster and connects through the proxy-url of its kubeconfig entry.
+type ClusterClient struct {
+	ctx      context.Context
+	endpoint string
+	token    string
+	http     *http.Client
+}
+
+// GetClusterToken generates a bearer token for the selected cluster
+func (app *EKSLoginApp) GetClusterToken() (*ExecCredential, error) {
+	if app.awsV1() {
+		return app.GenerateToken(TokenOptions{APIVersion: execCredentialAPIVersion})
+	}
+	output, err := app.AWS("eks", "get-token",
+		"--cluster-name", app.config.Cluster,
+		"--region", app.config.Region,
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for cluster %s: %w", app.config.Cluster, err)
+	}
+
+	var credential ExecCredential
+	if err := json.Unmarshal([]byte(output), &credential); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster token: %w", err)
+	}
+
+	return &credential, nil
+}
+
+// NewClusterClient builds an API client for the selected cluster
+func (app *EKSLoginApp) NewClusterClient() (*ClusterClient, error) {
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return nil, err
+	}
+	if err := app.probeEndpoint(details); err != nil {
+		return nil, err
+	}
+
+	credential, err := app.GetClusterToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return app.clusterClient(details, credential.Status.Token)
+}
+
+// clusterClient builds an API client for a described cluster and bearer token
+func (app *EKSLoginApp) clusterClient(details *ClusterDetails, token string) (*ClusterClient, error) {
+	caData, err := base64.StdEncoding.DecodeString(details.CertificateAuthority.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cluster CA: %w", err)
+	}
+
+	client, err := app.HTTPClient(10*time.Second, caData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster CA: %w", err)
+	}
+	if err := app.useProxy(client, details.Endpoint); err != nil {
+		return nil, err
+	}
+
+	return &ClusterClient{
+		ctx:      app.context(),
+		endpoint: strings.TrimSuffix(details.Endpoint, "/"),
+		token:    token,
+		http:     client,
+	}, nil
+}
+
+// Get requests path from the API server and returns the body and round-trip latency
+func (c *ClusterClient) Get(path string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, latency, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, latency, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return body, latency, &APIError{Path: path, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return body, latency, nil
+}
+
+// ServerVersion queries /version
+func (c *ClusterClient) ServerVersion() (*ServerVersion, time.Duration, error) {
+	body, latency, err := c.Get("/version")
+	if err != nil {
+		return nil, latency, err
+	}
+
+	var version ServerVersion
+	if err := json.Unmarshal(body, &version); err != nil {
+		return nil, latency, fmt.Errorf("failed to parse server version: %w", err)
+	}
+
+	return &version, latency, nil
+}
+
+// Healthz queries /healthz
+func (c *ClusterClient) Healthz() error {
+	body, _, err := c.Get("/healthz")
+	if err != nil {
+		return err
+	}
+	if status := strings.TrimSpace(string(body)); status != "ok" {
+		return fmt.Errorf("healthz reported: %s", status)
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package ekslogin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubeconfig is the subset of a kubeconfig file eks-login reads and edits.
+// Unknown fields are preserved through the inline Extra maps.
+type Kubeconfig struct {
+	APIVersion     string                 `yaml:"apiVersion"`
+	Kind           string                 `yaml:"kind"`
+	Preferences    map[string]interface{} `yaml:"preferences"`
+	Clusters       []NamedKubeCluster     `yaml:"clusters"`
+	Contexts       []NamedKubeContext     `yaml:"contexts"`
+	Users          []NamedKubeUser        `yaml:"users"`
+	CurrentContext string                 `yaml:"current-context"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+// NamedKubeCluster is a cluster entry of a kubeconfig
+type NamedKubeCluster struct {
+	Name    string      `yaml:"name"`
+	Cluster KubeCluster `yaml:"cluster"`
+}
+
+// KubeCluster holds the connection details of a cluster entry
+type KubeCluster struct {
+	Server                   string                 `yaml:"server,omitempty"`
+	CertificateAuthorityData string                 `yaml:"certificate-authority-data,omitempty"`
+	CertificateAuthority     string                 `yaml:"certificate-authority,omitempty"`
+	TLSServerName            string                 `yaml:"tls-server-name,omitempty"`
+	ProxyURL                 string                 `yaml:"proxy-url,omitempty"`
+	Extra                    map[string]interface{} `yaml:",inline"`
+}
+
+// NamedKubeContext is a context entry of a kubeconfig
+type NamedKubeContext struct {
+	Name    string      `yaml:"name"`
+	Context KubeContext `yaml:"context"`
+}
+
+// KubeContext ties a cluster entry to a user entry
+type KubeContext struct {
+	Cluster    string                 `yaml:"cluster"`
+	User       string                 `yaml:"user"`
+	Namespace  string                 `yaml:"namespace,omitempty"`
+	Extensions []NamedExtension       `yaml:"extensions,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+// NamedExtension is an extension entry of a kubeconfig object
+type NamedExtension struct {
+	Name      string    `yaml:"name"`
+	Extension yaml.Node `yaml:"extension"`
+}
+
+// kubeconfigExtension names the extension eks-login records on the contexts it owns
+const kubeconfigExtension = "eks-login"
+
+// LoginMetadata is recorded on each context eks-login writes
+type LoginMetadata struct {
+	Profile string `yaml:"profile"`
+	Region  string `yaml:"region"`
+	Account string `yaml:"account,omitempty"`
+	Cluster string `yaml:"cluster"`
+	RoleARN string `yaml:"role-arn,omitempty"`
+	// RoleDuration is the session duration requested for the role, e.g. "8h"
+	RoleDuration string    `yaml:"role-duration,omitempty"`
+	CreatedAt    time.Time `yaml:"created-at"`
+}
+
+// NamedKubeUser is a user entry of a kubeconfig
+type NamedKubeUser struct {
+	Name string   `yaml:"name"`
+	User KubeUser `yaml:"user"`
+}
+
+// KubeUser holds the credentials of a user entry
+type KubeUser struct {
+	Exec     *ExecConfig            `yaml:"exec,omitempty"`
+	As       string                 `yaml:"as,omitempty"`
+	AsGroups []string               `yaml:"as-groups,omitempty"`
+	Extra    map[string]interface{} `yaml:",inline"`
+}
+
+// ExecConfig is the exec credential plugin of a user entry
+type ExecConfig struct {
+	APIVersion string                 `yaml:"apiVersion,omitempty"`
+	Command    string                 `yaml:"command"`
+	Args       []string               `yaml:"args,omitempty"`
+	Env        []ExecEnvVar           `yaml:"env,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+// ExecEnvVar is an environment variable passed to an exec credential plugin
+type ExecEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// KubeconfigPath returns the kubeconfig file that eks-login writes to
+func KubeconfigPath() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		for _, path := range filepath.SplitList(env) {
+			if path != "" {
+				return path
+			}
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kube", "config")
+	}
+	return filepath.Join(home, ".kube", "config")
+}
+
+// LoadKubeconfig reads the kubeconfig at path. A missing file yields an empty kubeconfig.
+func LoadKubeconfig(path string) (*Kubeconfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return LoadKubeconfigData(nil)
+		}
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	kubeconfig, err := LoadKubeconfigData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	return kubeconfig, nil
+}
+
+// LoadKubeconfigData parses kubeconfig YAML
+func LoadKubeconfigData(data []byte) (*Kubeconfig, error) {
+	kubeconfig := &Kubeconfig{APIVersion: "v1", Kind: "Config"}
+	if err := yaml.Unmarshal(data, kubeconfig); err != nil {
+		return nil, err
+	}
+	return kubeconfig, nil
+}
+
+// Encode returns the kubeconfig as YAML, as Save writes it
+func (k *Kubeconfig) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(k); err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the kubeconfig to path, replacing the file atomically
+func (k *Kubeconfig) Save(path string) error {
+	data, err := k.Encode()
+	if err != nil {
+		return err
+	}
+	return writeKubeconfigFile(path, data)
+}
+
+// writeKubeconfigFile replaces the kubeconfig at path with data atomically, so
+// readers never see a partly written file
+func writeKubeconfigFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kubeconfig-*")
+	if err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// Context returns the named context, or nil if it does not exist
+func (k *Kubeconfig) Context(name string) *KubeContext {
+	for i := range k.Contexts {
+		if k.Contexts[i].Name == name {
+			return &k.Contexts[i].Context
+		}
+	}
+	return nil
+}
+
+// Cluster returns the named cluster entry, or nil if it does not exist
+func (k *Kubeconfig) Cluster(name string) *KubeCluster {
+	for i := range k.Clusters {
+		if k.Clusters[i].Name == name {
+			return &k.Clusters[i].Cluster
+		}
+	}
+	return nil
+}
+
+// User returns the named user entry, or nil if it does not exist
+func (k *Kubeconfig) User(name string) *KubeUser {
+	for i := range k.Users {
+		if k.Users[i].Name == name {
+			return &k.Users[i].User
+		}
+	}
+	return nil
+}
+
+// SetContext adds or replaces the named context
+func (k *Kubeconfig) SetContext(name string, context KubeContext) {
+	if existing := k.Context(name); existing != nil {
+		*existing = context
+		return
+	}
+	k.Contexts = append(k.Contexts, NamedKubeContext{Name: name, Context: context})
+}
+
+// Metadata returns the eks-login metadata of the context, or nil if eks-login does not own it
+func (c *KubeContext) Metadata() *LoginMetadata {
+	for _, extension := range c.Extensions {
+		if extension.Name != kubeconfigExtension {
+			continue
+		}
+		metadata := &LoginMetadata{}
+		if err := extension.Extension.Decode(metadata); err != nil {
+			return nil
+		}
+		return metadata
+	}
+	return nil
+}
+
+// SetMetadata records the eks-login metadata on the context
+func (c *KubeContext) SetMetadata(metadata LoginMetadata) error {
+	var node yaml.Node
+	if err := node.Encode(metadata); err != nil {
+		return fmt.Errorf("failed to encode context metadata: %w", err)
+	}
+
+	for i := range c.Extensions {
+		if c.Extensions[i].Name == kubeconfigExtension {
+			c.Extensions[i].Extension = node
+			return nil
+		}
+	}
+	c.Extensions = append(c.Extensions, NamedExtension{Name: kubeconfigExtension, Extension: node})
+	return nil
+}
+
+// SetCluster adds or replaces the named cluster entry
+func (k *Kubeconfig) SetCluster(name string, cluster KubeCluster) {
+	if existing := k.Cluster(name); existing != nil {
+		*existing = cluster
+		return
+	}
+	k.Clusters = append(k.Clusters, NamedKubeCluster{Name: name, Cluster: cluster})
+}
+
+// Merge adds or replaces the entries of other and takes over its current
+// context, as aws eks update-kubeconfig merges a cluster
+func (k *Kubeconfig) Merge(other *Kubeconfig) {
+	for _, cluster := range other.Clusters {
+		k.SetCluster(cluster.Name, cluster.Cluster)
+	}
+	for _, user := range other.Users {
+		k.SetUser(user.Name, user.User)
+	}
+	for _, context := range other.Contexts {
+		k.SetContext(context.Name, context.Context)
+	}
+	if other.CurrentContext != "" {
+		k.CurrentContext = other.CurrentContext
+	}
+}
+
+// SetUser adds or replaces the named user entry
+func (k *Kubeconfig) SetUser(name string, user KubeUser) {
+	if existing := k.User(name); existing != nil {
+		*existing = user
+		return
+	}
+	k.Users = append(k.Users, NamedKubeUser{Name: name, User: user})
+}
+
+// RecordMetadata tags the current context with the profile, region and account it was created for.
+// The caller saves the kubeconfig.
+func (app *EKSLoginApp) RecordMetadata(kubeconfig *Kubeconfig) error {
+	path := KubeconfigPath()
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, path)
+	}
+
+	metadata := LoginMetadata{
+		Profile:   app.config.Profile,
+		Region:    app.config.Region,
+		Cluster:   app.config.Cluster,
+		RoleARN:   app.config.RoleARN,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if app.config.RoleARN != "" {
+		metadata.RoleDuration = formatRoleDuration(app.roleDuration())
+	}
+	if clusterARN, err := ParseARN(context.Cluster); err == nil {
+		metadata.Account = clusterARN.AccountID
+	}
+
+	return context.SetMetadata(metadata)
+}
+
+// kubeconfigEntries returns the cluster, user and context entries of the
+// selected cluster as aws eks update-kubeconfig and eks-login write them,
+// named after the cluster ARN, with the context current
+func (app *EKSLoginApp) kubeconfigEntries(details *ClusterDetails, tokenExec string) (*Kubeconfig, error) {
+	name := details.Arn
+	if name == "" {
+		account, err := app.GetAccountID()
+		if err != nil {
+			return nil, err
+		}
+		name = ARN{
+			Partition: app.partition().ID,
+			Service:   "eks",
+			Region:    app.config.Region,
+			AccountID: account,
+			Resource:  "cluster/" + app.config.Cluster,
+		}.String()
+	}
+
+	stsRegion, err := app.stsRegion()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"--region", stsRegion, "eks", "get-token", "--cluster-name", app.config.Cluster, "--output", "json"}
+	if app.config.RoleARN != "" {
+		args = append(args, "--role", app.config.RoleARN)
+	}
+	exec := &ExecConfig{
+		APIVersion: execCredentialAPIVersion,
+		Command:    "aws",
+		Args:       args,
+		Env:        app.execEnv(),
+	}
+	if tokenExec == "eks-login" {
+		exec = app.tokenExecConfig(exec)
+	}
+
+	kubeconfig := &Kubeconfig{APIVersion: "v1", Kind: "Config", CurrentContext: name}
+	kubeconfig.Clusters = []NamedKubeCluster{{Name: name, Cluster: KubeCluster{
+		Server:                   details.Endpoint,
+		CertificateAuthorityData: details.CertificateAuthority.Data,
+		ProxyURL:                 app.proxyURL(),
+	}}}
+	user := app.userAlias(name)
+	kubeconfig.SetUser(user, KubeUser{Exec: exec})
+
+	context := KubeContext{Cluster: name, User: user, Namespace: app.config.Namespace}
+	metadata := LoginMetadata{
+		Profile:   app.config.Profile,
+		Region:    app.config.Region,
+		Cluster:   app.config.Cluster,
+		RoleARN:   app.config.RoleARN,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if app.config.RoleARN != "" {
+		metadata.RoleDuration = formatRoleDuration(app.roleDuration())
+	}
+	if arn, err := ParseARN(name); err == nil {
+		metadata.Account = arn.AccountID
+	}
+	if err := context.SetMetadata(metadata); err != nil {
+		return nil, err
+	}
+	kubeconfig.SetContext(name, context)
+	return kubeconfig, nil
+}
@@ -0,0 +1,117 @@
+package ekslogin
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// clusterEntries returns the entries update-kubeconfig writes for cluster in
+// account 111122223333, with its context current
+func clusterEntries(cluster string) *Kubeconfig {
+	arn := "arn:aws:eks:eu-west-1:111122223333:cluster/" + cluster
+	entries := &Kubeconfig{CurrentContext: arn}
+	entries.SetCluster(arn, KubeCluster{Server: "https://" + cluster + ".example.com"})
+	entries.SetUser(arn, KubeUser{Exec: &ExecConfig{Command: "aws", Args: []string{"eks", "get-token", "--cluster-name", cluster}}})
+	entries.SetContext(arn, KubeContext{Cluster: arn, User: arn})
+	return entries
+}
+
+// mergeLogin merges entries into the kubeconfig the way a login run does:
+// backed up first, saved once and recorded for undo when the run ends
+func mergeLogin(t *testing.T, app *EKSLoginApp, entries *Kubeconfig) {
+	t.Helper()
+	app.BackupKubeconfig()
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig.Merge(entries)
+	if err := kubeconfig.Save(KubeconfigPath()); err != nil {
+		t.Fatal(err)
+	}
+	app.RunCleanups()
+}
+
+func TestKubeconfigMergeUndoRoundTrip(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	original := testKubeconfig + "preferences:\n  colors: true\nx-team: platform\n"
+	path := writeTestKubeconfig(t, original)
+
+	mergeLogin(t, app, clusterEntries("prod"))
+	afterProd, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig, err := LoadKubeconfigData(afterProd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeconfig.Contexts) != 2 || !strings.HasSuffix(kubeconfig.CurrentContext, "cluster/prod") {
+		t.Fatalf("merged kubeconfig has contexts %v, current %s; want dev and prod, prod current", kubeconfig.Contexts, kubeconfig.CurrentContext)
+	}
+	if kubeconfig.Extra["x-team"] != "platform" || kubeconfig.Preferences["colors"] != true {
+		t.Errorf("merge dropped unknown fields: %v, preferences %v", kubeconfig.Extra, kubeconfig.Preferences)
+	}
+
+	// A second run backs up the kubeconfig the first one left
+	second := NewEKSLoginApp()
+	second.SetExecutor(&fakeExecutor{})
+	second.SetPrompter(&fakePrompter{})
+	mergeLogin(t, second, clusterEntries("staging"))
+
+	if err := app.Undo(); err != nil {
+		t.Fatalf("first undo: %v", err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != string(afterProd) {
+		t.Errorf("first undo left\n%s\nwant the kubeconfig after the first login\n%s", current, afterProd)
+	}
+	if err := app.Undo(); err != nil {
+		t.Fatalf("second undo: %v", err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != original {
+		t.Errorf("second undo left\n%s\nwant the original kubeconfig\n%s", current, original)
+	}
+	if err := app.Undo(); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Errorf("third undo = %v, want nothing to undo", err)
+	}
+}
+
+func TestUndoRemovesKubeconfigCreatedByLogin(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	path := writeTestKubeconfig(t, "")
+	os.Remove(path)
+
+	mergeLogin(t, app, clusterEntries("prod"))
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("login did not create the kubeconfig: %v", err)
+	}
+	if err := app.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("undo kept %s, which did not exist before the login", path)
+	}
+}
+
+func TestUndoAsksWhenKubeconfigChangedSince(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	app.config.Interactive = false
+	path := writeTestKubeconfig(t, testKubeconfig)
+
+	mergeLogin(t, app, clusterEntries("prod"))
+	if err := os.WriteFile(path, []byte(testKubeconfig+"x-edited: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := exitCode(app.Undo()); code != ExitNotConfirmed {
+		t.Errorf("Undo after an outside change exited %d, want it to require --force", code)
+	}
+
+	app.config.Force = true
+	if err := app.Undo(); err != nil {
+		t.Fatalf("Undo --force: %v", err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != testKubeconfig {
+		t.Errorf("Undo --force left %q, want the kubeconfig from before the login", current)
+	}
+}
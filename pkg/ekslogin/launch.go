@@ -0,0 +1,35 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+)
+
+const defaultLaunchCommand = "k9s"
+
+// LaunchConfig configures the tool started after a successful login
+type LaunchConfig struct {
+	// Command is run through the shell against the new context (default "k9s")
+	Command string `yaml:"command,omitempty"`
+	// Enabled launches the tool after every login without --k9s
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// LaunchTool starts the configured tool (k9s by default) against the new context
+func (app *EKSLoginApp) LaunchTool() error {
+	command := app.settings.Launch.Command
+	if command == "" {
+		command = defaultLaunchCommand
+	}
+
+	cyan.Printf("\n🚀 Launching: %s\n", command)
+
+	cmd := shellCommand(command)
+	cmd.Env = app.hookEnv()
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := app.run(app.context(), cmd); err != nil {
+		return fmt.Errorf("failed to launch %s: %w", command, err)
+	}
+	return nil
+}
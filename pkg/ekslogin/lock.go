@@ -0,0 +1,73 @@
+package ekslogin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockPollInterval is how often a held lock is retried
+const lockPollInterval = 100 * time.Millisecond
+
+// errLockHeld is returned by tryLock when another process holds the lock
+var errLockHeld = errors.New("lock held by another process")
+
+// FileLock is an advisory lock on a sidecar file next to the protected file.
+// The lock is released by the OS if the process dies.
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile takes an exclusive advisory lock for path, waiting until ctx is done.
+// A separate ".eks-login.lock" file is used so kubectl's own "<file>.lock"
+// convention is left alone.
+func LockFile(ctx context.Context, path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path+".eks-login.lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	waiting := false
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return &FileLock{file: file}, nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		if !waiting {
+			yellow.Printf("⏳ Waiting for another eks-login to release %s...\n", path)
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, fmt.Errorf("timed out waiting for lock on %s", path)
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() {
+	unlock(l.file)
+	l.file.Close()
+}
+
+// lockKubeconfig locks the kubeconfig against concurrent eks-login runs
+func (app *EKSLoginApp) lockKubeconfig() (*FileLock, error) {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	return LockFile(ctx, KubeconfigPath())
+}
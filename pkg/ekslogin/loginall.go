@@ -0,0 +1,176 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// filterProfiles keeps the profiles whose name matches one of the comma-separated glob patterns
+func filterProfiles(profiles []ProfileInfo, pattern string) []ProfileInfo {
+	if pattern == "" {
+		return profiles
+	}
+	patterns := strings.Split(pattern, ",")
+
+	filtered := make([]ProfileInfo, 0, len(profiles))
+	for _, profile := range profiles {
+		if matchesAny(patterns, profile.Name) {
+			filtered = append(filtered, profile)
+		}
+	}
+	return filtered
+}
+
+// profileFilter returns the glob restricting which profiles are offered
+func (app *EKSLoginApp) profileFilter() string {
+	if app.config.ProfileFilter != "" {
+		return app.config.ProfileFilter
+	}
+	return app.settings.Default.ProfileFilter
+}
+
+// FilteredProfiles returns the AWS profiles matching the profile filter
+func (app *EKSLoginApp) FilteredProfiles() ([]ProfileInfo, error) {
+	profiles, err := app.GetAWSProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := app.profileFilter()
+	profiles = filterProfiles(profiles, filter)
+	if len(profiles) == 0 && filter != "" {
+		return nil, withExitCode(ExitNoProfiles, "no_profiles", fmt.Errorf("no AWS profiles match %q", filter))
+	}
+	return profiles, nil
+}
+
+// LoginAll sets up kubeconfig contexts for every cluster of every matching
+// profile, running the pre-login hooks once per profile and the rest of the
+// login (pre-kubeconfig hooks, protected-cluster guard, webhook) per cluster
+func (app *EKSLoginApp) LoginAll() error {
+	if err := app.CheckDependencies(); err != nil {
+		return err
+	}
+
+	profiles, err := app.FilteredProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		return withExitCode(ExitNoProfiles, "no_profiles", fmt.Errorf("no AWS profiles found. Please configure AWS CLI first"))
+	}
+
+	blue.Printf("🌍 Setting up contexts for %d profile(s)...\n", len(profiles))
+
+	var updated int
+	var failures []string
+	for _, profile := range profiles {
+		cyan.Printf("\n📋 Profile: %s (region: %s)\n", profile.Name, profile.Region)
+
+		// Each cluster's login is audited by setupContext; a profile that fails
+		// before that is audited as a failed login without a cluster
+		target := app.forTarget(profile.Name, profile.Region, "")
+		if err := target.RunHooks("pre-login", app.settings.Hooks.PreLogin); err != nil {
+			target.Audit("login", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", profile.Name, err))
+			continue
+		}
+		if err := target.ensureSession(); err != nil {
+			target.Audit("login", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", profile.Name, err))
+			continue
+		}
+
+		clusters, err := target.ListEKSClusters()
+		if err != nil {
+			target.Audit("login", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", profile.Name, err))
+			continue
+		}
+
+		// Protected clusters are confirmed one by one; without a terminal, only
+		// the one named by --confirm-cluster is written and the others fail
+		for _, cluster := range clusters {
+			if err := app.forTarget(profile.Name, profile.Region, cluster).setupContext(); err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s: %v", profile.Name, cluster, err))
+				continue
+			}
+			updated++
+		}
+	}
+
+	green.Printf("\n🎉 Updated %d context(s) across %d profile(s)\n", updated, len(profiles))
+	return printFailures(failures, "profile(s) or cluster(s)")
+}
+
+// printFailures lists the failures of a batch run and returns an error if there were any
+func printFailures(failures []string, what string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	red.Printf("\n✗ %d failure(s):\n", len(failures))
+	for _, failure := range failures {
+		fmt.Printf("  - %s\n", failure)
+	}
+	return fmt.Errorf("%d %s failed", len(failures), what)
+}
+
+// setupContext writes the kubeconfig context of the selected cluster and
+// records the outcome in the audit log
+func (app *EKSLoginApp) setupContext() error {
+	err := app.updateContext()
+	app.Audit("login", err)
+	return err
+}
+
+// updateContext writes the kubeconfig context of the selected cluster, running
+// the pre-kubeconfig hooks and the protected-cluster guard first
+func (app *EKSLoginApp) updateContext() error {
+	if err := app.RunHooks("pre-kubeconfig", app.settings.Hooks.PreKubeconfig); err != nil {
+		return err
+	}
+	if err := app.ConfirmProtected(); err != nil {
+		return err
+	}
+	if err := app.UpdateKubeconfig(); err != nil {
+		return err
+	}
+	app.PostLoginEvent()
+	return nil
+}
+
+// SetupContexts sets up contexts for several clusters of the selected profile
+func (app *EKSLoginApp) SetupContexts(targets []ClusterTarget) error {
+	blue.Printf("🎯 Setting up contexts for %d cluster(s)...\n", len(targets))
+
+	var updated int
+	var failures []string
+	for _, target := range targets {
+		cyan.Printf("\n🎯 Cluster: %s (%s)\n", target.Cluster, target.Region)
+		if err := app.forTarget(app.config.Profile, target.Region, target.Cluster).setupContext(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s: %v", target.Region, target.Cluster, err))
+			continue
+		}
+		updated++
+	}
+
+	green.Printf("\n🎉 Updated %d context(s) for profile %s\n", updated, app.config.Profile)
+	return printFailures(failures, "cluster(s)")
+}
+
+func newLoginAllCmd(app *EKSLoginApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login-all",
+		Short: "Set up contexts for all clusters of all (matching) profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.LoginAll()
+		},
+	}
+	cmd.Flags().BoolVar(&app.config.Force, "force", false, "Overwrite existing contexts that log in with another profile without asking")
+	cmd.Flags().StringVar(&app.config.ConfirmCluster, "confirm-cluster", "", "Confirm this protected cluster non-interactively by passing its name")
+	return cmd
+}
@@ -0,0 +1,139 @@
+package ekslogin
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metricFamily is one metric in the Prometheus text exposition format
+type metricFamily struct {
+	name   string
+	help   string
+	kind   string
+	values map[string]float64 // keyed by rendered label set
+}
+
+// daemonMetrics holds the metrics the daemon exposes on /metrics.
+// All methods are no-ops on a nil receiver, so callers need not check
+// whether metrics are enabled.
+type daemonMetrics struct {
+	mu        sync.Mutex
+	logins    *metricFamily
+	refreshes *metricFamily
+	failures  *metricFamily
+	expiry    *metricFamily
+}
+
+func newDaemonMetrics() *daemonMetrics {
+	family := func(name, kind, help string) *metricFamily {
+		return &metricFamily{name: name, kind: kind, help: help, values: make(map[string]float64)}
+	}
+	return &daemonMetrics{
+		logins:    family("eks_login_logins_total", "counter", "SSO logins observed (session renewals) per profile."),
+		refreshes: family("eks_login_token_refreshes_total", "counter", "Successful EKS token refreshes per profile and cluster."),
+		failures:  family("eks_login_failures_total", "counter", "Failed session checks and token refreshes per profile and error class."),
+		expiry:    family("eks_login_session_expiry_seconds", "gauge", "Seconds until the SSO session of the profile expires (negative once expired)."),
+	}
+}
+
+// labels renders alternating label names and values, e.g. profile="dev"
+func labels(pairs ...string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], escape.Replace(pairs[i+1])))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m *daemonMetrics) add(family *metricFamily, labelSet string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	family.values[labelSet]++
+}
+
+// Login counts a new SSO session of profile
+func (m *daemonMetrics) Login(profile string) {
+	if m != nil {
+		m.add(m.logins, labels("profile", profile))
+	}
+}
+
+// Refresh counts a successful token refresh
+func (m *daemonMetrics) Refresh(profile, cluster string) {
+	if m != nil {
+		m.add(m.refreshes, labels("profile", profile, "cluster", cluster))
+	}
+}
+
+// Failure counts a failure of profile by error class
+func (m *daemonMetrics) Failure(profile, class string) {
+	if m != nil {
+		m.add(m.failures, labels("profile", profile, "class", class))
+	}
+}
+
+// SetExpiry records the seconds until the SSO session of profile expires
+func (m *daemonMetrics) SetExpiry(profile string, seconds float64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expiry.values[labels("profile", profile)] = seconds
+}
+
+// ClearExpiry drops the expiry of profile once it can no longer be determined
+func (m *daemonMetrics) ClearExpiry(profile string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.expiry.values, labels("profile", profile))
+}
+
+// Write renders the metrics in the Prometheus text exposition format
+func (m *daemonMetrics) Write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, family := range []*metricFamily{m.logins, m.refreshes, m.failures, m.expiry} {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.kind)
+		labelSets := make([]string, 0, len(family.values))
+		for labelSet := range family.values {
+			labelSets = append(labelSets, labelSet)
+		}
+		sort.Strings(labelSets)
+		for _, labelSet := range labelSets {
+			fmt.Fprintf(w, "%s{%s} %g\n", family.name, labelSet, family.values[labelSet])
+		}
+	}
+}
+
+// ServeMetrics serves /metrics on address until the app's context is cancelled
+func (app *EKSLoginApp) ServeMetrics(address string, metrics *daemonMetrics) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.Write(w)
+	})
+
+	blue.Printf("📈 Serving metrics on http://%s/metrics\n", listener.Addr())
+	go func() {
+		if err := app.serveUntilDone(listener, mux); err != nil {
+			yellow.Printf("⚠️  Metrics server stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,81 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetNamespace sets the default namespace of the current kubeconfig context
+func (app *EKSLoginApp) SetNamespace(namespace string) error {
+	lock, err := app.lockKubeconfig()
+	if err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	defer lock.Unlock()
+
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	app.BackupKubeconfig()
+
+	app.config.Namespace = namespace
+	if err := app.ApplyNamespace(kubeconfig); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", fmt.Errorf("failed to set namespace %s: %w", namespace, err))
+	}
+	if err := kubeconfig.Save(path); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	green.Printf("✓ Default namespace set to: %s\n", namespace)
+	return nil
+}
+
+// ApplyNamespace sets the --namespace default on the current context.
+// The caller saves the kubeconfig.
+func (app *EKSLoginApp) ApplyNamespace(kubeconfig *Kubeconfig) error {
+	if app.config.Namespace == "" {
+		return nil
+	}
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, KubeconfigPath())
+	}
+	context.Namespace = app.config.Namespace
+	return nil
+}
+
+// ListNamespaces retrieves the namespaces of the connected cluster
+func (app *EKSLoginApp) ListNamespaces() ([]string, error) {
+	output, err := app.Execute("kubectl", "get", "namespaces",
+		"-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	return strings.Fields(output), nil
+}
+
+// SelectNamespace allows interactive selection of the context's default namespace
+func (app *EKSLoginApp) SelectNamespace() error {
+	namespaces, err := app.ListNamespaces()
+	if err != nil {
+		yellow.Printf("⚠️  Unable to list namespaces: %v\n", err)
+		return nil
+	}
+
+	if len(namespaces) == 0 {
+		yellow.Println("⚠️  No namespaces visible with your permissions")
+		return nil
+	}
+
+	blue.Println("\n📂 Available Namespaces:")
+	choice, err := app.Select("namespace", namespaces, -1)
+	if err != nil {
+		return err
+	}
+
+	app.config.Namespace = namespaces[choice]
+	return nil
+}
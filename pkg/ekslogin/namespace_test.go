@@ -0,0 +1,72 @@
+package ekslogin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+  cluster:
+    server: https://dev.example.com
+contexts:
+- name: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+  context:
+    cluster: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+    user: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+current-context: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+users:
+- name: arn:aws:eks:eu-west-1:111122223333:cluster/dev
+  user: {}
+`
+
+// writeTestKubeconfig writes data to a temporary kubeconfig that KUBECONFIG
+// points at
+func writeTestKubeconfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", path)
+	return path
+}
+
+func TestSetNamespaceEditsKubeconfigWithoutKubectl(t *testing.T) {
+	executor := &fakeExecutor{}
+	app := newTestApp(t, executor, &fakePrompter{})
+	path := writeTestKubeconfig(t, testKubeconfig)
+
+	if err := app.SetNamespace("payments"); err != nil {
+		t.Fatalf("SetNamespace: %v", err)
+	}
+	if len(executor.commands) > 0 {
+		t.Errorf("SetNamespace ran %v", executor.commands)
+	}
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kubeconfig.Context(kubeconfig.CurrentContext).Namespace; got != "payments" {
+		t.Errorf("namespace = %q, want payments", got)
+	}
+}
+
+func TestApplyNamespaceLeavesContextWithoutFlag(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	kubeconfig, err := LoadKubeconfigData([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig.Context(kubeconfig.CurrentContext).Namespace = "kept"
+
+	if err := app.ApplyNamespace(kubeconfig); err != nil {
+		t.Fatalf("ApplyNamespace: %v", err)
+	}
+	if got := kubeconfig.Context(kubeconfig.CurrentContext).Namespace; got != "kept" {
+		t.Errorf("namespace = %q, want it unchanged without --namespace", got)
+	}
+}
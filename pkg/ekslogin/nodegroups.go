@@ -0,0 +1,170 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NodegroupDetails is the subset of eks describe-nodegroup output shown by nodegroups
+type NodegroupDetails struct {
+	Name          string   `json:"nodegroupName"`
+	Status        string   `json:"status"`
+	CapacityType  string   `json:"capacityType"`
+	InstanceTypes []string `json:"instanceTypes"`
+	AMIType       string   `json:"amiType"`
+	Release       string   `json:"releaseVersion"`
+	Version       string   `json:"version"`
+	ScalingConfig struct {
+		MinSize     int `json:"minSize"`
+		MaxSize     int `json:"maxSize"`
+		DesiredSize int `json:"desiredSize"`
+	} `json:"scalingConfig"`
+}
+
+// FargateProfileDetails is the subset of eks describe-fargate-profile output shown by nodegroups
+type FargateProfileDetails struct {
+	Name      string `json:"fargateProfileName"`
+	Status    string `json:"status"`
+	Selectors []struct {
+		Namespace string            `json:"namespace"`
+		Labels    map[string]string `json:"labels"`
+	} `json:"selectors"`
+}
+
+// eksJSON runs an eks subcommand for the selected cluster and decodes its JSON output
+func (app *EKSLoginApp) eksJSON(out interface{}, subcommand string, args ...string) error {
+	args = append([]string{"eks", subcommand,
+		"--cluster-name", app.config.Cluster,
+		"--region", app.config.Region,
+		"--output", "json"}, args...)
+
+	output, err := app.AWS(args...)
+	if err != nil {
+		return fmt.Errorf("eks %s failed: %w", subcommand, err)
+	}
+
+	if err := json.Unmarshal([]byte(output), out); err != nil {
+		return fmt.Errorf("failed to parse eks %s output: %w", subcommand, err)
+	}
+	return nil
+}
+
+// ListNodegroups retrieves the managed node groups of the selected cluster
+func (app *EKSLoginApp) ListNodegroups() ([]NodegroupDetails, error) {
+	var list struct {
+		Nodegroups []string `json:"nodegroups"`
+	}
+	if err := app.eksJSON(&list, "list-nodegroups"); err != nil {
+		return nil, err
+	}
+
+	nodegroups := make([]NodegroupDetails, 0, len(list.Nodegroups))
+	for _, name := range list.Nodegroups {
+		var response struct {
+			Nodegroup NodegroupDetails `json:"nodegroup"`
+		}
+		if err := app.eksJSON(&response, "describe-nodegroup", "--nodegroup-name", name); err != nil {
+			return nil, err
+		}
+		nodegroups = append(nodegroups, response.Nodegroup)
+	}
+
+	return nodegroups, nil
+}
+
+// ListFargateProfiles retrieves the Fargate profiles of the selected cluster
+func (app *EKSLoginApp) ListFargateProfiles() ([]FargateProfileDetails, error) {
+	var list struct {
+		FargateProfileNames []string `json:"fargateProfileNames"`
+	}
+	if err := app.eksJSON(&list, "list-fargate-profiles"); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]FargateProfileDetails, 0, len(list.FargateProfileNames))
+	for _, name := range list.FargateProfileNames {
+		var response struct {
+			FargateProfile FargateProfileDetails `json:"fargateProfile"`
+		}
+		if err := app.eksJSON(&response, "describe-fargate-profile", "--fargate-profile-name", name); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, response.FargateProfile)
+	}
+
+	return profiles, nil
+}
+
+// ComputeResources are the managed node groups and Fargate profiles of a cluster
+type ComputeResources struct {
+	Nodegroups      []NodegroupDetails      `json:"nodegroups"`
+	FargateProfiles []FargateProfileDetails `json:"fargateProfiles"`
+}
+
+// ShowNodegroups prints the managed node groups and Fargate profiles of the selected cluster
+func (app *EKSLoginApp) ShowNodegroups(output string) error {
+	blue.Printf("📋 Fetching node groups for cluster: %s\n", app.config.Cluster)
+
+	nodegroups, err := app.ListNodegroups()
+	if err != nil {
+		return err
+	}
+	fargateProfiles, err := app.ListFargateProfiles()
+	if err != nil {
+		return err
+	}
+
+	resources := ComputeResources{Nodegroups: nodegroups, FargateProfiles: fargateProfiles}
+	return printOutput(output, resources, func() error {
+		cyan.Println("\n🖥️  Managed Node Groups:")
+		if len(nodegroups) == 0 {
+			fmt.Println("  (none)")
+		} else {
+			table := NewTable("  ", "NAME", "STATUS", "CAPACITY", "INSTANCE TYPES", "DESIRED", "MIN", "MAX", "AMI RELEASE")
+			for _, ng := range nodegroups {
+				table.Row(ng.Name, ng.Status, ng.CapacityType, strings.Join(ng.InstanceTypes, ","),
+					ng.ScalingConfig.DesiredSize, ng.ScalingConfig.MinSize, ng.ScalingConfig.MaxSize, ng.Release)
+			}
+			if err := table.Write(os.Stdout); err != nil {
+				return err
+			}
+		}
+
+		cyan.Println("\n☁️  Fargate Profiles:")
+		if len(fargateProfiles) == 0 {
+			fmt.Println("  (none)")
+			return nil
+		}
+		table := NewTable("  ", "NAME", "STATUS", "NAMESPACES")
+		for _, profile := range fargateProfiles {
+			namespaces := make([]string, 0, len(profile.Selectors))
+			for _, selector := range profile.Selectors {
+				namespaces = append(namespaces, selector.Namespace)
+			}
+			table.Row(profile.Name, profile.Status, strings.Join(namespaces, ","))
+		}
+		return table.Write(os.Stdout)
+	})
+}
+
+func newNodegroupsCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "nodegroups",
+		Short: "List managed node groups and Fargate profiles of the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.ShowNodegroups(output)
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	return cmd
+}
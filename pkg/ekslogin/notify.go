@@ -0,0 +1,48 @@
+package ekslogin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// notifyCommand builds the native command that shows a desktop notification
+func notifyCommand(title, message string) Command {
+	switch runtime.GOOS {
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, quote.Replace(message), quote.Replace(title))
+		return Command{Name: "osascript", Args: []string{"-e", script}}
+	case "windows":
+		quote := strings.NewReplacer(`'`, `''`)
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms; `+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; `+
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; `+
+			`$n.ShowBalloonTip(10000, '%s', '%s', 'Warning'); Start-Sleep -Seconds 10; $n.Dispose()`,
+			quote.Replace(title), quote.Replace(message))
+		return Command{Name: "powershell", Args: []string{"-NoProfile", "-NonInteractive", "-Command", script}}
+	default:
+		return Command{Name: "notify-send", Args: []string{"--app-name=eks-login", "--urgency=critical", title, message}}
+	}
+}
+
+// Notify shows a desktop notification through the platform's native mechanism
+// (osascript on macOS, notify-send on Linux, a PowerShell balloon on Windows)
+func (app *EKSLoginApp) Notify(title, message string) error {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var output bytes.Buffer
+	command := notifyCommand(title, message)
+	command.Env, command.Stdout, command.Stderr = os.Environ(), &output, &output
+	if err := app.run(ctx, command); err != nil {
+		detail := strings.TrimSpace(output.String())
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("failed to show notification via %s: %s", command.Name, detail)
+	}
+	return nil
+}
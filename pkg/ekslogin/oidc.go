@@ -0,0 +1,109 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// OIDCInfo describes the cluster's OIDC issuer and its IAM OIDC provider
+type OIDCInfo struct {
+	Issuer      string `json:"issuer"`
+	ProviderARN string `json:"providerArn,omitempty"`
+	Associated  bool   `json:"associated"`
+}
+
+// FindOIDCProvider returns the ARN of the IAM OIDC provider for issuer, if one exists
+func (app *EKSLoginApp) FindOIDCProvider(issuer string) (string, error) {
+	output, err := app.AWS("iam", "list-open-id-connect-providers",
+		"--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to list IAM OIDC providers: %w", err)
+	}
+
+	var response struct {
+		OpenIDConnectProviderList []struct {
+			Arn string `json:"Arn"`
+		} `json:"OpenIDConnectProviderList"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return "", fmt.Errorf("failed to parse IAM OIDC providers: %w", err)
+	}
+
+	host := strings.TrimPrefix(issuer, "https://")
+	for _, provider := range response.OpenIDConnectProviderList {
+		if strings.HasSuffix(provider.Arn, ":oidc-provider/"+host) {
+			return provider.Arn, nil
+		}
+	}
+	return "", nil
+}
+
+// GetOIDCInfo resolves the OIDC/IRSA information of a described cluster
+func (app *EKSLoginApp) GetOIDCInfo(details *ClusterDetails) (*OIDCInfo, error) {
+	info := &OIDCInfo{Issuer: details.Identity.OIDC.Issuer}
+	if info.Issuer == "" {
+		return info, nil
+	}
+
+	providerARN, err := app.FindOIDCProvider(info.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	info.ProviderARN = providerARN
+	info.Associated = providerARN != ""
+
+	return info, nil
+}
+
+// printOIDCInfo prints the OIDC lines shared by describe and oidc
+func printOIDCInfo(info *OIDCInfo) {
+	fmt.Printf("OIDC issuer:      %s\n", info.Issuer)
+	if info.Associated {
+		fmt.Printf("OIDC provider:    %s\n", info.ProviderARN)
+	} else {
+		fmt.Printf("OIDC provider:    %s\n", yellow.Sprint("not associated (IRSA unavailable)"))
+	}
+}
+
+// ShowOIDCInfo prints the OIDC/IRSA information of the selected cluster
+func (app *EKSLoginApp) ShowOIDCInfo(output string) error {
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return err
+	}
+
+	info, err := app.GetOIDCInfo(details)
+	if err != nil {
+		return err
+	}
+
+	return printOutput(output, info, func() error {
+		printOIDCInfo(info)
+		if !info.Associated {
+			fmt.Printf("\nAssociate one with:\n  eksctl utils associate-iam-oidc-provider --cluster %s --region %s --approve\n",
+				app.config.Cluster, app.config.Region)
+		}
+		return nil
+	})
+}
+
+func newOIDCCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "oidc",
+		Short: "Show the cluster's OIDC issuer and IAM OIDC provider (IRSA)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.ShowOIDCInfo(output)
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	return cmd
+}
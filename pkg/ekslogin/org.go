@@ -0,0 +1,208 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// OrgAccount represents an account returned by organizations list-accounts
+type OrgAccount struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+}
+
+// ListOrgAccounts retrieves the active member accounts of the organization
+func (app *EKSLoginApp) ListOrgAccounts() ([]OrgAccount, error) {
+	output, err := app.AWS("organizations", "list-accounts", "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+	}
+
+	var response struct {
+		Accounts []OrgAccount `json:"Accounts"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse organization accounts: %w", err)
+	}
+
+	accounts := make([]OrgAccount, 0, len(response.Accounts))
+	for _, account := range response.Accounts {
+		if account.Status == "ACTIVE" {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// AssumeRole assumes roleARN with the selected profile's credentials, for
+// --duration if given. Sessions are shared with the AWS CLI's credential cache.
+func (app *EKSLoginApp) AssumeRole(roleARN string) (*AWSCredentials, error) {
+	input := stsAssumeRoleInput{RoleARN: roleARN, SessionName: "eks-login", Duration: app.roleDurationSeconds()}
+	return app.cachedCredentials(assumeRoleCacheKey(input), "", func() (*AWSCredentials, error) {
+		return app.assumeRole(roleARN)
+	})
+}
+
+// assumeRole calls sts assume-role for AssumeRole
+func (app *EKSLoginApp) assumeRole(roleARN string) (*AWSCredentials, error) {
+	args := []string{"sts", "assume-role",
+		"--role-arn", roleARN,
+		"--role-session-name", "eks-login",
+		"--output", "json"}
+	if seconds := app.roleDurationSeconds(); seconds != "" {
+		args = append(args, "--duration-seconds", seconds)
+	}
+	output, err := app.AWS(args...)
+	if err != nil {
+		return nil, app.roleDurationError(fmt.Errorf("failed to assume role %s: %w", roleARN, err))
+	}
+
+	var response struct {
+		Credentials AWSCredentials `json:"Credentials"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse assumed role credentials: %w", err)
+	}
+
+	return &response.Credentials, nil
+}
+
+// orgRoleARN returns the ARN of the cross-account role in a member account
+func (app *EKSLoginApp) orgRoleARN(accountID, roleName string) string {
+	return ARN{
+		Partition: app.partition().ID,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  "role/" + roleName,
+	}.String()
+}
+
+// DiscoverOrgClusters assumes roleName in every member account and lists their
+// clusters, up to --concurrency AWS calls at once. An empty region list scans
+// the selected region only.
+func (app *EKSLoginApp) DiscoverOrgClusters(roleName string, regions []string) ([]InventoryEntry, error) {
+	accounts, err := app.ListOrgAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	management, err := app.GetAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regions) == 0 {
+		regions = []string{app.config.Region}
+	}
+
+	blue.Printf("🏢 Scanning %d organization accounts...\n", len(accounts))
+
+	// Each account is one target: the role is assumed, then its regions listed
+	progress := NewScanProgress(len(accounts))
+	found := make([][]InventoryEntry, len(accounts))
+	targets := make([][]*EKSLoginApp, len(accounts))
+	app.forEach(len(accounts), func(i int) {
+		account := accounts[i]
+		label := fmt.Sprintf("%s (%s)", account.Name, account.ID)
+
+		// The management account is reached directly, members through the role
+		var creds *AWSCredentials
+		roleARN := ""
+		if account.ID != management {
+			roleARN = app.orgRoleARN(account.ID, roleName)
+			var err error
+			if creds, err = app.AssumeRole(roleARN); err != nil {
+				progress.Done(label, 0, err)
+				return
+			}
+		}
+
+		var errs []error
+		for _, region := range regions {
+			target := app.forTarget(app.config.Profile, region, "")
+			if creds != nil {
+				target.credentials = creds
+			}
+			clusters, err := target.listClusters()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", region, err))
+				continue
+			}
+			for _, cluster := range clusters {
+				found[i] = append(found[i], InventoryEntry{
+					Profile:     app.config.Profile,
+					Account:     account.ID,
+					AccountName: account.Name,
+					RoleARN:     roleARN,
+					Region:      region,
+					Cluster:     cluster,
+				})
+				clusterTarget := app.forTarget(app.config.Profile, region, cluster)
+				clusterTarget.credentials = target.credentials
+				targets[i] = append(targets[i], clusterTarget)
+			}
+		}
+		progress.Done(label, len(found[i]), errors.Join(errs...))
+	})
+	progress.Summary()
+
+	var entries []InventoryEntry
+	var clusters []*EKSLoginApp
+	for i := range accounts {
+		entries = append(entries, found[i]...)
+		clusters = append(clusters, targets[i]...)
+	}
+
+	app.describeEntries(entries, clusters)
+	return entries, nil
+}
+
+// SelectOrgCluster allows interactive selection of a cluster anywhere in the organization
+func (app *EKSLoginApp) SelectOrgCluster(roleName string) error {
+	entries, err := app.DiscoverOrgClusters(roleName, app.regions())
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return withExitCode(ExitNoClusters, "no_clusters", fmt.Errorf("no EKS clusters found in the organization in region %s", app.config.Region))
+	}
+
+	items := make([]string, len(entries))
+	for i, entry := range entries {
+		items[i] = fmt.Sprintf("%s (%s / %s)", entry.Cluster, entry.AccountName, entry.Account)
+	}
+
+	blue.Printf("\n🎯 Available EKS Clusters in the organization (%s):\n", app.config.Region)
+	choice, err := app.Select("cluster", items, -1)
+	if err != nil {
+		return err
+	}
+
+	return app.useInventoryEntry(entries[choice])
+}
+
+// useInventoryEntry points the app at a discovered cluster, assuming its role if needed
+func (app *EKSLoginApp) useInventoryEntry(entry InventoryEntry) error {
+	app.config.Cluster = entry.Cluster
+	app.config.Region = entry.Region
+	app.config.RoleARN = entry.RoleARN
+
+	if entry.RoleARN != "" {
+		creds, err := app.AssumeRole(entry.RoleARN)
+		if err != nil {
+			return err
+		}
+		app.credentials = creds
+	}
+	return nil
+}
+
+// addOrgFlags registers the organization discovery flags on cmd
+func addOrgFlags(cmd *cobra.Command, roleName *string) {
+	cmd.Flags().StringVar(roleName, "org-role", "", "Discover clusters in all organization accounts by assuming this role (requires a management account profile)")
+}
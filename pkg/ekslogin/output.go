@@ -0,0 +1,143 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats shared by the listing commands
+const (
+	outputTable = "table"
+	outputYAML  = "yaml"
+	outputJSON  = "json"
+)
+
+// addOutputFlag registers the shared --output/-o flag of a listing command;
+// extra lists formats it supports besides table, yaml and json. "text" is
+// accepted for table. Formats other than table send status messages to stderr,
+// so stdout can be piped.
+func addOutputFlag(cmd *cobra.Command, output *string, extra ...string) {
+	formats := append([]string{outputTable, outputYAML, outputJSON}, extra...)
+	help := strings.Join(formats[:len(formats)-1], ", ") + " or " + formats[len(formats)-1]
+	cmd.Flags().StringVarP(output, "output", "o", outputTable, "Output format: "+help)
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if *output == "text" || *output == "" {
+			*output = outputTable
+		}
+		if !slices.Contains(formats, *output) {
+			return usageError("unsupported output format %q (use %s)", *output, help)
+		}
+		if *output != outputTable {
+			statusToStderr()
+		}
+		return nil
+	}
+}
+
+// printOutput writes value as JSON or YAML, or calls table for the table format
+func printOutput(output string, value interface{}, table func() error) error {
+	switch output {
+	case outputJSON:
+		return printJSON(value)
+	case outputYAML:
+		return printYAML(value)
+	default:
+		return table()
+	}
+}
+
+// printYAML writes value to stdout as YAML with the same keys as its JSON
+func printYAML(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML: decoding it keeps the keys and their order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(&node)
+}
+
+// blockStyle turns the flow style of decoded JSON into YAML's block style
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// ansiCode matches the color codes of a table cell
+var ansiCode = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Table renders rows as aligned columns. Widths are measured without color
+// codes, so colored cells line up, which text/tabwriter does not do.
+type Table struct {
+	indent string
+	rows   [][]string
+}
+
+// NewTable starts a table with a header row; indent prefixes every line
+func NewTable(indent string, headers ...string) *Table {
+	return &Table{indent: indent, rows: [][]string{headers}}
+}
+
+// Row adds a row of values, formatted with fmt.Sprint
+func (t *Table) Row(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = fmt.Sprint(value)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Write renders the table to w, two spaces between columns
+func (t *Table) Write(w io.Writer) error {
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], cellWidth(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range t.rows {
+		var line strings.Builder
+		line.WriteString(t.indent)
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-cellWidth(cell)+2))
+			}
+		}
+		// trailing empty cells leave no padding behind
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cellWidth is the width of cell on the terminal
+func cellWidth(cell string) int {
+	return utf8.RuneCountInString(ansiCode.ReplaceAllString(cell, ""))
+}
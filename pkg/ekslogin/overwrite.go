@@ -0,0 +1,115 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextOwner returns the profile and role the user entry of context logs in
+// with: from eks-login's metadata, or for contexts written by other tools from
+// the aws eks get-token arguments and environment
+func contextOwner(kubeconfig *Kubeconfig, context *KubeContext) (profile, role string) {
+	if metadata := context.Metadata(); metadata != nil {
+		return metadata.Profile, metadata.RoleARN
+	}
+	user := kubeconfig.User(context.User)
+	if user == nil || user.Exec == nil {
+		return "", ""
+	}
+	for _, env := range user.Exec.Env {
+		if env.Name == "AWS_PROFILE" {
+			profile = env.Value
+		}
+	}
+	for i, arg := range user.Exec.Args {
+		if (arg == "--role-arn" || arg == "--role") && i+1 < len(user.Exec.Args) {
+			role = user.Exec.Args[i+1]
+		}
+	}
+	return profile, role
+}
+
+// contextConflict describes how the existing context name differs from the
+// login about to overwrite it, or returns "" if it does not
+func (app *EKSLoginApp) contextConflict(kubeconfig *Kubeconfig, name string, context *KubeContext) string {
+	if context.Cluster != name {
+		return fmt.Sprintf("points at cluster entry %s", context.Cluster)
+	}
+	profile, role := contextOwner(kubeconfig, context)
+	switch {
+	case profile != "" && profile != app.config.Profile:
+		return fmt.Sprintf("logs in with profile %s, not %s", profile, app.config.Profile)
+	case role != app.config.RoleARN && role == "":
+		return "logs in without assuming a role"
+	case role != app.config.RoleARN:
+		return fmt.Sprintf("assumes role %s", role)
+	}
+	return ""
+}
+
+// ConfirmOverwrite asks before the login replaces a context of the same name
+// that points at another cluster entry or logs in with another profile or
+// role, so a context that was switched silently cannot be mistaken for the
+// old one. --force skips the question. The caller must hold the kubeconfig lock.
+func (app *EKSLoginApp) ConfirmOverwrite() error {
+	if app.config.Force {
+		return nil
+	}
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+
+	// Contexts are named after the cluster ARN; look for this cluster's before
+	// asking STS for the account
+	suffix := ":cluster/" + app.config.Cluster
+	var conflicts []string
+	for _, named := range kubeconfig.Contexts {
+		arn, err := ParseARN(named.Name)
+		if err != nil || arn.Service != "eks" || arn.Region != app.config.Region || !strings.HasSuffix(named.Name, suffix) {
+			continue
+		}
+		if app.contextConflict(kubeconfig, named.Name, &named.Context) != "" {
+			conflicts = append(conflicts, named.Name)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	account, err := app.GetAccountID()
+	if err != nil {
+		return err
+	}
+	name := ARN{
+		Partition: app.partition().ID,
+		Service:   "eks",
+		Region:    app.config.Region,
+		AccountID: account,
+		Resource:  "cluster/" + app.config.Cluster,
+	}.String()
+	context := kubeconfig.Context(name)
+	if context == nil {
+		return nil
+	}
+	conflict := app.contextConflict(kubeconfig, name, context)
+	if conflict == "" {
+		return nil
+	}
+
+	if !app.config.Interactive {
+		return withExitCode(ExitNotConfirmed, "context_conflict",
+			fmt.Errorf("context %s in %s already exists and %s; pass --force to overwrite it", name, path, conflict))
+	}
+	yellow.Printf("⚠️  Context %s already exists and %s\n", name, conflict)
+	yellow.Printf("⚠️  Overwrite it with profile %s? [y/N]: ", app.config.Profile)
+	input, err := app.prompter.ReadLine()
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+		return errAborted
+	}
+	return nil
+}
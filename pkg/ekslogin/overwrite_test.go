@@ -0,0 +1,56 @@
+package ekslogin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextConflict(t *testing.T) {
+	const name = "arn:aws:eks:eu-west-1:111122223333:cluster/prod"
+	const role = "arn:aws:iam::111122223333:role/admin"
+
+	// login returns a context written by eks-login with profile and role
+	login := func(profile, role string) KubeContext {
+		context := KubeContext{Cluster: name, User: name}
+		if err := context.SetMetadata(LoginMetadata{Profile: profile, Cluster: "prod", RoleARN: role}); err != nil {
+			t.Fatal(err)
+		}
+		return context
+	}
+	// foreign returns the user entry of a context another tool wrote
+	foreign := func(profile string, args ...string) *KubeUser {
+		args = append([]string{"eks", "get-token", "--cluster-name", "prod"}, args...)
+		return &KubeUser{Exec: &ExecConfig{Command: "aws", Args: args, Env: []ExecEnvVar{{Name: "AWS_PROFILE", Value: profile}}}}
+	}
+
+	tests := []struct {
+		name    string
+		context KubeContext
+		user    *KubeUser
+		role    string
+		want    string
+	}{
+		{name: "same login", context: login("dev", "")},
+		{name: "same role", context: login("dev", role), role: role},
+		{name: "other cluster entry", context: KubeContext{Cluster: "other", User: name}, want: "points at cluster entry other"},
+		{name: "other profile", context: login("prod", ""), want: "logs in with profile prod, not dev"},
+		{name: "role not requested", context: login("dev", role), want: "assumes role " + role},
+		{name: "role requested", context: login("dev", ""), role: role, want: "logs in without assuming a role"},
+		{name: "unknown owner", context: KubeContext{Cluster: name, User: name}},
+		{name: "other tool's profile", context: KubeContext{Cluster: name, User: name}, user: foreign("ops"), want: "logs in with profile ops, not dev"},
+		{name: "other tool's role", context: KubeContext{Cluster: name, User: name}, user: foreign("dev", "--role-arn", role), role: role},
+	}
+	for _, test := range tests {
+		t.Run(strings.ReplaceAll(test.name, " ", "_"), func(t *testing.T) {
+			app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+			app.config.RoleARN = test.role
+			kubeconfig := &Kubeconfig{}
+			if test.user != nil {
+				kubeconfig.SetUser(name, *test.user)
+			}
+			if got := app.contextConflict(kubeconfig, name, &test.context); got != test.want {
+				t.Errorf("contextConflict = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
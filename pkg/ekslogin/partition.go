@@ -0,0 +1,75 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Partition holds the partition-specific names and domains of AWS
+type Partition struct {
+	ID             string
+	DNSSuffix      string
+	SigninEndpoint string
+	// ConsoleHost may contain a %s placeholder for the region
+	ConsoleHost string
+}
+
+var (
+	partitionAWS = Partition{
+		ID:             "aws",
+		DNSSuffix:      "amazonaws.com",
+		SigninEndpoint: "https://signin.aws.amazon.com/federation",
+		ConsoleHost:    "%s.console.aws.amazon.com",
+	}
+	partitionGovCloud = Partition{
+		ID:             "aws-us-gov",
+		DNSSuffix:      "amazonaws.com",
+		SigninEndpoint: "https://signin.amazonaws-us-gov.com/federation",
+		ConsoleHost:    "console.amazonaws-us-gov.com",
+	}
+	partitionChina = Partition{
+		ID:             "aws-cn",
+		DNSSuffix:      "amazonaws.com.cn",
+		SigninEndpoint: "https://signin.amazonaws.cn/federation",
+		ConsoleHost:    "console.amazonaws.cn",
+	}
+)
+
+// PartitionForRegion returns the partition a region belongs to
+func PartitionForRegion(region string) Partition {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return partitionGovCloud
+	case strings.HasPrefix(region, "cn-"):
+		return partitionChina
+	default:
+		return partitionAWS
+	}
+}
+
+// Endpoint returns the regional hostname of a service
+func (p Partition) Endpoint(service, region string) string {
+	return fmt.Sprintf("%s.%s.%s", service, region, p.DNSSuffix)
+}
+
+// ConsoleURL returns a console URL for path in region
+func (p Partition) ConsoleURL(region, path string) string {
+	host := p.ConsoleHost
+	if strings.Contains(host, "%s") {
+		host = fmt.Sprintf(host, region)
+	}
+	return "https://" + host + path
+}
+
+// serviceEndpoint returns the hostname used for a service in the selected region
+func (app *EKSLoginApp) serviceEndpoint(service string) string {
+	if app.fipsEnabled() {
+		service += "-fips"
+	}
+	return app.partition().Endpoint(service, app.config.Region)
+}
+
+// partition returns the partition of the selected region
+func (app *EKSLoginApp) partition() Partition {
+	return PartitionForRegion(app.config.Region)
+}
@@ -0,0 +1,56 @@
+package ekslogin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// kubectlPluginName is the binary name kubectl runs for 'kubectl eks-login'
+const kubectlPluginName = "kubectl-eks_login"
+
+// binaryName returns the name this binary was invoked as, without .exe
+func binaryName() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+}
+
+// IsKubectlPlugin reports whether the binary runs as 'kubectl eks-login'
+func IsKubectlPlugin() bool {
+	return binaryName() == kubectlPluginName
+}
+
+// usePluginMode presents rootCmd as 'kubectl eks-login': help and usage show the
+// kubectl invocation, and --kubeconfig is accepted like other kubectl commands
+func usePluginMode(rootCmd *cobra.Command) {
+	if rootCmd.Annotations == nil {
+		rootCmd.Annotations = map[string]string{}
+	}
+	rootCmd.Annotations[cobra.CommandDisplayNameAnnotation] = "kubectl eks-login"
+	rootCmd.Use = "kubectl " + rootCmd.Use
+	rootCmd.Long = strings.ReplaceAll(rootCmd.Long, "\n  eks-login", "\n  kubectl eks-login")
+	var rename func(cmd *cobra.Command)
+	rename = func(cmd *cobra.Command) {
+		cmd.Example = strings.ReplaceAll(cmd.Example, "eks-login ", "kubectl eks-login ")
+		for _, child := range cmd.Commands() {
+			rename(child)
+		}
+	}
+	rename(rootCmd)
+
+	var kubeconfig string
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to update (default $KUBECONFIG or ~/.kube/config)")
+
+	// --kubeconfig is exported as KUBECONFIG so the AWS CLI and every kubeconfig
+	// read or write use the same file
+	preRun := rootCmd.PersistentPreRunE
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if kubeconfig != "" {
+			if err := os.Setenv("KUBECONFIG", kubeconfig); err != nil {
+				return err
+			}
+		}
+		return preRun(cmd, args)
+	}
+}
@@ -0,0 +1,129 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// Preset bundles the target of a named environment such as "prod-eu"
+type Preset struct {
+	Profile   string `yaml:"profile" json:"profile"`
+	Region    string `yaml:"region,omitempty" json:"region,omitempty"`
+	Cluster   string `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+}
+
+// presetNames returns the configured preset names in order
+func (app *EKSLoginApp) presetNames() []string {
+	names := make([]string, 0, len(app.settings.Presets))
+	for name := range app.settings.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printPresets lists the presets with their selection numbers
+func (app *EKSLoginApp) printPresets(names []string) {
+	for i, item := range app.presetItems(names) {
+		fmt.Printf("  %d. %s\n", i+1, item)
+	}
+}
+
+// presetItems describes each preset for listing and selection
+func (app *EKSLoginApp) presetItems(names []string) []string {
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = app.settings.Presets[name].describe(name)
+	}
+	return items
+}
+
+// describe renders a named preset, e.g. "prod (profile: p, cluster: c)"
+func (p Preset) describe(name string) string {
+	item := fmt.Sprintf("%s (profile: %s", name, p.Profile)
+	if p.Region != "" {
+		item += ", region: " + p.Region
+	}
+	if p.Cluster != "" {
+		item += ", cluster: " + p.Cluster
+	}
+	if p.Namespace != "" {
+		item += ", namespace: " + p.Namespace
+	}
+	return item + ")"
+}
+
+// ApplyPreset sets the target from a preset. Flags given explicitly take precedence.
+func (app *EKSLoginApp) ApplyPreset(cmd *cobra.Command, name string) error {
+	preset, ok := app.settings.Presets[name]
+	if !ok {
+		return usageError("unknown preset %q (configured: %v)", name, app.presetNames())
+	}
+
+	app.applyPreset(cmd, preset)
+	cyan.Printf("🌍 Using preset: %s\n", name)
+	return nil
+}
+
+// applyPreset sets the target fields of preset that were not given as flags
+func (app *EKSLoginApp) applyPreset(cmd *cobra.Command, preset Preset) {
+	if preset.Profile != "" && !cmd.Flags().Changed("profile") {
+		app.config.Profile = preset.Profile
+	}
+	if preset.Region != "" && !cmd.Flags().Changed("region") {
+		app.config.Region = preset.Region
+		app.config.Regions = []string{preset.Region}
+		app.config.RegionSet = true
+	}
+	if preset.Cluster != "" && !cmd.Flags().Changed("cluster") {
+		app.config.Cluster = preset.Cluster
+	}
+	if preset.Namespace != "" {
+		app.config.Namespace = preset.Namespace
+	}
+}
+
+// newUseCmd creates the use subcommand
+func newUseCmd(app *EKSLoginApp) *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "use [preset]",
+		Short: "Log in to a named environment preset from the config file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := app.presetNames()
+			if len(names) == 0 {
+				return usageError("no presets configured; add them under 'presets' in %s", app.config.ConfigFile)
+			}
+
+			if list {
+				app.printPresets(names)
+				return nil
+			}
+
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			} else {
+				blue.Println("\n🌍 Available presets:")
+				choice, err := app.Select("preset", app.presetItems(names), -1)
+				if err != nil {
+					return err
+				}
+				name = names[choice]
+			}
+
+			if err := app.ApplyPreset(cmd, name); err != nil {
+				return err
+			}
+			return app.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List configured presets")
+	return cmd
+}
@@ -0,0 +1,100 @@
+package ekslogin
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// redactCertificates shortens the certificate authorities of kubeconfig to a
+// fingerprint, so a diff shows whether one changed without its base64 lines
+func redactCertificates(kubeconfig *Kubeconfig) {
+	for i := range kubeconfig.Clusters {
+		cluster := &kubeconfig.Clusters[i].Cluster
+		if cluster.CertificateAuthorityData != "" {
+			sum := sha256.Sum256([]byte(cluster.CertificateAuthorityData))
+			cluster.CertificateAuthorityData = fmt.Sprintf("<certificate authority sha256:%x>", sum[:6])
+		}
+	}
+}
+
+// kubeconfigDiff returns the unified diff of the changes the login would make
+// to the kubeconfig file, or nil if it would leave it as it is
+func (app *EKSLoginApp) kubeconfigDiff(tokenExec string) ([]string, error) {
+	entries, err := app.plannedEntries(tokenExec)
+	if err != nil {
+		return nil, err
+	}
+
+	path := KubeconfigPath()
+	before, err := LoadKubeconfig(path)
+	if err != nil {
+		return nil, err
+	}
+	after, err := LoadKubeconfig(path)
+	if err != nil {
+		return nil, err
+	}
+	after.Merge(entries)
+	redactCertificates(before)
+	redactCertificates(after)
+
+	old, err := before.Encode()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := after.Encode()
+	if err != nil {
+		return nil, err
+	}
+	hunks := unifiedDiff(splitLines(string(old)), splitLines(string(updated)))
+	if len(hunks) == 0 {
+		return nil, nil
+	}
+	return append([]string{"--- " + path, "+++ " + path + " (after login)"}, hunks...), nil
+}
+
+// PreviewKubeconfig shows the diff of the kubeconfig changes the login would
+// make and asks before they are written; --force writes them without asking
+func (app *EKSLoginApp) PreviewKubeconfig(tokenExec string) error {
+	diff, err := app.kubeconfigDiff(tokenExec)
+	if err != nil {
+		return fmt.Errorf("failed to preview kubeconfig changes: %w", err)
+	}
+	if len(diff) == 0 {
+		green.Println("✓ The kubeconfig already has these entries; nothing changes")
+		return nil
+	}
+
+	blue.Println("📝 Kubeconfig changes:")
+	for _, line := range diff {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			fmt.Println(line)
+		case strings.HasPrefix(line, "@@"):
+			cyan.Println(line)
+		case strings.HasPrefix(line, "+"):
+			green.Println(line)
+		case strings.HasPrefix(line, "-"):
+			red.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+
+	if app.config.Force {
+		return nil
+	}
+	if !app.config.Interactive {
+		return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf("kubeconfig changes need confirmation; pass --force to write them"))
+	}
+	yellow.Print("Write these changes? [y/N]: ")
+	input, err := app.prompter.ReadLine()
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+		return errAborted
+	}
+	return nil
+}
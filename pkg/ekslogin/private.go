@@ -0,0 +1,118 @@
+package ekslogin
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// endpointDialTimeout bounds the reachability probe of a private or restricted API endpoint
+const endpointDialTimeout = 3 * time.Second
+
+// EndpointUnreachableError is returned when the API endpoint of a private (or
+// CIDR-restricted) cluster cannot be reached from this network
+type EndpointUnreachableError struct {
+	Cluster  string
+	Endpoint string
+	// Private is set when the cluster has no public endpoint at all
+	Private bool
+	VpcID   string
+	Cidrs   []string
+	Err     error
+}
+
+func (e *EndpointUnreachableError) Error() string {
+	kind := "API endpoint"
+	if e.Private {
+		kind = "private API endpoint"
+	}
+	return fmt.Sprintf("%s %s of cluster %s is not reachable from this network: %v", kind, e.Endpoint, e.Cluster, e.Err)
+}
+
+func (e *EndpointUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// restrictedPublicAccess reports whether the public endpoint only admits some CIDRs
+func restrictedPublicAccess(cidrs []string) bool {
+	for _, cidr := range cidrs {
+		if cidr == "0.0.0.0/0" {
+			return false
+		}
+	}
+	return len(cidrs) > 0
+}
+
+// probeEndpoint checks that the API endpoint of a private or CIDR-restricted
+// cluster accepts connections, so verification fails fast with guidance
+// instead of hanging. Endpoints reached through a proxy are not probed.
+func (app *EKSLoginApp) probeEndpoint(details *ClusterDetails) error {
+	vpc := details.ResourcesVpcConfig
+	if vpc.EndpointPublicAccess && !restrictedPublicAccess(vpc.PublicAccessCidrs) {
+		return nil
+	}
+	if app.clusterProxyURL(details.Endpoint) != "" {
+		return nil
+	}
+
+	endpoint, err := url.Parse(details.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil
+	}
+	if proxy, err := http.ProxyFromEnvironment(&http.Request{URL: endpoint}); err != nil || proxy != nil {
+		return nil
+	}
+
+	address := endpoint.Host
+	if endpoint.Port() == "" {
+		address = net.JoinHostPort(endpoint.Hostname(), "443")
+	}
+	dialer := net.Dialer{Timeout: endpointDialTimeout}
+	conn, err := dialer.DialContext(app.context(), "tcp", address)
+	if err != nil {
+		return &EndpointUnreachableError{
+			Cluster:  details.Name,
+			Endpoint: details.Endpoint,
+			Private:  !vpc.EndpointPublicAccess,
+			VpcID:    vpc.VpcID,
+			Cidrs:    vpc.PublicAccessCidrs,
+			Err:      err,
+		}
+	}
+	conn.Close()
+	return nil
+}
+
+// printEndpointGuidance explains how to reach an unreachable API endpoint
+func (app *EKSLoginApp) printEndpointGuidance(e *EndpointUnreachableError) {
+	host := strings.TrimPrefix(e.Endpoint, "https://")
+	if endpoint, err := url.Parse(e.Endpoint); err == nil && endpoint.Hostname() != "" {
+		host = endpoint.Hostname()
+	}
+	vpc := "the cluster's VPC"
+	if e.VpcID != "" {
+		vpc = e.VpcID
+	}
+
+	if !e.Private {
+		yellow.Printf("🔒 The API endpoint of %s only accepts connections from %s,\n", e.Cluster, strings.Join(e.Cidrs, ", "))
+		yellow.Println("   and this network is not among them. Connect to the VPN or an allowed network.")
+		fmt.Println("   The kubeconfig context is written and works once the endpoint is reachable.")
+		return
+	}
+
+	yellow.Printf("🔒 %s only has a private API endpoint, which is not reachable from this network.\n", e.Cluster)
+	fmt.Printf("   Connect to the VPN that routes to %s, or tunnel through a bastion in it:\n", vpc)
+	bastion := " --bastion <bastion-instance-id>"
+	if via, _ := app.tunnelVia(""); (via == tunnelViaSSH && app.settings.Tunnel.SSH.Host != "") || (via == tunnelViaSSM && app.tunnelBastion("") != "") {
+		bastion = ""
+	}
+	fmt.Printf("     eks-login tunnel --profile %s --region %s --cluster %s%s\n", app.config.Profile, app.config.Region, e.Cluster, bastion)
+	fmt.Printf("   or, without eks-login:\n")
+	fmt.Printf("     aws ssm start-session --target <bastion-instance-id> --document-name AWS-StartPortForwardingSessionToRemoteHost \\\n")
+	fmt.Printf("       --parameters host=%s,portNumber=443,localPortNumber=%d --profile %s --region %s\n", host, app.tunnelPort(0), app.config.Profile, app.config.Region)
+	fmt.Println("   The kubeconfig context is written and works once the endpoint is reachable.")
+}
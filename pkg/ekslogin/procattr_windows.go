@@ -0,0 +1,8 @@
+//go:build windows
+
+package ekslogin
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows, where cancellation kills the process only
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,73 @@
+package ekslogin
+
+import (
+	"strings"
+	"sync"
+)
+
+// scanFailure is a target a scan could not cover
+type scanFailure struct {
+	Target string
+	Err    error
+}
+
+// ScanProgress reports the targets of a scan of several regions, profiles or
+// accounts as they finish, one line each, and remembers the failed ones for
+// the summary, so a failing target neither goes unnoticed nor stops the scan
+type ScanProgress struct {
+	mu       sync.Mutex
+	total    int
+	done     int
+	failures []scanFailure
+}
+
+// NewScanProgress starts reporting a scan of total targets
+func NewScanProgress(total int) *ScanProgress {
+	return &ScanProgress{total: total}
+}
+
+// Done records that target finished, having found count clusters; a non-nil
+// err marks it failed. Clusters found before a failure still count.
+func (p *ScanProgress) Done(target string, count int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	if err != nil {
+		p.failures = append(p.failures, scanFailure{Target: target, Err: err})
+		red.Printf("  ✗ [%d/%d] %s failed\n", p.done, p.total, target)
+		return
+	}
+	green.Printf("  ✓ [%d/%d] %s: %d cluster(s)\n", p.done, p.total, target, count)
+}
+
+// Skip records a target that was left out before scanning, e.g. a profile
+// whose session could not be established
+func (p *ScanProgress) Skip(target string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = append(p.failures, scanFailure{Target: target, Err: err})
+}
+
+// Failed reports whether any target failed or was skipped
+func (p *ScanProgress) Failed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.failures) > 0
+}
+
+// Summary prints the targets that failed or were skipped, with their errors
+func (p *ScanProgress) Summary() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.failures) == 0 {
+		return
+	}
+	yellow.Printf("⚠️  %d target(s) could not be scanned:\n", len(p.failures))
+	for _, failure := range p.failures {
+		// AWS CLI errors span lines; keep each failure on one
+		message := strings.Join(strings.Fields(failure.Err.Error()), " ")
+		yellow.Printf("   %s: %s\n", failure.Target, message)
+	}
+}
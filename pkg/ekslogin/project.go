@@ -0,0 +1,92 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigNames are the per-project config files looked up from the working directory
+var projectConfigNames = []string{".eks-login.yaml", ".eks-login.yml"}
+
+// FindProjectConfig walks up from dir and returns the first project config file, or ""
+func FindProjectConfig(dir string) string {
+	for {
+		for _, name := range projectConfigNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// MergeProjectSettings overlays the project config at path onto settings.
+// Hooks, the launch command, endpoints, the CA bundle and the protected-login
+// webhook are ignored: a cloned repository must not be able to run commands,
+// redirect AWS traffic or collect logins just because eks-login was started
+// inside it.
+func MergeProjectSettings(settings *Settings, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read project config: %w", err)
+	}
+	// Project configs are checked in with the repository: migrate them in memory only
+	if data, _, err = migrateConfig(path, data); err != nil {
+		return err
+	}
+
+	if issues := ValidateConfig(data); len(issues) > 0 {
+		return withExitCode(ExitUsage, "invalid_config", &ConfigError{Path: path, Issues: issues})
+	}
+	var project Settings
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	if len(project.Hooks.PreLogin) > 0 || len(project.Hooks.PreKubeconfig) > 0 || project.Launch.Command != "" ||
+		len(project.Endpoints) > 0 || project.CABundle != "" || project.Protected.Webhook != "" {
+		yellow.Printf("⚠️  Ignoring hooks, launch command, endpoints, CA bundle and webhook in project config %s\n", path)
+	}
+	// Colors are a personal choice, e.g. for color blindness
+	if project.Theme != (ThemeConfig{}) {
+		yellow.Printf("⚠️  Ignoring theme in project config %s\n", path)
+	}
+
+	hooks, launch, endpoints, caBundle := settings.Hooks, settings.Launch, settings.Endpoints, settings.CABundle
+	webhook, theme := settings.Protected.Webhook, settings.Theme
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	settings.Hooks, settings.Launch, settings.Endpoints, settings.CABundle = hooks, launch, endpoints, caBundle
+	settings.Protected.Webhook, settings.Theme = webhook, theme
+	settings.ProjectFile = path
+	return nil
+}
+
+// loadProjectSettings merges the project config found from the working directory, if any
+func (app *EKSLoginApp) loadProjectSettings() error {
+	if app.config.NoProjectConfig {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if path := FindProjectConfig(cwd); path != "" {
+		return MergeProjectSettings(app.settings, path)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// menuPageSize is how many items the arrow-key menu shows at once
+const menuPageSize = 12
+
+// menuKey is a key press understood by the arrow-key menu
+type menuKey int
+
+const (
+	keyOther menuKey = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyEscape
+	keyInterrupt
+	keyBackspace
+	keyRune
+)
+
+// errAborted is returned when the user leaves a menu with Esc
+var errAborted = withExitCode(ExitAborted, "aborted", errors.New("selection aborted"))
+
+// errInterrupted is returned when the user presses Ctrl-C in a menu. The
+// terminal is in raw mode, so no SIGINT is delivered.
+var errInterrupted = withExitCode(exitInterrupted, "interrupted", errors.New("interrupted"))
+
+// menuSupported reports whether the terminal can show the arrow-key menu
+func menuSupported() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// Select asks the user to pick one of items and returns its index. A
+// non-negative preselected index is highlighted and accepted by Enter alone.
+// Terminals get an arrow-key menu; dumb terminals and piped input get a
+// numbered list.
+func (app *EKSLoginApp) Select(label string, items []string, preselected int) (int, error) {
+	choices, err := app.choose(label, items, preselected, false)
+	if err != nil {
+		return 0, err
+	}
+	return choices[0], nil
+}
+
+// SelectMany is Select where several items can be picked: Space toggles items
+// in the menu, and the numbered list accepts comma-separated answers.
+func (app *EKSLoginApp) SelectMany(label string, items []string, preselected int) ([]int, error) {
+	return app.choose(label, items, preselected, true)
+}
+
+func (app *EKSLoginApp) choose(label string, items []string, preselected int, multi bool) ([]int, error) {
+	if !app.config.Interactive {
+		return nil, usageError("selecting a %s requires a prompt, but interactive mode is disabled", label)
+	}
+
+	return app.prompter.Select(label, items, preselected, multi)
+}
+
+// itemName returns the name an item can be selected by: its first word
+func itemName(item string) string {
+	if fields := strings.Fields(item); len(fields) > 0 {
+		return fields[0]
+	}
+	return item
+}
+
+// matchItem finds the item named input, or the only item whose name starts with it
+func matchItem(items []string, input string) (int, error) {
+	var matches []int
+	for i, item := range items {
+		name := itemName(item)
+		if name == input {
+			return i, nil
+		}
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(input)) {
+			matches = append(matches, i)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no entry matches %q", input)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, i := range matches {
+			names = append(names, itemName(items[i]))
+		}
+		return 0, fmt.Errorf("%q matches several entries: %s", input, strings.Join(names, ", "))
+	}
+}
+
+// parseAnswer resolves one numbered-list answer: a number, a name or a unique prefix
+func parseAnswer(items []string, answer string) (int, error) {
+	choice, err := strconv.Atoi(answer)
+	if err != nil {
+		return matchItem(items, answer)
+	}
+	if choice < 1 || choice > len(items) {
+		return 0, fmt.Errorf("choose a number between 1 and %d", len(items))
+	}
+	return choice - 1, nil
+}
+
+// selectNumbered prints the items with numbers and reads the chosen number(s)
+func (p *terminalPrompter) selectNumbered(label string, items []string, preselected int, multi bool) ([]int, error) {
+	for i, item := range items {
+		if i == preselected {
+			green.Printf("  %d. %s (last used)\n", i+1, item)
+			continue
+		}
+		fmt.Printf("  %d. %s\n", i+1, item)
+	}
+
+	hint := fmt.Sprintf("1-%d", len(items))
+	if multi {
+		hint += ", comma-separated for several"
+	}
+
+	for {
+		if preselected >= 0 {
+			yellow.Printf("\nSelect %s (%s) [%d]: ", label, hint, preselected+1)
+		} else {
+			yellow.Printf("\nSelect %s (%s): ", label, hint)
+		}
+		input, err := p.ReadLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" && preselected >= 0 {
+			return []int{preselected}, nil
+		}
+
+		answers := []string{input}
+		if multi {
+			answers = strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+		}
+
+		var choices []int
+		for _, answer := range answers {
+			choice, err := parseAnswer(items, answer)
+			if err != nil {
+				red.Printf("Invalid selection: %v. Enter a number or a name.\n", err)
+				choices = nil
+				break
+			}
+			if !slices.Contains(choices, choice) {
+				choices = append(choices, choice)
+			}
+		}
+		if len(choices) > 0 {
+			return choices, nil
+		}
+	}
+}
+
+// readKey reads one key press from the raw terminal. Printable characters
+// are returned with keyRune.
+func (p *terminalPrompter) readKey() (menuKey, rune, error) {
+	r, _, err := p.stdin.ReadRune()
+	if err != nil {
+		return keyOther, 0, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, 0, nil
+	case 3: // Ctrl-C
+		return keyInterrupt, 0, nil
+	case 8, 127:
+		return keyBackspace, 0, nil
+	case 0x1b:
+		// A lone Esc arrives by itself; arrow keys arrive as ESC [ A in one read
+		if p.stdin.Buffered() == 0 {
+			return keyEscape, 0, nil
+		}
+		next, _ := p.stdin.ReadByte()
+		if next != '[' && next != 'O' {
+			return keyOther, 0, nil
+		}
+		code, _ := p.stdin.ReadByte()
+		switch code {
+		case 'A':
+			return keyUp, 0, nil
+		case 'B':
+			return keyDown, 0, nil
+		}
+		return keyOther, 0, nil
+	}
+
+	if r >= ' ' {
+		return keyRune, r, nil
+	}
+	return keyOther, 0, nil
+}
+
+// jumpTo returns the first item whose name starts with query, or cursor if none does
+func jumpTo(items []string, query string, cursor int) int {
+	for i, item := range items {
+		if strings.HasPrefix(strings.ToLower(itemName(item)), strings.ToLower(query)) {
+			return i
+		}
+	}
+	return cursor
+}
+
+// menu is the state of an arrow-key menu
+type menu struct {
+	label       string
+	items       []string
+	cursor      int
+	preselected int
+	query       string
+	multi       bool
+	marked      map[int]bool
+	drawn       int
+}
+
+// selectMenu shows an arrow-key menu in raw terminal mode
+func (p *terminalPrompter) selectMenu(label string, items []string, preselected int, multi bool) ([]int, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return p.selectNumbered(label, items, preselected, multi)
+	}
+	defer term.Restore(fd, state)
+
+	out := color.Output
+	m := &menu{
+		label:       label,
+		items:       items,
+		cursor:      max(preselected, 0),
+		preselected: preselected,
+		multi:       multi,
+		marked:      make(map[int]bool),
+	}
+
+	for {
+		m.draw(out)
+
+		key, r, err := p.readKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		switch {
+		case key == keyUp:
+			m.cursor = (m.cursor - 1 + len(items)) % len(items)
+			m.query = ""
+		case key == keyDown:
+			m.cursor = (m.cursor + 1) % len(items)
+			m.query = ""
+		case key == keyRune && r == ' ' && multi:
+			m.marked[m.cursor] = !m.marked[m.cursor]
+			m.query = ""
+		case key == keyRune:
+			m.query += string(r)
+			m.cursor = jumpTo(items, m.query, m.cursor)
+		case key == keyBackspace:
+			if m.query != "" {
+				m.query = string([]rune(m.query)[:len([]rune(m.query))-1])
+				m.cursor = jumpTo(items, m.query, m.cursor)
+			}
+		case key == keyEnter:
+			choices := m.choices()
+			m.clear(out)
+			names := make([]string, len(choices))
+			for i, choice := range choices {
+				names[i] = items[choice]
+			}
+			yellow.Fprintf(out, "Select %s: ", label)
+			fmt.Fprintf(out, "%s\r\n", strings.Join(names, ", "))
+			return choices, nil
+		case key == keyEscape:
+			m.clear(out)
+			return nil, errAborted
+		case key == keyInterrupt:
+			m.clear(out)
+			return nil, errInterrupted
+		}
+	}
+}
+
+// choices returns the marked items in order, or the item under the cursor if none are marked
+func (m *menu) choices() []int {
+	var choices []int
+	for i := range m.items {
+		if m.marked[i] {
+			choices = append(choices, i)
+		}
+	}
+	if len(choices) == 0 {
+		choices = []int{m.cursor}
+	}
+	return choices
+}
+
+// clear erases the lines drawn by the previous draw call
+func (m *menu) clear(out io.Writer) {
+	if m.drawn > 0 {
+		fmt.Fprintf(out, "\x1b[%dA", m.drawn)
+	}
+	fmt.Fprint(out, "\r\x1b[J")
+	m.drawn = 0
+}
+
+// draw redraws the menu in place
+func (m *menu) draw(out io.Writer) {
+	m.clear(out)
+
+	start := 0
+	if len(m.items) > menuPageSize {
+		start = min(max(m.cursor-menuPageSize/2, 0), len(m.items)-menuPageSize)
+	}
+	end := min(start+menuPageSize, len(m.items))
+
+	yellow.Fprintf(out, "Select %s", m.label)
+	switch {
+	case m.query != "":
+		fmt.Fprintf(out, " › %s\r\n", m.query)
+	case m.multi:
+		fmt.Fprint(out, " (↑/↓ move, space toggle, type to jump, enter select, esc abort)\r\n")
+	default:
+		fmt.Fprint(out, " (↑/↓ move, type to jump, enter select, esc abort)\r\n")
+	}
+	m.drawn = 1
+
+	for i := start; i < end; i++ {
+		item := m.items[i]
+		if i == m.preselected {
+			item += " (last used)"
+		}
+		if m.multi {
+			if m.marked[i] {
+				item = "◉ " + item
+			} else {
+				item = "○ " + item
+			}
+		}
+		if i == m.cursor {
+			cyan.Fprintf(out, "❯ %s\r\n", item)
+		} else {
+			fmt.Fprintf(out, "  %s\r\n", item)
+		}
+		m.drawn++
+	}
+
+	if len(m.items) > menuPageSize {
+		fmt.Fprintf(out, "  (%d-%d of %d)\r\n", start+1, end, len(m.items))
+		m.drawn++
+	}
+}
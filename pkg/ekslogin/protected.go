@@ -0,0 +1,77 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProtectedConfig marks clusters that require typing their name before login
+type ProtectedConfig struct {
+	Clusters []string `yaml:"clusters,omitempty"`
+	Profiles []string `yaml:"profiles,omitempty"`
+	// Tags protects clusters whose tag values match the given glob patterns
+	Tags map[string]string `yaml:"tags,omitempty"`
+	// Webhook receives a JSON event (or a Slack message) for each login to a protected cluster
+	Webhook string `yaml:"webhook,omitempty"`
+}
+
+// matchesSome reports whether value matches one of the patterns; an empty list matches nothing
+func matchesSome(patterns []string, value string) bool {
+	return len(patterns) > 0 && matchesAny(patterns, value)
+}
+
+// IsProtected reports whether the selected cluster is marked as protected
+func (app *EKSLoginApp) IsProtected() bool {
+	protected := app.settings.Protected
+
+	if matchesSome(protected.Clusters, app.config.Cluster) || matchesSome(protected.Profiles, app.config.Profile) {
+		return true
+	}
+
+	if len(protected.Tags) == 0 {
+		return false
+	}
+
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return false
+	}
+	for key, pattern := range protected.Tags {
+		if value, ok := details.Tags[key]; ok && matchesAny([]string{pattern}, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfirmProtected requires the user to type the cluster name before logging in to a protected cluster
+func (app *EKSLoginApp) ConfirmProtected() error {
+	if !app.IsProtected() {
+		return nil
+	}
+
+	if app.config.ConfirmCluster != "" {
+		if app.config.ConfirmCluster != app.config.Cluster {
+			return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf("--confirm-cluster %q does not match protected cluster %q", app.config.ConfirmCluster, app.config.Cluster))
+		}
+		return nil
+	}
+	if !app.config.Interactive {
+		return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf("%s is a protected cluster; pass --confirm-cluster %s to confirm", app.config.Cluster, app.config.Cluster))
+	}
+
+	red.Printf("\n🚨 %s is a PROTECTED cluster (profile: %s, region: %s)\n", app.config.Cluster, app.config.Profile, app.config.Region)
+	red.Printf("🚨 Type the cluster name to continue: ")
+
+	input, err := app.prompter.ReadLine()
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	if strings.TrimSpace(input) != app.config.Cluster {
+		return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf("confirmation did not match, aborting login to protected cluster %s", app.config.Cluster))
+	}
+
+	green.Println("✓ Confirmed")
+	return nil
+}
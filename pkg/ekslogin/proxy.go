@@ -0,0 +1,106 @@
+package ekslogin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyConfig configures the proxy-url written into generated kubeconfig
+// cluster entries, for clusters only reachable through an HTTP or SOCKS proxy
+type ProxyConfig struct {
+	// URL applies to every cluster without a more specific entry
+	URL string `yaml:"url,omitempty"`
+	// Clusters maps cluster name globs to proxy URLs ("" for a direct
+	// connection); the most specific matching glob applies
+	Clusters map[string]string `yaml:"clusters,omitempty"`
+}
+
+// proxyURL returns the proxy for the selected cluster: --proxy-url, the most
+// specific matching proxy.clusters glob, or proxy.url
+func (app *EKSLoginApp) proxyURL() string {
+	if app.config.ProxyURL != "" {
+		return app.config.ProxyURL
+	}
+
+	proxy := app.settings.Proxy
+	if url, ok := mostSpecificMatch(proxy.Clusters, app.config.Cluster); ok {
+		return url
+	}
+	return proxy.URL
+}
+
+// parseProxyURL validates a proxy-url as kubectl accepts it
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, usageError("invalid proxy URL %q: %v", raw, err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, usageError("invalid proxy URL %q: scheme must be http, https or socks5", raw)
+	}
+	if proxy.Host == "" {
+		return nil, usageError("invalid proxy URL %q: no host", raw)
+	}
+	return proxy, nil
+}
+
+// clusterProxyURL returns the proxy kubectl uses for the API server at
+// endpoint: the proxy-url of its kubeconfig cluster entry, which may have been
+// edited by hand, or else the configured proxy of the selected cluster
+func (app *EKSLoginApp) clusterProxyURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if kubeconfig, err := LoadKubeconfig(KubeconfigPath()); err == nil {
+		for _, cluster := range kubeconfig.Clusters {
+			if cluster.Cluster.ProxyURL != "" && strings.TrimSuffix(cluster.Cluster.Server, "/") == endpoint {
+				return cluster.Cluster.ProxyURL
+			}
+		}
+	}
+	return app.proxyURL()
+}
+
+// useProxy routes an API client for the API server at endpoint through the
+// same proxy as kubectl, if any
+func (app *EKSLoginApp) useProxy(client *http.Client, endpoint string) error {
+	raw := app.clusterProxyURL(endpoint)
+	if raw == "" {
+		return nil
+	}
+	proxy, err := parseProxyURL(raw)
+	if err != nil {
+		return err
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return nil
+}
+
+// SetProxyURL writes the selected cluster's proxy into the cluster entry of
+// the current context. The caller saves the kubeconfig.
+func (app *EKSLoginApp) SetProxyURL(kubeconfig *Kubeconfig) error {
+	raw := app.proxyURL()
+	if raw == "" {
+		return nil
+	}
+	if _, err := parseProxyURL(raw); err != nil {
+		return err
+	}
+
+	path := KubeconfigPath()
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, path)
+	}
+	cluster := kubeconfig.Cluster(context.Cluster)
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found in %s", context.Cluster, path)
+	}
+
+	cluster.ProxyURL = raw
+	return nil
+}
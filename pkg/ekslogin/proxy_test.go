@@ -0,0 +1,55 @@
+package ekslogin
+
+import "testing"
+
+func TestProxyURLPicksMostSpecificGlob(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	app.settings.Proxy = ProxyConfig{
+		URL: "http://default:3128",
+		Clusters: map[string]string{
+			"*":         "http://any:3128",
+			"prod-*":    "http://prod:3128",
+			"prod-eu-*": "socks5://eu:1055",
+			"sandbox-?": "",
+		},
+	}
+
+	tests := []struct {
+		cluster string
+		flag    string
+		want    string
+	}{
+		{cluster: "prod-eu-1", want: "socks5://eu:1055"},
+		{cluster: "prod-us-1", want: "http://prod:3128"},
+		{cluster: "dev", want: "http://any:3128"},
+		{cluster: "sandbox-1", want: ""},
+		{cluster: "prod-eu-1", flag: "http://flag:8080", want: "http://flag:8080"},
+	}
+	for _, test := range tests {
+		app.config.Cluster, app.config.ProxyURL = test.cluster, test.flag
+		if got := app.proxyURL(); got != test.want {
+			t.Errorf("proxyURL(%q, flag %q) = %q, want %q", test.cluster, test.flag, got, test.want)
+		}
+	}
+
+	delete(app.settings.Proxy.Clusters, "*")
+	app.config.Cluster, app.config.ProxyURL = "dev", ""
+	if got := app.proxyURL(); got != "http://default:3128" {
+		t.Errorf("proxyURL without a matching glob = %q, want proxy.url", got)
+	}
+}
+
+func TestGlobLiterals(t *testing.T) {
+	tests := map[string]int{
+		"*":          0,
+		"prod-*":     5,
+		"prod-eu-*":  8,
+		"prod-[ab]?": 5,
+		`a\*b`:       3,
+	}
+	for pattern, want := range tests {
+		if got := globLiterals(pattern); got != want {
+			t.Errorf("globLiterals(%q) = %d, want %d", pattern, got, want)
+		}
+	}
+}
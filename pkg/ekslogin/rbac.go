@@ -0,0 +1,68 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rbacCheck is a single kubectl auth can-i probe
+type rbacCheck struct {
+	verb       string
+	resource   string
+	namespaced bool
+}
+
+var rbacChecks = []rbacCheck{
+	{verb: "get", resource: "pods", namespaced: true},
+	{verb: "list", resource: "namespaces"},
+	{verb: "create", resource: "deployments", namespaced: true},
+	{verb: "delete", resource: "pods", namespaced: true},
+	{verb: "*", resource: "*"},
+}
+
+// canI reports whether the current context may perform verb on resource
+func (app *EKSLoginApp) canI(check rbacCheck, namespace string) bool {
+	args := []string{"auth", "can-i", check.verb, check.resource, "--quiet"}
+	if check.namespaced {
+		args = append(args, "--namespace", namespace)
+	} else {
+		args = append(args, "--all-namespaces")
+	}
+	_, err := app.Execute("kubectl", args...)
+	return err == nil
+}
+
+// RBACSmokeTest runs a few auth can-i checks and prints a compact capability summary
+func (app *EKSLoginApp) RBACSmokeTest() {
+	namespace := app.config.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	blue.Printf("🛡️  Checking permissions (namespace: %s)...\n", namespace)
+
+	allowed := make(map[string]bool, len(rbacChecks))
+	results := make([]string, 0, len(rbacChecks))
+	for _, check := range rbacChecks {
+		label := fmt.Sprintf("%s %s", check.verb, check.resource)
+		ok := app.canI(check, namespace)
+		allowed[label] = ok
+		if ok {
+			results = append(results, green.Sprint("✓ ")+label)
+		} else {
+			results = append(results, red.Sprint("✗ ")+label)
+		}
+	}
+	fmt.Println("  " + strings.Join(results, "   "))
+
+	level := "none"
+	switch {
+	case allowed["* *"]:
+		level = "cluster admin"
+	case allowed["create deployments"]:
+		level = "read-write"
+	case allowed["get pods"]:
+		level = "read-only"
+	}
+	cyan.Printf("  Access level: %s\n", level)
+}
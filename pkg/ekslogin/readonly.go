@@ -0,0 +1,89 @@
+package ekslogin
+
+import (
+	"fmt"
+)
+
+// ReadOnlyConfig describes the identity impersonated by read-only contexts
+type ReadOnlyConfig struct {
+	// As is the Kubernetes user to impersonate; required by the API server
+	As string `yaml:"as,omitempty"`
+	// AsGroups are the Kubernetes groups to impersonate, e.g. a view-only group
+	AsGroups []string `yaml:"as_groups,omitempty"`
+	// Protected creates read-only contexts automatically for protected clusters
+	Protected bool `yaml:"protected,omitempty"`
+	// Suffix is appended to the writable context name (default "-readonly")
+	Suffix string `yaml:"suffix,omitempty"`
+}
+
+const defaultReadOnlySuffix = "-readonly"
+
+// wantsReadOnly reports whether a read-only context should be created for the selected cluster
+func (app *EKSLoginApp) wantsReadOnly() bool {
+	if app.config.ReadOnly {
+		return true
+	}
+	return app.settings.ReadOnly.Protected && app.IsProtected()
+}
+
+// CreateReadOnlyContext adds a context next to the current one that impersonates
+// the configured view-only identity, and makes it the current context. The
+// writable context is left in place to be selected explicitly.
+func (app *EKSLoginApp) CreateReadOnlyContext() error {
+	readOnly := app.settings.ReadOnly
+	if readOnly.As == "" {
+		return fmt.Errorf("read-only contexts require read_only.as in %s", app.config.ConfigFile)
+	}
+
+	suffix := readOnly.Suffix
+	if suffix == "" {
+		suffix = defaultReadOnlySuffix
+	}
+
+	lock, err := app.lockKubeconfig()
+	if err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	defer lock.Unlock()
+
+	app.BackupKubeconfig()
+
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+
+	writable := kubeconfig.CurrentContext
+	context := kubeconfig.Context(writable)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", writable, path)
+	}
+	user := kubeconfig.User(context.User)
+	if user == nil {
+		return fmt.Errorf("user %q not found in %s", context.User, path)
+	}
+
+	readOnlyUser := *user
+	if user.Exec != nil {
+		exec := *user.Exec
+		readOnlyUser.Exec = &exec
+	}
+	readOnlyUser.As = readOnly.As
+	readOnlyUser.AsGroups = readOnly.AsGroups
+	kubeconfig.SetUser(context.User+suffix, readOnlyUser)
+
+	readOnlyContext := *context
+	readOnlyContext.User = context.User + suffix
+	name := writable + suffix
+	kubeconfig.SetContext(name, readOnlyContext)
+	kubeconfig.CurrentContext = name
+
+	if err := kubeconfig.Save(path); err != nil {
+		return withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+
+	green.Printf("✓ Read-only context %s is now current\n", name)
+	cyan.Printf("   Writable context: kubectl config use-context %s\n", writable)
+	return nil
+}
@@ -0,0 +1,215 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// contextTarget returns an app targeting the cluster behind an eks-login
+// context, keeping its namespace, its user entry's name and the command
+// that user calls for tokens
+func (app *EKSLoginApp) contextTarget(kubeconfig *Kubeconfig, name string) (*EKSLoginApp, error) {
+	context := kubeconfig.Context(name)
+	if context == nil {
+		return nil, fmt.Errorf("context %q not found in %s", name, KubeconfigPath())
+	}
+	metadata := context.Metadata()
+	if metadata == nil || metadata.Profile == "" {
+		return nil, fmt.Errorf("context %q was not created by eks-login", name)
+	}
+
+	target := app.forTarget(metadata.Profile, metadata.Region, metadata.Cluster)
+	target.config.RoleARN = metadata.RoleARN
+	target.config.RoleDuration, _ = time.ParseDuration(metadata.RoleDuration)
+	target.config.Namespace = context.Namespace
+	if context.User != context.Cluster {
+		target.config.UserAlias = context.User
+	}
+	if user := kubeconfig.User(context.User); user != nil && user.Exec != nil &&
+		user.Exec.Command != "aws" && len(user.Exec.Args) > 0 && user.Exec.Args[0] == "token" {
+		target.config.TokenExec = "eks-login"
+	}
+	return target, nil
+}
+
+// rewriteContext writes the kubeconfig context of the selected cluster again
+// without prompting, and restores its default namespace
+func (app *EKSLoginApp) rewriteContext() error {
+	if err := app.RunHooks("pre-kubeconfig", app.settings.Hooks.PreKubeconfig); err != nil {
+		return err
+	}
+	// Organization contexts describe their cluster in the member account
+	if app.config.RoleARN != "" && app.credentials == nil {
+		creds, err := app.AssumeRole(app.config.RoleARN)
+		if err != nil {
+			return err
+		}
+		app.credentials = creds
+	}
+	return app.UpdateKubeconfig()
+}
+
+// Refresh renews the SSO session behind the current context and rewrites the
+// context, using the profile, region and cluster recorded in its metadata
+func (app *EKSLoginApp) Refresh() error {
+	if err := app.CheckDependencies(); err != nil {
+		return err
+	}
+
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return err
+	}
+	if kubeconfig.CurrentContext == "" {
+		return usageError("no current kubeconfig context to refresh")
+	}
+	target, err := app.contextTarget(kubeconfig, kubeconfig.CurrentContext)
+	if err != nil {
+		return usageError("%v; log in with eks-login first", err)
+	}
+
+	cyan.Printf("🔄 Refreshing %s (profile: %s, region: %s)\n", target.config.Cluster, target.config.Profile, target.config.Region)
+	err = target.ensureSession()
+	if err == nil {
+		err = target.rewriteContext()
+	}
+	target.Audit("refresh", err)
+	if err != nil {
+		return err
+	}
+
+	green.Printf("✓ Context %s refreshed\n", kubeconfig.CurrentContext)
+	return nil
+}
+
+// RefreshAll renews the SSO session of every profile behind an eks-login context,
+// logging in once per profile, and rewrites each of their contexts. Contexts
+// for the same cluster (such as read-only contexts) are rewritten once.
+func (app *EKSLoginApp) RefreshAll() error {
+	if err := app.CheckDependencies(); err != nil {
+		return err
+	}
+
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return err
+	}
+
+	byProfile := make(map[string][]*EKSLoginApp)
+	seen := make(map[LoginMetadata]bool)
+	for _, context := range kubeconfig.Contexts {
+		target, err := app.contextTarget(kubeconfig, context.Name)
+		if err != nil {
+			continue
+		}
+		if app.config.Profile != "" && target.config.Profile != app.config.Profile {
+			continue
+		}
+		key := LoginMetadata{Profile: target.config.Profile, Region: target.config.Region, Cluster: target.config.Cluster, RoleARN: target.config.RoleARN}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		byProfile[target.config.Profile] = append(byProfile[target.config.Profile], target)
+	}
+	if len(byProfile) == 0 {
+		yellow.Println("No eks-login contexts found in kubeconfig")
+		return nil
+	}
+
+	profiles := make([]string, 0, len(byProfile))
+	for profile := range byProfile {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	var refreshed int
+	var failures []string
+	for _, profile := range profiles {
+		targets := byProfile[profile]
+		cyan.Printf("\n📋 Profile: %s (%d context(s))\n", profile, len(targets))
+
+		if err := targets[0].ensureSession(); err != nil {
+			for _, target := range targets {
+				target.Audit("refresh", err)
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", profile, err))
+			continue
+		}
+
+		for _, target := range targets {
+			err := target.rewriteContext()
+			target.Audit("refresh", err)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s: %v", profile, target.config.Cluster, err))
+				continue
+			}
+			refreshed++
+		}
+	}
+
+	// Rewriting makes each context current in turn; switch back to the original
+	if current := kubeconfig.CurrentContext; current != "" {
+		if err := app.useContext(current); err != nil {
+			yellow.Printf("⚠️  Unable to restore current context %s: %v\n", current, err)
+		}
+	}
+
+	green.Printf("\n🎉 Refreshed %d context(s) across %d profile(s)\n", refreshed, len(profiles))
+	return printFailures(failures, "profile(s) or context(s)")
+}
+
+// useContext makes name the current kubeconfig context
+func (app *EKSLoginApp) useContext(name string) error {
+	lock, err := app.lockKubeconfig()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+	if kubeconfig.Context(name) == nil {
+		return fmt.Errorf("context %q not found in %s", name, path)
+	}
+	if kubeconfig.CurrentContext == name {
+		return nil
+	}
+	kubeconfig.CurrentContext = name
+	return kubeconfig.Save(path)
+}
+
+func newRefreshCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Renew the SSO session and kubeconfig entry of the current context",
+		Long: `Look up the profile, region and cluster eks-login recorded on the current
+kubeconfig context, log in to its SSO session if needed and write the context
+again, keeping its namespace. Nothing is prompted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.Refresh()
+		},
+	}
+}
+
+func newRefreshAllCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh-all",
+		Short: "Renew the SSO sessions and kubeconfig entries of all eks-login contexts",
+		Long: `Rewrite every kubeconfig context eks-login created, logging in to each
+profile's SSO session once, so one command restores access to all clusters
+after a reboot. --profile limits the run to that profile's contexts. The
+current context is kept.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.RefreshAll()
+		},
+	}
+}
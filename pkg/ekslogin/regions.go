@@ -0,0 +1,76 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClusterTarget is a cluster and the region it lives in
+type ClusterTarget struct {
+	Region  string
+	Cluster string
+
+	// Version and Status are only known when the list was sorted by them
+	Version string
+	Status  string
+}
+
+// regions returns the regions given with --region, or nil when none were given explicitly
+func (app *EKSLoginApp) regions() []string {
+	if !app.config.RegionSet {
+		return nil
+	}
+
+	var regions []string
+	for _, region := range app.config.Regions {
+		for _, part := range strings.Split(region, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				regions = append(regions, part)
+			}
+		}
+	}
+	return regions
+}
+
+// ListClusterTargets lists the clusters of the selected profile in each
+// region, listing up to --concurrency regions at once. Regions that fail are
+// reported and skipped; only when all of them fail is the first error returned.
+func (app *EKSLoginApp) ListClusterTargets(regions []string) ([]ClusterTarget, error) {
+	if len(regions) == 1 {
+		clusters, err := app.forTarget(app.config.Profile, regions[0], "").ListEKSClusters()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", regions[0], err)
+		}
+		targets := make([]ClusterTarget, len(clusters))
+		for i, cluster := range clusters {
+			targets[i] = ClusterTarget{Region: regions[0], Cluster: cluster}
+		}
+		return targets, nil
+	}
+
+	blue.Printf("📋 Fetching EKS clusters in %d regions...\n", len(regions))
+	progress := NewScanProgress(len(regions))
+	found := make([][]string, len(regions))
+	errs := make([]error, len(regions))
+	app.forEach(len(regions), func(i int) {
+		found[i], errs[i] = app.forTarget(app.config.Profile, regions[i], "").listClusters()
+		progress.Done(regions[i], len(found[i]), errs[i])
+	})
+	progress.Summary()
+
+	var targets []ClusterTarget
+	failed := 0
+	for i, region := range regions {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+		for _, cluster := range found[i] {
+			targets = append(targets, ClusterTarget{Region: region, Cluster: cluster})
+		}
+	}
+	if failed == len(regions) {
+		return nil, fmt.Errorf("%s: %w", regions[0], errs[0])
+	}
+	return targets, nil
+}
@@ -0,0 +1,87 @@
+package ekslogin
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBaseDelay   = 500 * time.Millisecond
+	maxRetryDelay      = 20 * time.Second
+)
+
+// throttlingErrors are the error codes that make an AWS call worth retrying
+var throttlingErrors = []string{
+	"Throttling",
+	"ThrottlingException",
+	"TooManyRequestsException",
+	"RequestLimitExceeded",
+	"Rate exceeded",
+}
+
+// RetryConfig configures retries of throttled AWS calls
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts,omitempty"`
+	BaseDelay   time.Duration `yaml:"base_delay,omitempty"`
+}
+
+// isThrottlingError reports whether err looks like AWS request throttling
+func isThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	for _, code := range throttlingErrors {
+		if strings.Contains(message, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxAttempts returns the attempt limit from --max-attempts, the config file or the default
+func (app *EKSLoginApp) maxAttempts() int {
+	switch {
+	case app.config.MaxAttempts > 0:
+		return app.config.MaxAttempts
+	case app.settings.Retry.MaxAttempts > 0:
+		return app.settings.Retry.MaxAttempts
+	default:
+		return defaultMaxAttempts
+	}
+}
+
+// backoff returns the jittered delay before retry number attempt (starting at 1)
+func (app *EKSLoginApp) backoff(attempt int) time.Duration {
+	base := app.settings.Retry.BaseDelay
+	if base <= 0 {
+		base = defaultBaseDelay
+	}
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	// Full jitter: sleep a random duration up to the exponential delay
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// withRetry runs operation, retrying throttled attempts with jittered exponential backoff
+func (app *EKSLoginApp) withRetry(operation func() (string, error)) (string, error) {
+	attempts := app.maxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		output, err := operation()
+		if err == nil || attempt >= attempts || !isThrottlingError(err) {
+			return output, err
+		}
+
+		delay := app.backoff(attempt)
+		yellow.Printf("⏳ AWS throttled the request, retrying in %s (attempt %d/%d)\n",
+			delay.Round(time.Millisecond), attempt+1, attempts)
+		time.Sleep(delay)
+	}
+}
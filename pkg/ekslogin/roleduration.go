@@ -0,0 +1,75 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bounds STS sets on the session duration of an assumed role; a role's own
+// maximum (MaxSessionDuration) may be lower
+const (
+	minRoleDuration = 15 * time.Minute
+	maxRoleDuration = 12 * time.Hour
+)
+
+// checkRoleDuration validates a requested role session duration; 0 keeps the
+// STS default of one hour
+func checkRoleDuration(duration time.Duration) error {
+	if duration == 0 {
+		return nil
+	}
+	if duration < minRoleDuration || duration > maxRoleDuration || duration%time.Second != 0 {
+		return fmt.Errorf("role session duration %s must be whole seconds between %s and %s", duration, minRoleDuration, maxRoleDuration)
+	}
+	return nil
+}
+
+// roleDuration returns the session duration requested for assumed roles:
+// --duration, then default.role_duration; 0 for the STS default
+func (app *EKSLoginApp) roleDuration() time.Duration {
+	if app.config.RoleDuration != 0 {
+		return app.config.RoleDuration
+	}
+	return app.settings.Default.RoleDuration
+}
+
+// roleDurationSeconds returns the DurationSeconds parameter of STS calls, or
+// "" to leave it out
+func (app *EKSLoginApp) roleDurationSeconds() string {
+	if duration := app.roleDuration(); duration != 0 {
+		return strconv.Itoa(int(duration.Seconds()))
+	}
+	return ""
+}
+
+// formatRoleDuration renders a role session duration for metadata and output,
+// e.g. "8h" or "1h30m"
+func formatRoleDuration(duration time.Duration) string {
+	if duration == 0 {
+		return ""
+	}
+	text := duration.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// roleDurationError explains STS refusing the requested session duration
+func (app *EKSLoginApp) roleDurationError(err error) error {
+	duration := app.roleDuration()
+	switch {
+	case duration == 0:
+		return err
+	case strings.Contains(err.Error(), "role chaining"):
+		return fmt.Errorf("%w; roles assumed with the credentials of another role, such as an SSO role, are limited to 1h by AWS: use --duration 1h or less", err)
+	case strings.Contains(err.Error(), "MaxSessionDuration"):
+		return fmt.Errorf("%w; --duration %s is longer than the role's maximum session duration, which an administrator can raise in IAM", err, formatRoleDuration(duration))
+	}
+	return err
+}
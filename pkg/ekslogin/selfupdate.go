@@ -0,0 +1,297 @@
+package ekslogin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ReleasePublicKey is the base64 ed25519 key that signs checksums.txt, injected
+// with -ldflags "-X eks-login/pkg/ekslogin.ReleasePublicKey=..." by 'make
+// release'. Self-update requires a valid checksums.txt.sig; builds without a
+// key can only install releases verified by their checksum with
+// --skip-signature.
+var ReleasePublicKey = ""
+
+// ReleaseAsset is a file attached to a GitHub release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the release asset with the given name
+func (r *Release) Asset(name string) *ReleaseAsset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// platformAsset returns the release archive built for this OS and architecture
+func (r *Release) platformAsset() *ReleaseAsset {
+	suffix := fmt.Sprintf("-%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		suffix = fmt.Sprintf("-%s-%s.zip", runtime.GOOS, runtime.GOARCH)
+	}
+	for i := range r.Assets {
+		if strings.HasPrefix(r.Assets[i].Name, "eks-login-") && strings.HasSuffix(r.Assets[i].Name, suffix) {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// download fetches url into memory
+func (app *EKSLoginApp) download(url string) ([]byte, error) {
+	client, err := app.HTTPClient(app.timeout())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, timeoutError(ctx, "download", app.timeout(), err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseChecksums reads a sha256sum-style file into a name -> digest map
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return sums
+}
+
+// verifyChecksums checks the signature of checksums.txt against the built-in
+// release key. Without a key the checksums only prove the download is intact,
+// not that it comes from the project, so that takes skipSignature.
+func (app *EKSLoginApp) verifyChecksums(release *Release, checksums []byte, skipSignature bool) error {
+	if ReleasePublicKey == "" {
+		if !skipSignature {
+			return usageError("this build has no release signing key to verify releases with; " +
+				"pass --skip-signature to rely on the SHA-256 checksum alone, or install a release build")
+		}
+		yellow.Println("⚠️  No release signing key built in; verifying the SHA-256 checksum only")
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid built-in release signing key")
+	}
+
+	asset := release.Asset("checksums.txt.sig")
+	if asset == nil {
+		return fmt.Errorf("release %s has no checksums.txt.sig", release.TagName)
+	}
+	encoded, err := app.download(asset.URL)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("failed to decode checksums signature: %w", err)
+	}
+
+	if !ed25519.Verify(key, checksums, signature) {
+		return fmt.Errorf("checksums.txt signature verification failed")
+	}
+	green.Println("✓ Release signature verified")
+	return nil
+}
+
+// extractBinary returns the eks-login executable inside a release archive
+func extractBinary(archive []byte, name string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		for _, file := range reader.File {
+			if strings.HasPrefix(path.Base(file.Name), "eks-login") && strings.HasSuffix(file.Name, ".exe") {
+				rc, err := file.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+		return nil, fmt.Errorf("no eks-login executable in %s", name)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no eks-login executable in %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && strings.HasPrefix(path.Base(header.Name), "eks-login") {
+			return io.ReadAll(reader)
+		}
+	}
+}
+
+// replaceExecutable atomically swaps the running binary for binary
+func replaceExecutable(binary []byte) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(executable), ".eks-login-update-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write new binary (try running with sudo): %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Chmod(0o755); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	// A running executable cannot be overwritten on Windows, but it can be renamed
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return "", fmt.Errorf("failed to replace %s: %w", executable, err)
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), executable); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	return executable, nil
+}
+
+// SelfUpdate replaces the running binary with the latest release for this platform
+func (app *EKSLoginApp) SelfUpdate(force, skipSignature bool) error {
+	blue.Println("🔍 Checking for the latest release...")
+	release, err := app.LatestRelease()
+	if err != nil {
+		return err
+	}
+
+	version, _, _ := buildInfo()
+	if !force && versionNumberPattern.MatchString(version) && compareVersions(version, release.TagName) >= 0 {
+		green.Printf("✓ eks-login %s is up to date\n", version)
+		return nil
+	}
+
+	asset := release.platformAsset()
+	if asset == nil {
+		return fmt.Errorf("release %s has no build for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	checksumsAsset := release.Asset("checksums.txt")
+	if checksumsAsset == nil {
+		return fmt.Errorf("release %s has no checksums.txt; refusing to install an unverified binary", release.TagName)
+	}
+
+	checksums, err := app.download(checksumsAsset.URL)
+	if err != nil {
+		return err
+	}
+	if err := app.verifyChecksums(release, checksums, skipSignature); err != nil {
+		return err
+	}
+	expected, ok := parseChecksums(checksums)[asset.Name]
+	if !ok {
+		return fmt.Errorf("checksums.txt has no entry for %s", asset.Name)
+	}
+
+	blue.Printf("⬇️  Downloading %s...\n", asset.Name)
+	archive, err := app.download(asset.URL)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(archive)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("checksum mismatch for %s: refusing to install", asset.Name)
+	}
+	green.Println("✓ Checksum verified")
+
+	binary, err := extractBinary(archive, asset.Name)
+	if err != nil {
+		return err
+	}
+
+	executable, err := replaceExecutable(binary)
+	if err != nil {
+		return err
+	}
+
+	green.Printf("✓ Updated %s from %s to %s\n", executable, version, release.TagName)
+	return nil
+}
+
+// newSelfUpdateCmd creates the self-update subcommand
+func newSelfUpdateCmd(app *EKSLoginApp) *cobra.Command {
+	var force, skipSignature bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Download, verify and install the latest eks-login release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.SelfUpdate(force, skipSignature)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Reinstall even if already up to date")
+	cmd.Flags().BoolVar(&skipSignature, "skip-signature", false, "Install without a signature check when this build has no release signing key (the SHA-256 checksum is still verified)")
+	return cmd
+}
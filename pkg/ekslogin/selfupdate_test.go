@@ -0,0 +1,65 @@
+package ekslogin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// A checksums.txt signed by the makefile's release step (openssl pkeyutl
+// -sign -rawin, base64) and the public key it injects
+const (
+	testReleasePublicKey = "GuNn65cbPVBwhXZIS1L3SIYMyH2/M++vPdnIArm3Tpg="
+	testChecksums        = "abc  file\n"
+	testChecksumsSig     = "jGXOJYYpInHqO5bXh559qq8yAbboJvklXu4ufHpNz+lhCfNhxjXPhDAHXqcmIgE+eq+UBKP2y/o54FK0WT4mBA=="
+)
+
+// signedRelease returns a release whose checksums.txt.sig is served with
+// signature
+func signedRelease(t *testing.T, signature string) *Release {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, signature+"\n")
+	}))
+	t.Cleanup(server.Close)
+	return &Release{TagName: "v9.9.9", Assets: []ReleaseAsset{{Name: "checksums.txt.sig", URL: server.URL + "/checksums.txt.sig"}}}
+}
+
+func withReleaseKey(t *testing.T, key string) {
+	t.Helper()
+	previous := ReleasePublicKey
+	ReleasePublicKey = key
+	t.Cleanup(func() { ReleasePublicKey = previous })
+}
+
+func TestVerifyChecksumsSignature(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	withReleaseKey(t, testReleasePublicKey)
+
+	if err := app.verifyChecksums(signedRelease(t, testChecksumsSig), []byte(testChecksums), false); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	if err := app.verifyChecksums(signedRelease(t, testChecksumsSig), []byte("abd  file\n"), false); err == nil {
+		t.Errorf("tampered checksums were accepted")
+	}
+	// A signature is required even with --skip-signature when a key is built in
+	if err := app.verifyChecksums(&Release{TagName: "v9.9.9"}, []byte(testChecksums), true); err == nil {
+		t.Errorf("release without checksums.txt.sig was accepted")
+	}
+}
+
+func TestVerifyChecksumsWithoutKey(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	withReleaseKey(t, "")
+
+	err := app.verifyChecksums(&Release{TagName: "v9.9.9"}, []byte(testChecksums), false)
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitUsage {
+		t.Errorf("unsigned build without --skip-signature: %v, want a usage error", err)
+	}
+	if err := app.verifyChecksums(&Release{TagName: "v9.9.9"}, []byte(testChecksums), true); err != nil {
+		t.Errorf("unsigned build with --skip-signature: %v", err)
+	}
+}
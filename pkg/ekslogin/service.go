@@ -0,0 +1,278 @@
+package ekslogin
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Names of the service installed by 'eks-login daemon install'
+const (
+	systemdUnitName = "eks-login.service"
+	launchdLabel    = "com.github.krutsko.eks-login"
+)
+
+// daemonArgs returns the arguments that start the daemon with the flags that
+// were given; the rest is left to the config file, so a reload can change it
+func (app *EKSLoginApp) daemonArgs(flags *daemonFlags) []string {
+	args := []string{"daemon"}
+	if flags.changed("interval") {
+		args = append(args, "--interval", flags.options.Interval.String())
+	}
+	if flags.changed("notify-before") {
+		args = append(args, "--notify-before", flags.options.NotifyBefore.String())
+	}
+	if flags.changed("no-notify") {
+		args = append(args, fmt.Sprintf("--no-notify=%t", flags.noNotify))
+	}
+	if flags.options.MetricsAddress != "" {
+		args = append(args, "--metrics-address", flags.options.MetricsAddress)
+	}
+	if flags.changed("profile") {
+		args = append(args, "--profile", app.config.Profile)
+	}
+	if app.config.ConfigFile != DefaultConfigPath() {
+		args = append(args, "--config", app.config.ConfigFile)
+	}
+	return args
+}
+
+// systemdQuote quotes a word of a systemd ExecStart line when needed
+func systemdQuote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\"'\\$%") {
+		return word
+	}
+	word = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`).Replace(word)
+	return `"` + word + `"`
+}
+
+// systemdUnit renders a systemd user unit that runs command
+func systemdUnit(command []string) string {
+	words := make([]string, len(command))
+	for i, word := range command {
+		words[i] = systemdQuote(word)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=eks-login SSO session watcher
+
+[Service]
+ExecStart=%s
+ExecReload=/bin/kill -HUP $MAINPID
+Environment=%s
+Restart=on-failure
+SuccessExitStatus=143
+
+[Install]
+WantedBy=default.target
+`, strings.Join(words, " "), systemdQuote("PATH="+os.Getenv("PATH")))
+}
+
+// xmlEscape escapes text for a plist string element
+func xmlEscape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// launchdPlist renders a launchd agent that runs command and logs to logPath
+func launchdPlist(command []string, logPath string) string {
+	var arguments strings.Builder
+	for _, word := range command {
+		fmt.Fprintf(&arguments, "\t\t<string>%s</string>\n", xmlEscape(word))
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>%s</string>
+	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, launchdLabel, arguments.String(), xmlEscape(os.Getenv("PATH")), xmlEscape(logPath), xmlEscape(logPath))
+}
+
+// servicePath returns where the service definition of this platform is installed
+func servicePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate config directory: %w", err)
+		}
+		return filepath.Join(dir, "systemd", "user", systemdUnitName), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist"), nil
+	default:
+		return "", fmt.Errorf("installing the daemon as a service is not supported on %s; run 'eks-login daemon' from your startup programs instead", runtime.GOOS)
+	}
+}
+
+// serviceCtl runs a service manager command, reporting its output on failure
+func (app *EKSLoginApp) serviceCtl(name string, args ...string) error {
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+
+	var output bytes.Buffer
+	err := app.run(ctx, Command{Name: name, Args: args, Env: os.Environ(), Stdout: &output, Stderr: &output})
+	if err != nil {
+		detail := strings.TrimSpace(output.String())
+		if detail == "" {
+			detail = err.Error()
+		}
+		return fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), detail)
+	}
+	return nil
+}
+
+// InstallDaemon writes a systemd user unit or launchd agent that starts the
+// daemon on login, and starts it
+func (app *EKSLoginApp) InstallDaemon(flags *daemonFlags) error {
+	path, err := servicePath()
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the eks-login executable: %w", err)
+	}
+	command := append([]string{executable}, app.daemonArgs(flags)...)
+
+	var definition string
+	if runtime.GOOS == "darwin" {
+		logPath := filepath.Join(filepath.Dir(filepath.Dir(path)), "Logs", "eks-login.log")
+		definition = launchdPlist(command, logPath)
+	} else {
+		definition = systemdUnit(command)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(definition), 0o644); err != nil {
+		return fmt.Errorf("failed to write service definition: %w", err)
+	}
+	green.Printf("✓ Wrote %s\n", path)
+
+	if runtime.GOOS == "darwin" {
+		app.serviceCtl("launchctl", "unload", path)
+		err = app.serviceCtl("launchctl", "load", "-w", path)
+	} else {
+		err = app.serviceCtl("systemctl", "--user", "daemon-reload")
+		if err == nil {
+			err = app.serviceCtl("systemctl", "--user", "enable", "--now", systemdUnitName)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	green.Println("✓ Daemon installed and started; it will start automatically on login")
+	return nil
+}
+
+// UninstallDaemon stops the daemon service and removes its definition
+func (app *EKSLoginApp) UninstallDaemon() error {
+	path, err := servicePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		yellow.Println("Daemon is not installed")
+		return nil
+	}
+
+	if runtime.GOOS == "darwin" {
+		err = app.serviceCtl("launchctl", "unload", "-w", path)
+	} else {
+		err = app.serviceCtl("systemctl", "--user", "disable", "--now", systemdUnitName)
+	}
+	if err != nil {
+		yellow.Printf("⚠️  %v\n", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove service definition: %w", err)
+	}
+	if runtime.GOOS == "linux" {
+		app.serviceCtl("systemctl", "--user", "daemon-reload")
+	}
+
+	green.Printf("✓ Removed %s\n", path)
+	return nil
+}
+
+// addDaemonFlags registers the options shared by daemon and daemon install
+func addDaemonFlags(cmd *cobra.Command, flags *daemonFlags) {
+	cmd.Flags().DurationVar(&flags.options.Interval, "interval", defaultDaemonInterval, "How often to check the sessions")
+	cmd.Flags().DurationVar(&flags.options.NotifyBefore, "notify-before", defaultDaemonNotifyBefore, "Notify this long before a session expires")
+	cmd.Flags().BoolVar(&flags.noNotify, "no-notify", false, "Only log expiring sessions, without desktop notifications")
+	cmd.Flags().StringVar(&flags.options.MetricsAddress, "metrics-address", "", "Serve Prometheus metrics on this address (e.g. 127.0.0.1:9464) and refresh tokens every interval")
+	flags.changed = func(name string) bool {
+		return cmd.Flags().Changed(name)
+	}
+}
+
+// newDaemonInstallCmd creates the daemon install subcommand
+func newDaemonInstallCmd(app *EKSLoginApp) *cobra.Command {
+	flags := &daemonFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Start the daemon on login (systemd user unit or launchd agent)",
+		Long: `Start the daemon on login (systemd user unit or launchd agent). Only the
+flags given here are written into the service; everything else comes from the
+daemon section of the config file, which 'systemctl --user reload eks-login'
+(or sending the daemon SIGHUP) reads again.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.changed("interval") && flags.options.Interval <= 0 {
+				return usageError("--interval must be positive")
+			}
+			return app.InstallDaemon(flags)
+		},
+	}
+
+	addDaemonFlags(cmd, flags)
+	return cmd
+}
+
+// newDaemonUninstallCmd creates the daemon uninstall subcommand
+func newDaemonUninstallCmd(app *EKSLoginApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop the daemon service and remove it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.UninstallDaemon()
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings holds the options loaded from the eks-login config file
+type Settings struct {
+	// Version is the format of the file; older files are migrated on load
+	Version int `yaml:"version,omitempty"`
+
+	// Default supplies values for flags that are not given
+	Default DefaultsConfig `yaml:"default,omitempty"`
+
+	// Presets are named environments for 'eks-login use'
+	Presets map[string]Preset `yaml:"presets,omitempty"`
+
+	// Aliases are shortcuts for 'eks-login NAME'
+	Aliases map[string]Preset `yaml:"aliases,omitempty"`
+
+	Hooks  HooksConfig  `yaml:"hooks,omitempty"`
+	Launch LaunchConfig `yaml:"launch,omitempty"`
+
+	// FIPS routes all AWS calls through FIPS endpoints
+	FIPS bool `yaml:"fips,omitempty"`
+
+	// Endpoints overrides AWS endpoints per service ("default" applies to all)
+	Endpoints map[string]string `yaml:"endpoints,omitempty"`
+
+	// CABundle is a PEM bundle trusted for AWS and cluster connections
+	CABundle string `yaml:"ca_bundle,omitempty"`
+
+	// Timeout limits each AWS/kubectl operation
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Retry configures retries of throttled AWS calls
+	Retry RetryConfig `yaml:"retry,omitempty"`
+
+	// Protected marks clusters that require an explicit confirmation
+	Protected ProtectedConfig `yaml:"protected,omitempty"`
+
+	// ReadOnly configures the impersonated identity of read-only contexts
+	ReadOnly ReadOnlyConfig `yaml:"read_only,omitempty"`
+
+	// Backups controls kubeconfig snapshots taken before modifications
+	Backups BackupConfig `yaml:"backups,omitempty"`
+
+	// Updates controls the opt-in check for newer releases
+	Updates UpdatesConfig `yaml:"updates,omitempty"`
+
+	// Audit configures the local log of logins and logouts
+	Audit AuditConfig `yaml:"audit,omitempty"`
+
+	// Kubeconfig selects between one merged kubeconfig and a file per cluster
+	Kubeconfig KubeconfigLayoutConfig `yaml:"kubeconfig,omitempty"`
+
+	// Proxy configures the proxy-url of generated kubeconfig cluster entries
+	Proxy ProxyConfig `yaml:"proxy,omitempty"`
+
+	// Tunnel configures 'eks-login tunnel' for private clusters
+	Tunnel TunnelConfig `yaml:"tunnel,omitempty"`
+
+	// Tmux configures the 'eks-login tmux-status' segment
+	Tmux TmuxConfig `yaml:"tmux,omitempty"`
+
+	// STS selects the STS endpoint EKS tokens are signed for
+	STS STSConfig `yaml:"sts,omitempty"`
+
+	// Daemon configures the session watcher of 'eks-login daemon'
+	Daemon DaemonConfig `yaml:"daemon,omitempty"`
+
+	// Theme assigns the colors of the output
+	Theme ThemeConfig `yaml:"theme,omitempty"`
+
+	// ProjectFile is the per-project config merged into these settings, if any
+	ProjectFile string `yaml:"-"`
+
+	// AccessContact is who users should ask for cluster access, e.g. "#platform on Slack"
+	AccessContact string `yaml:"access_contact,omitempty"`
+}
+
+// DefaultConfigPath returns the location of the config file, honoring EKS_LOGIN_CONFIG
+func DefaultConfigPath() string {
+	if path := os.Getenv("EKS_LOGIN_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "eks-login", "config.yaml")
+}
+
+// LoadSettings reads the config file at path. A missing file yields empty
+// settings; a file of an older version is migrated first.
+func LoadSettings(path string) (*Settings, error) {
+	settings := &Settings{}
+	if path == "" {
+		return settings, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return settings, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if data, err = migrateConfigFile(path, data); err != nil {
+		return nil, err
+	}
+	if issues := ValidateConfig(data); len(issues) > 0 {
+		return nil, withExitCode(ExitUsage, "invalid_config", &ConfigError{Path: path, Issues: issues})
+	}
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return settings, nil
+}
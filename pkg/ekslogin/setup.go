@@ -0,0 +1,303 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSSORegion is offered when the SSO region is not given
+const defaultSSORegion = "us-east-1"
+
+// profileNameUnsafe matches characters left out of generated profile names
+var profileNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// SetupOptions are the flags of 'eks-login setup'; empty values are asked for
+type SetupOptions struct {
+	StartURL  string
+	SSORegion string
+	Session   string
+	Account   string
+	Role      string
+	Name      string
+}
+
+// SSOAccount is an AWS account the SSO user has access to
+type SSOAccount struct {
+	AccountID   string `json:"accountId"`
+	AccountName string `json:"accountName"`
+}
+
+// ask returns value if set, and otherwise asks for it on the terminal,
+// offering def. Without a prompt def is used, or the flag is required.
+func (app *EKSLoginApp) ask(label, flag, value, def string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if !app.config.Interactive {
+		if def != "" {
+			return def, nil
+		}
+		return "", usageError("%s is required: pass %s", label, flag)
+	}
+
+	for {
+		if def != "" {
+			cyan.Printf("%s [%s]: ", label, def)
+		} else {
+			cyan.Printf("%s: ", label)
+		}
+		input, err := app.prompter.ReadLine()
+		input = strings.TrimSpace(input)
+		if input == "" {
+			input = def
+		}
+		if input != "" {
+			return input, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+}
+
+// ensureSSOSessionLogin logs in to the sso-session unless a valid token is cached
+func (app *EKSLoginApp) ensureSSOSessionLogin(session string) (*ssoCacheEntry, error) {
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+	settings := map[string]string{"sso_session": session}
+	if entry, _, err := ssoSession(files, settings); err == nil {
+		green.Println("✓ SSO session is valid")
+		return entry, nil
+	}
+
+	blue.Println("🔐 Logging in to AWS SSO...")
+	ctx, cancel := app.withTimeout(app.loginTimeout())
+	defer cancel()
+	err = app.run(ctx, Command{Name: "aws", Args: []string{"sso", "login", "--sso-session", session}, Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr})
+	if err != nil {
+		return nil, withExitCode(ExitSSOLoginFailed, "sso_login_failed", fmt.Errorf("SSO login failed: %w", timeoutError(ctx, "aws sso login", app.loginTimeout(), err)))
+	}
+	green.Println("✓ SSO login successful")
+
+	entry, _, err := ssoSession(files, settings)
+	return entry, err
+}
+
+// ListSSOAccounts lists the accounts the SSO session can access
+func (app *EKSLoginApp) ListSSOAccounts(token, region string) ([]SSOAccount, error) {
+	spinner := app.StartSpinner("Listing SSO accounts")
+	output, err := app.Execute("aws", "sso", "list-accounts", "--access-token", token, "--region", region, "--output", "json")
+	spinner.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSO accounts: %w", err)
+	}
+
+	var response struct {
+		AccountList []SSOAccount `json:"accountList"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse SSO accounts: %w", err)
+	}
+	sort.Slice(response.AccountList, func(i, j int) bool {
+		return response.AccountList[i].AccountName < response.AccountList[j].AccountName
+	})
+	return response.AccountList, nil
+}
+
+// Setup walks through the SSO start URL, region, account and role, and
+// writes the resulting sso-session and profile to the AWS config file
+func (app *EKSLoginApp) Setup(opts SetupOptions) error {
+	path := awsFile("AWS_CONFIG_FILE", "config")
+	blue.Printf("🧭 Setting up an AWS SSO profile in %s\n\n", path)
+
+	startURL, err := app.ask("SSO start URL", "--start-url", opts.StartURL, "")
+	if err != nil {
+		return err
+	}
+	parsed, err := url.Parse(startURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return usageError("invalid SSO start URL %q, e.g. https://my-company.awsapps.com/start", startURL)
+	}
+	ssoRegion, err := app.ask("SSO region", "--sso-region", opts.SSORegion, defaultSSORegion)
+	if err != nil {
+		return err
+	}
+	session, err := app.ask("SSO session name", "--session", opts.Session, strings.Split(parsed.Hostname(), ".")[0])
+	if err != nil {
+		return err
+	}
+
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		return err
+	}
+	if existing, ok := files.config["sso-session "+session]; ok && existing["sso_start_url"] != startURL {
+		return usageError("sso-session %s already exists for %s; choose another --session name", session, existing["sso_start_url"])
+	}
+	if err := setINISection(path, "sso-session "+session, map[string]string{
+		"sso_start_url":           startURL,
+		"sso_region":              ssoRegion,
+		"sso_registration_scopes": "sso:account:access",
+	}); err != nil {
+		return err
+	}
+
+	entry, err := app.ensureSSOSessionLogin(session)
+	if err != nil {
+		return err
+	}
+	if files, err = loadAWSConfigFiles(); err != nil {
+		return err
+	}
+
+	accounts, err := app.ListSSOAccounts(entry.AccessToken, ssoRegion)
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		return fmt.Errorf("no AWS accounts are assigned to you in %s", startURL)
+	}
+	account := -1
+	switch {
+	case opts.Account != "":
+		for i, a := range accounts {
+			if a.AccountID == opts.Account || strings.EqualFold(a.AccountName, opts.Account) {
+				account = i
+			}
+		}
+		if account < 0 {
+			return usageError("account %s is not assigned to you in %s", opts.Account, startURL)
+		}
+	case len(accounts) == 1:
+		account = 0
+	default:
+		items := make([]string, len(accounts))
+		for i, a := range accounts {
+			items[i] = fmt.Sprintf("%s (%s)", a.AccountName, a.AccountID)
+		}
+		blue.Println("\n🏢 Accounts:")
+		if account, err = app.Select("account", items, -1); err != nil {
+			return err
+		}
+	}
+	selected := accounts[account]
+	cyan.Printf("🏢 Account: %s (%s)\n", selected.AccountName, selected.AccountID)
+
+	settings := map[string]string{"sso_session": session, "sso_account_id": selected.AccountID}
+	roles, err := app.ListSSORoles(files, settings)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("no SSO roles are assigned to you in account %s", selected.AccountID)
+	}
+	role := ""
+	switch {
+	case opts.Role != "":
+		for _, r := range roles {
+			if r.RoleName == opts.Role {
+				role = r.RoleName
+			}
+		}
+		if role == "" {
+			return usageError("SSO role %s is not assigned to you in account %s", opts.Role, selected.AccountID)
+		}
+	case len(roles) == 1:
+		role = roles[0].RoleName
+	default:
+		items := make([]string, len(roles))
+		for i, r := range roles {
+			items[i] = r.RoleName
+		}
+		blue.Println("\n🎭 Roles:")
+		choice, err := app.Select("role", items, -1)
+		if err != nil {
+			return err
+		}
+		role = roles[choice].RoleName
+	}
+	cyan.Printf("🎭 Role: %s\n", role)
+
+	region := ""
+	if app.config.RegionSet {
+		region = app.config.Region
+	}
+	if region, err = app.ask("Default region for EKS clusters", "--region", region, ssoRegion); err != nil {
+		return err
+	}
+
+	def := profileNameUnsafe.ReplaceAllString(strings.ToLower(selected.AccountName+"-"+role), "-")
+	name, err := app.ask("Profile name", "--profile", opts.Name, def)
+	if err != nil {
+		return err
+	}
+	if _, ok := files.profile(name); ok {
+		if !app.config.Interactive {
+			return usageError("profile %s already exists; pass another --profile", name)
+		}
+		yellow.Printf("⚠️  Profile %s already exists. Replace it? [y/N]: ", name)
+		input, _ := app.prompter.ReadLine()
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			return errAborted
+		}
+	}
+
+	if err := setINISection(path, "profile "+name, map[string]string{
+		"sso_session":    session,
+		"sso_account_id": selected.AccountID,
+		"sso_role_name":  role,
+		"region":         region,
+		"output":         "json",
+	}); err != nil {
+		return err
+	}
+
+	green.Printf("\n✓ Profile %s written to %s\n", name, path)
+	fmt.Printf("   Log in with: eks-login --profile %s\n", name)
+	return nil
+}
+
+func newSetupCmd(app *EKSLoginApp) *cobra.Command {
+	var opts SetupOptions
+
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Create an AWS SSO profile interactively",
+		Long: `Walk through the SSO start URL and region, log in, pick an account and a
+role from the ones assigned to you, and write the sso-session and profile to
+~/.aws/config (or AWS_CONFIG_FILE): a shorter 'aws configure sso' for EKS users.
+Values given as flags are not asked for; without a prompt, --start-url,
+--account and --role are required.`,
+		Example: `  eks-login setup
+  eks-login setup --start-url https://my-company.awsapps.com/start --sso-region eu-west-1
+  eks-login setup --start-url https://my-company.awsapps.com/start --account 123456789012 --role ReadOnly --profile prod-ro --region eu-west-1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.CheckDependencies(); err != nil {
+				return err
+			}
+			// --profile names the new profile; AWS calls must not use it
+			if cmd.Flags().Changed("profile") {
+				opts.Name = app.config.Profile
+			}
+			app.config.Profile = ""
+			return app.Setup(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.StartURL, "start-url", "", "SSO start URL, e.g. https://my-company.awsapps.com/start")
+	cmd.Flags().StringVar(&opts.SSORegion, "sso-region", "", "Region of the SSO instance (default us-east-1)")
+	cmd.Flags().StringVar(&opts.Session, "session", "", "Name of the sso-session section (default from the start URL)")
+	cmd.Flags().StringVar(&opts.Account, "account", "", "Account ID or name to use")
+	cmd.Flags().StringVar(&opts.Role, "role", "", "SSO role (permission set) to use")
+	return cmd
+}
@@ -0,0 +1,98 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CachedStatus describes the current context and its SSO session expiry using
+// only kubeconfig and cached files, without running the AWS CLI
+func (app *EKSLoginApp) CachedStatus() (*LoginStatus, error) {
+	status, metadata, err := currentContext()
+	if err != nil || metadata == nil {
+		return status, err
+	}
+
+	if path := app.LoadState().SSOTokens[metadata.Profile]; path != "" {
+		if expiry, err := readSSOExpiry(path); err == nil {
+			status.SSOExpiresAt = &expiry
+		}
+	}
+	return status, nil
+}
+
+// remaining renders the time left in the SSO session, "expired", or "" when unknown
+func (s *LoginStatus) remaining() string {
+	if s.SSOExpiresAt == nil {
+		return ""
+	}
+	left := time.Until(*s.SSOExpiresAt)
+	if left <= 0 {
+		return "expired"
+	}
+	return formatRemaining(left)
+}
+
+// defaultSegmentFormat renders "cluster@namespace (2h13m)", leaving out what is unknown
+func defaultSegmentFormat(status *LoginStatus) string {
+	format := "{cluster}"
+	if !status.Managed {
+		format = "{context}"
+	}
+	if status.Namespace != "" {
+		format += "@{namespace}"
+	}
+	if status.SSOExpiresAt != nil {
+		format += " ({remaining})"
+	}
+	return format
+}
+
+// FormatSegment expands the placeholders {context}, {cluster}, {namespace},
+// {profile}, {region} and {remaining} of format
+func (s *LoginStatus) FormatSegment(format string) string {
+	return strings.NewReplacer(
+		"{context}", s.Context,
+		"{cluster}", s.Cluster,
+		"{namespace}", s.Namespace,
+		"{profile}", s.Profile,
+		"{region}", s.Region,
+		"{remaining}", s.remaining(),
+	).Replace(format)
+}
+
+// newPromptCmd creates the prompt subcommand
+func newPromptCmd(app *EKSLoginApp) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a compact context/session segment for shell prompts",
+		Long: `Print the current cluster, namespace and remaining SSO session time, e.g.
+"prod-api@payments (2h13m)", for embedding in PS1 or starship. Only kubeconfig
+and cached files are read, so it is fast enough to run on every prompt.
+
+Prints nothing when there is no current context.
+
+Placeholders for --format: {context}, {cluster}, {namespace}, {profile},
+{region} and {remaining}.`,
+		Example: `  PS1='$(eks-login prompt) \$ '`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := app.CachedStatus()
+			if err != nil || status.Context == "" {
+				return nil
+			}
+			if format == "" {
+				format = defaultSegmentFormat(status)
+			}
+			fmt.Println(status.FormatSegment(format))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Segment format (default \"{cluster}@{namespace} ({remaining})\")")
+	return cmd
+}
@@ -0,0 +1,122 @@
+package ekslogin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// interruptGrace is how long cancelled subprocesses get to exit before we force our own exit
+const interruptGrace = 3 * time.Second
+
+// Exit codes used when the run is interrupted by a signal
+const (
+	exitInterrupted = 130 // 128 + SIGINT
+	exitTerminated  = 143 // 128 + SIGTERM
+)
+
+// lifecycle tracks the cleanups and exit code shared by all copies of the app
+type lifecycle struct {
+	mu       sync.Mutex
+	cleanups []func()
+	exitCode int
+	// backedUp holds the kubeconfigs this run has snapshotted
+	backedUp map[string]bool
+}
+
+// ExitCode returns the exit code recorded by a signal, or 0
+func (l *lifecycle) ExitCode() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.exitCode
+}
+
+func (l *lifecycle) setExitCode(code int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitCode = code
+}
+
+// markBackedUp records that this run snapshots the kubeconfig at path, and
+// reports whether it had not done so yet
+func (l *lifecycle) markBackedUp(path string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.backedUp[path] {
+		return false
+	}
+	if l.backedUp == nil {
+		l.backedUp = map[string]bool{}
+	}
+	l.backedUp[path] = true
+	return true
+}
+
+// AddCleanup registers fn to run when the program exits, including on interrupt
+func (app *EKSLoginApp) AddCleanup(fn func()) {
+	app.lifecycle.mu.Lock()
+	defer app.lifecycle.mu.Unlock()
+	app.lifecycle.cleanups = append(app.lifecycle.cleanups, fn)
+}
+
+// RunCleanups runs the registered cleanups once, most recent first
+func (app *EKSLoginApp) RunCleanups() {
+	app.lifecycle.mu.Lock()
+	cleanups := app.lifecycle.cleanups
+	app.lifecycle.cleanups = nil
+	app.lifecycle.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+// HandleSignals returns a context cancelled on SIGINT/SIGTERM. Cancelling it kills
+// in-flight subprocesses; if the run does not return within interruptGrace (e.g. it is
+// blocked on a prompt) the process cleans up and exits on its own.
+func (app *EKSLoginApp) HandleSignals(done <-chan struct{}) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-done:
+			signal.Stop(signals)
+			return
+		}
+
+		if sig == syscall.SIGTERM {
+			app.lifecycle.setExitCode(exitTerminated)
+		} else {
+			app.lifecycle.setExitCode(exitInterrupted)
+		}
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(interruptGrace):
+			app.ExitInterrupted()
+		}
+	}()
+
+	return ctx
+}
+
+// ExitInterrupted restores the terminal, runs cleanups and exits with the signal's exit code
+func (app *EKSLoginApp) ExitInterrupted() {
+	// Reset any color left active and move off a half-typed prompt line
+	fmt.Fprint(color.Output, "\x1b[0m\n")
+	yellow.Println("⚠️  Interrupted")
+	app.RunCleanups()
+	os.Exit(app.lifecycle.ExitCode())
+}
@@ -0,0 +1,137 @@
+package ekslogin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sigv4Algorithm identifies AWS Signature Version 4 signatures
+const sigv4Algorithm = "AWS4-HMAC-SHA256"
+
+// sigv4Signer signs requests for one service and region with SigV4
+type sigv4Signer struct {
+	credentials *AWSCredentials
+	region      string
+	service     string
+	now         time.Time
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// scope returns the credential scope, e.g. "20240101/us-east-1/sts/aws4_request"
+func (s sigv4Signer) scope() string {
+	return strings.Join([]string{s.now.Format("20060102"), s.region, s.service, "aws4_request"}, "/")
+}
+
+// signature signs the canonical form of a request
+func (s sigv4Signer) signature(method, path, query string, headers http.Header, payloadHash string) (signature, signedHeaders string) {
+	lower := make(map[string][]string, len(headers))
+	names := make([]string, 0, len(headers))
+	for name, values := range headers {
+		name = strings.ToLower(name)
+		if _, seen := lower[name]; !seen {
+			names = append(names, name)
+		}
+		for _, value := range values {
+			lower[name] = append(lower[name], strings.TrimSpace(value))
+		}
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.Join(lower[name], ","))
+	}
+	signedHeaders = strings.Join(names, ";")
+
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		method, path, query, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		sigv4Algorithm,
+		s.now.Format("20060102T150405Z"),
+		s.scope(),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.credentials.SecretAccessKey), s.now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign)), signedHeaders
+}
+
+// canonicalQuery encodes query parameters the way SigV4 expects (%20, not +)
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// Presign returns u as a GET URL authenticated by its query string and valid for
+// expires; headers are signed and must be sent along with the request
+func (s sigv4Signer) Presign(u url.URL, headers http.Header, expires time.Duration) string {
+	s.now = s.now.UTC()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers = headers.Clone()
+	headers.Set("Host", u.Host)
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigv4Algorithm)
+	query.Set("X-Amz-Credential", s.credentials.AccessKeyID+"/"+s.scope())
+	query.Set("X-Amz-Date", s.now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", fmt.Sprint(int(expires.Seconds())))
+	if s.credentials.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	query.Set("X-Amz-SignedHeaders", strings.Join(names, ";"))
+
+	signature, _ := s.signature(http.MethodGet, u.EscapedPath(), canonicalQuery(query), headers, sha256Hex(nil))
+	u.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// Sign adds SigV4 authorization headers to req, whose body is body
+func (s sigv4Signer) Sign(req *http.Request, body []byte) {
+	s.now = s.now.UTC()
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", s.now.Format("20060102T150405Z"))
+	if s.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+
+	headers := http.Header{"Host": {req.URL.Host}}
+	for name, values := range req.Header {
+		headers[name] = append([]string(nil), values...)
+	}
+
+	signature, signedHeaders := s.signature(req.Method, req.URL.EscapedPath(), canonicalQuery(req.URL.Query()), headers, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigv4Algorithm, s.credentials.AccessKeyID, s.scope(), signedHeaders, signature))
+}
@@ -0,0 +1,70 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxKubectlSkew is the number of minor versions kubectl supports on either side of the server
+const maxKubectlSkew = 1
+
+// KubectlClientVersion returns the version of the local kubectl client
+func (app *EKSLoginApp) KubectlClientVersion() (*ServerVersion, error) {
+	output, err := app.Execute("kubectl", "version", "--client", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var version struct {
+		ClientVersion ServerVersion `json:"clientVersion"`
+	}
+	if err := json.Unmarshal([]byte(output), &version); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl version: %w", err)
+	}
+
+	return &version.ClientVersion, nil
+}
+
+// MinorVersion returns the numeric minor version (EKS reports values like "29+")
+func (v *ServerVersion) MinorVersion() (int, error) {
+	return strconv.Atoi(strings.TrimRight(v.Minor, "+"))
+}
+
+// CheckVersionSkew warns when kubectl is outside the supported skew of the server version
+func (app *EKSLoginApp) CheckVersionSkew(server *ServerVersion) {
+	if !app.kubectlAvailable {
+		return
+	}
+
+	client, err := app.KubectlClientVersion()
+	if err != nil {
+		return
+	}
+
+	serverMinor, err := server.MinorVersion()
+	if err != nil {
+		return
+	}
+	clientMinor, err := client.MinorVersion()
+	if err != nil {
+		return
+	}
+
+	skew := clientMinor - serverMinor
+	if skew >= -maxKubectlSkew && skew <= maxKubectlSkew {
+		return
+	}
+
+	yellow.Printf("⚠️  kubectl %s is %d minor versions away from the cluster (%s); only ±%d is supported\n",
+		client.GitVersion, abs(skew), server.GitVersion, maxKubectlSkew)
+	fmt.Printf("   Install kubectl v%s.%d: https://kubernetes.io/docs/tasks/tools/#kubectl\n", server.Major, serverMinor)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,125 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// clusterSortModes are the accepted values of --sort
+var clusterSortModes = []string{"name", "version", "status", "recent"}
+
+// clusterSort returns the validated sort mode of the cluster list, or "" for API order
+func (app *EKSLoginApp) clusterSort() (string, error) {
+	mode := app.config.Sort
+	if mode == "" {
+		mode = app.settings.Default.Sort
+	}
+	if mode == "" {
+		return "", nil
+	}
+	for _, valid := range clusterSortModes {
+		if mode == valid {
+			return mode, nil
+		}
+	}
+	return "", usageError("invalid sort %q: use one of %v", mode, clusterSortModes)
+}
+
+// describeTargets fills in the version and status of each target
+func (app *EKSLoginApp) describeTargets(targets []ClusterTarget) {
+	app.forEach(len(targets), func(i int) {
+		target := &targets[i]
+		details, err := app.forTarget(app.config.Profile, target.Region, target.Cluster).DescribeCluster()
+		if err != nil {
+			target.Status = "UNKNOWN"
+			return
+		}
+		target.Version = details.Version
+		target.Status = details.Status
+	})
+}
+
+// statusRank orders cluster statuses with usable clusters first
+func statusRank(status string) int {
+	switch status {
+	case "ACTIVE":
+		return 0
+	case "UPDATING":
+		return 1
+	case "CREATING", "PENDING":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SortTargets orders the cluster list by mode: name, version (newest first),
+// status (active first) or recent (most recently used first)
+func (app *EKSLoginApp) SortTargets(targets []ClusterTarget, mode string) {
+	byName := func(a, b ClusterTarget) bool {
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Region < b.Region
+	}
+
+	var less func(a, b ClusterTarget) bool
+	switch mode {
+	case "name":
+		less = byName
+	case "version":
+		app.describeTargets(targets)
+		less = func(a, b ClusterTarget) bool {
+			if cmp := compareVersions(a.Version, b.Version); cmp != 0 {
+				return cmp > 0
+			}
+			return byName(a, b)
+		}
+	case "status":
+		app.describeTargets(targets)
+		less = func(a, b ClusterTarget) bool {
+			if ra, rb := statusRank(a.Status), statusRank(b.Status); ra != rb {
+				return ra < rb
+			}
+			return byName(a, b)
+		}
+	case "recent":
+		used := app.LoadState().Used
+		lastUsed := func(t ClusterTarget) time.Time {
+			return used[app.config.Profile+"/"+t.Region+"/"+t.Cluster]
+		}
+		less = func(a, b ClusterTarget) bool {
+			if ta, tb := lastUsed(a), lastUsed(b); !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+			return byName(a, b)
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return less(targets[i], targets[j])
+	})
+}
+
+// describe returns the picker label of a target
+func (t ClusterTarget) describe(showRegion bool) string {
+	label := t.Cluster
+	var details []string
+	if showRegion {
+		details = append(details, t.Region)
+	}
+	if t.Version != "" {
+		details = append(details, "v"+t.Version)
+	}
+	if t.Status != "" {
+		details = append(details, t.Status)
+	}
+	if len(details) > 0 {
+		label += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+	return label
+}
@@ -0,0 +1,84 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// spinnerFrames are drawn in turn while a step runs
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is the time between two frames
+const spinnerInterval = 100 * time.Millisecond
+
+// Spinner animates a line with the elapsed time while a long step runs, so
+// slow AWS calls do not look like a hang. The line is cleared when it stops.
+type Spinner struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// spinnersEnabled reports whether spinners may be drawn: only on a terminal and
+// outside CI mode, so logs and piped output stay clean
+func (app *EKSLoginApp) spinnersEnabled() bool {
+	if app.config.CI || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fd := os.Stderr.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// StartSpinner draws a spinner for step on stderr until Stop is called. Where
+// spinners are disabled it returns one that draws nothing; in accessible mode
+// the step is announced on a line of its own instead.
+func (app *EKSLoginApp) StartSpinner(step string) *Spinner {
+	spinner := &Spinner{}
+	if app.config.Accessible && !app.config.CI {
+		fmt.Fprintf(os.Stderr, "%s...\n", step)
+		return spinner
+	}
+	if !app.spinnersEnabled() {
+		return spinner
+	}
+
+	spinner.stop = make(chan struct{})
+	spinner.done = make(chan struct{})
+	go spinner.spin(step, time.Now())
+	return spinner
+}
+
+// Active reports whether the spinner is being drawn
+func (s *Spinner) Active() bool {
+	return s.stop != nil
+}
+
+// spin redraws the spinner line until stopped
+func (s *Spinner) spin(step string, start time.Time) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		elapsed := time.Since(start).Truncate(time.Second)
+		fmt.Fprintf(os.Stderr, "\r\033[K  %s %s (%s)", spinnerFrames[frame%len(spinnerFrames)], step, elapsed)
+		select {
+		case <-s.stop:
+			fmt.Fprint(os.Stderr, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop clears the spinner line
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
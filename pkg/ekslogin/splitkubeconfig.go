@@ -0,0 +1,140 @@
+package ekslogin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSplitName names per-cluster kubeconfig files unless kubeconfig.name is set
+const defaultSplitName = "{cluster}"
+
+// KubeconfigLayoutConfig selects where eks-login writes kubeconfig entries
+type KubeconfigLayoutConfig struct {
+	// Split writes each cluster to its own file in Dir instead of merging
+	// it into the one kubeconfig, like --split-kubeconfig
+	Split bool `yaml:"split,omitempty"`
+	// Dir holds the per-cluster files (default ~/.kube/configs)
+	Dir string `yaml:"dir,omitempty"`
+	// Name is the file name, with {cluster}, {profile} and {region} expanded (default "{cluster}")
+	Name string `yaml:"name,omitempty"`
+}
+
+// splitKubeconfigDir returns the directory of per-cluster kubeconfig files
+func (app *EKSLoginApp) splitKubeconfigDir() (string, error) {
+	dir := app.settings.Kubeconfig.Dir
+	if dir != "" && dir != "~" && !strings.HasPrefix(dir, "~/") {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	if dir == "" {
+		return filepath.Join(home, ".kube", "configs"), nil
+	}
+	return filepath.Join(home, strings.TrimPrefix(dir, "~")), nil
+}
+
+// splitKubeconfigPath returns the file the selected cluster is written to
+// in the split layout, or "" when entries are merged into one kubeconfig
+func (app *EKSLoginApp) splitKubeconfigPath() (string, error) {
+	if !app.config.SplitKubeconfig && !app.settings.Kubeconfig.Split {
+		return "", nil
+	}
+
+	dir, err := app.splitKubeconfigDir()
+	if err != nil {
+		return "", err
+	}
+	format := app.settings.Kubeconfig.Name
+	if format == "" {
+		format = defaultSplitName
+	}
+	name := strings.NewReplacer(
+		"{cluster}", app.config.Cluster,
+		"{profile}", app.config.Profile,
+		"{region}", app.config.Region,
+		"/", "_",
+	).Replace(format)
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// useSplitKubeconfig points this process and the commands it runs at the
+// selected cluster's own kubeconfig file in the split layout
+func (app *EKSLoginApp) useSplitKubeconfig() error {
+	path, err := app.splitKubeconfigPath()
+	if err != nil || path == "" {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+	return os.Setenv("KUBECONFIG", path)
+}
+
+// SplitKubeconfigFiles lists the per-cluster kubeconfig files, sorted by name
+func (app *EKSLoginApp) SplitKubeconfigFiles() ([]string, error) {
+	dir, err := app.splitKubeconfigDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read kubeconfig directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func newKubeconfigPathCmd(app *EKSLoginApp) *cobra.Command {
+	var withDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "kubeconfig-path",
+		Short: "Print a KUBECONFIG value joining the per-cluster kubeconfig files",
+		Long: `Print the per-cluster kubeconfig files written with --split-kubeconfig (or
+kubeconfig.split in the config file) joined into one KUBECONFIG value, so kubectl
+sees every cluster while each stays in its own file.`,
+		Example: `  export KUBECONFIG=$(eks-login kubeconfig-path)
+  export KUBECONFIG=$(eks-login kubeconfig-path --with-default)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := app.SplitKubeconfigFiles()
+			if err != nil {
+				return err
+			}
+			if withDefault {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("failed to locate home directory: %w", err)
+				}
+				files = append([]string{filepath.Join(home, ".kube", "config")}, files...)
+			}
+			if len(files) == 0 {
+				return usageError("no kubeconfig files found; log in with --split-kubeconfig first")
+			}
+			fmt.Println(strings.Join(files, string(os.PathListSeparator)))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&withDefault, "with-default", false, "Put ~/.kube/config first, so its current context stays the default")
+	return cmd
+}
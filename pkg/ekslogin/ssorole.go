@@ -0,0 +1,144 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SSORole is a permission set the SSO user may assume in an account
+type SSORole struct {
+	RoleName  string `json:"roleName"`
+	AccountID string `json:"accountId"`
+}
+
+// ListSSORoles lists the roles the SSO session of the selected profile can
+// assume in the profile's account
+func (app *EKSLoginApp) ListSSORoles(files *awsConfigFiles, settings map[string]string) ([]SSORole, error) {
+	entry, region, err := ssoSession(files, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	spinner := app.StartSpinner("Listing SSO roles")
+	output, err := app.AWS("sso", "list-account-roles",
+		"--account-id", settings["sso_account_id"],
+		"--access-token", entry.AccessToken,
+		"--region", region,
+		"--output", "json")
+	spinner.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSO roles: %w", err)
+	}
+
+	var response struct {
+		RoleList []SSORole `json:"roleList"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse SSO roles: %w", err)
+	}
+	sort.Slice(response.RoleList, func(i, j int) bool {
+		return response.RoleList[i].RoleName < response.RoleList[j].RoleName
+	})
+	return response.RoleList, nil
+}
+
+// SelectSSORole lets the user log in with another permission set of the
+// profile's account (--sso-role, or picked with --select-role). The role is
+// saved as a profile named <profile>-<role> in the AWS config file, which the
+// login then uses, so kubectl keeps assuming it.
+func (app *EKSLoginApp) SelectSSORole() error {
+	if !app.config.SelectRole && app.config.SSORole == "" {
+		return nil
+	}
+
+	files, err := loadAWSConfigFiles()
+	if err != nil {
+		return err
+	}
+	settings, ok := files.profile(app.config.Profile)
+	if !ok || (settings["sso_session"] == "" && settings["sso_start_url"] == "") {
+		return usageError("choosing an SSO role requires an SSO profile, but %s is not one", app.config.Profile)
+	}
+
+	roles, err := app.ListSSORoles(files, settings)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("no SSO roles are assigned to you in account %s", settings["sso_account_id"])
+	}
+
+	var role string
+	switch {
+	case app.config.SSORole != "":
+		for _, r := range roles {
+			if r.RoleName == app.config.SSORole {
+				role = r.RoleName
+			}
+		}
+		if role == "" {
+			names := make([]string, len(roles))
+			for i, r := range roles {
+				names[i] = r.RoleName
+			}
+			return usageError("SSO role %s is not assigned to you in account %s (available: %v)", app.config.SSORole, settings["sso_account_id"], names)
+		}
+	case len(roles) == 1:
+		role = roles[0].RoleName
+	default:
+		items := make([]string, len(roles))
+		current := -1
+		for i, r := range roles {
+			items[i] = r.RoleName
+			if r.RoleName == settings["sso_role_name"] {
+				items[i] += " (profile default)"
+				current = i
+			}
+		}
+		blue.Printf("\n🎭 Roles in account %s:\n", settings["sso_account_id"])
+		choice, err := app.Select("role", items, current)
+		if err != nil {
+			return err
+		}
+		role = roles[choice].RoleName
+	}
+
+	if role == settings["sso_role_name"] {
+		return nil
+	}
+	return app.useSSORoleProfile(files, role)
+}
+
+// useSSORoleProfile writes the selected profile with another SSO role as
+// <profile>-<role> and switches the login to it
+func (app *EKSLoginApp) useSSORoleProfile(files *awsConfigFiles, role string) error {
+	name := app.config.Profile + "-" + role
+	source := files.config["profile "+app.config.Profile]
+	if app.config.Profile == "default" && source == nil {
+		source = files.config["default"]
+	}
+
+	values := map[string]string{}
+	for key, value := range source {
+		values[key] = value
+	}
+	values["sso_role_name"] = role
+
+	if existing, ok := files.profile(name); ok &&
+		(existing["sso_account_id"] != values["sso_account_id"] || existing["sso_role_name"] != role) {
+		return usageError("profile %s already exists with other settings; remove it or pick the role in it directly", name)
+	}
+
+	path := awsFile("AWS_CONFIG_FILE", "config")
+	if app.config.DryRun {
+		yellow.Printf("🧪 Would save role %s as profile %s in %s and use it; showing the login with %s\n", role, name, path, app.config.Profile)
+		return nil
+	}
+	if err := setINISection(path, "profile "+name, values); err != nil {
+		return err
+	}
+	cyan.Printf("🎭 Using role %s through profile %s (saved to %s)\n", role, name, path)
+	app.config.Profile = name
+	return nil
+}
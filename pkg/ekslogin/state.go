@@ -0,0 +1,129 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is what eks-login remembers between runs
+type State struct {
+	// Version is the format of the file; see stateVersion
+	Version int `json:"version,omitempty"`
+	// LastClusters maps "profile/region" to the cluster last logged in to
+	LastClusters map[string]string `json:"last_clusters,omitempty"`
+	// Used maps "profile/region/cluster" to when the cluster was last logged in to
+	Used map[string]time.Time `json:"used,omitempty"`
+	// SSOTokens maps profiles to their SSO token cache file, so the prompt can
+	// show the session expiry without calling the AWS CLI
+	SSOTokens map[string]string `json:"sso_tokens,omitempty"`
+	// AWSCLI remembers the AWS CLI version, so it is not asked for on every run
+	AWSCLI *AWSCLIInfo `json:"aws_cli,omitempty"`
+}
+
+// stateVersion is the state file format this build writes. The state is a
+// cache: older files are read as they are and rewritten in this format, while
+// files of a newer eks-login are read but never overwritten.
+const stateVersion = 1
+
+// statePath returns the location of the state file
+func statePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "eks-login", "state.json"), nil
+}
+
+// readState loads the state file; a missing or unreadable file yields empty state
+func readState(path string) *State {
+	state := &State{}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		json.Unmarshal(data, state)
+	}
+	if state.LastClusters == nil {
+		state.LastClusters = make(map[string]string)
+	}
+	if state.Used == nil {
+		state.Used = make(map[string]time.Time)
+	}
+	if state.SSOTokens == nil {
+		state.SSOTokens = make(map[string]string)
+	}
+	return state
+}
+
+// LoadState returns the remembered state
+func (app *EKSLoginApp) LoadState() *State {
+	path, err := statePath()
+	if err != nil {
+		return readState("")
+	}
+	return readState(path)
+}
+
+// UpdateState applies update to the state file under its lock
+func (app *EKSLoginApp) UpdateState(update func(*State)) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	state := readState(path)
+	if state.Version > stateVersion {
+		return fmt.Errorf("state file %s was written by a newer eks-login (version %d); upgrade eks-login or run 'eks-login cache clear'", path, state.Version)
+	}
+	update(state)
+	state.Version = stateVersion
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(fmt.Errorf("failed to write state: %w", err), os.Remove(tmp))
+	}
+	return nil
+}
+
+// stateKey identifies the current profile and region in the state file
+func (app *EKSLoginApp) stateKey() string {
+	return app.config.Profile + "/" + app.config.Region
+}
+
+// LastCluster returns the cluster last used with the current profile and region
+func (app *EKSLoginApp) LastCluster() string {
+	return app.LoadState().LastClusters[app.stateKey()]
+}
+
+// RememberCluster records the current cluster as the last one used with the
+// profile, along with the profile's SSO token file
+func (app *EKSLoginApp) RememberCluster() {
+	tokenPath, _ := app.SSOTokenPath()
+	err := app.UpdateState(func(state *State) {
+		state.LastClusters[app.stateKey()] = app.config.Cluster
+		state.Used[app.stateKey()+"/"+app.config.Cluster] = time.Now()
+		if tokenPath != "" {
+			state.SSOTokens[app.config.Profile] = tokenPath
+		}
+	})
+	if err != nil {
+		yellow.Printf("⚠️  Unable to remember cluster selection: %v\n", err)
+	}
+}
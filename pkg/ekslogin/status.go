@@ -0,0 +1,123 @@
+package ekslogin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// LoginStatus describes the current kubeconfig context and its session
+type LoginStatus struct {
+	Context        string     `json:"context"`
+	Managed        bool       `json:"managed"`
+	Profile        string     `json:"profile,omitempty"`
+	Region         string     `json:"region,omitempty"`
+	Cluster        string     `json:"cluster,omitempty"`
+	Namespace      string     `json:"namespace,omitempty"`
+	RoleARN        string     `json:"roleArn,omitempty"`
+	RoleDuration   string     `json:"roleDuration,omitempty"`
+	SSOExpiresAt   *time.Time `json:"ssoExpiresAt,omitempty"`
+	TokenExpiresAt *time.Time `json:"tokenExpiresAt,omitempty"`
+}
+
+// currentContext describes the current kubeconfig context from kubeconfig alone.
+// The metadata is nil when eks-login did not write the context.
+func currentContext() (*LoginStatus, *LoginMetadata, error) {
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status := &LoginStatus{Context: kubeconfig.CurrentContext}
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return status, nil, nil
+	}
+	status.Namespace = context.Namespace
+
+	metadata := context.Metadata()
+	if metadata == nil {
+		return status, nil, nil
+	}
+	status.Managed = true
+	status.Profile = metadata.Profile
+	status.Region = metadata.Region
+	status.Cluster = metadata.Cluster
+	status.RoleARN = metadata.RoleARN
+	status.RoleDuration = metadata.RoleDuration
+	return status, metadata, nil
+}
+
+// Status inspects the current kubeconfig context and, if eks-login wrote it,
+// when its SSO session and EKS token expire
+func (app *EKSLoginApp) Status() (*LoginStatus, error) {
+	status, metadata, err := currentContext()
+	if err != nil || metadata == nil {
+		return status, err
+	}
+
+	target := app.forTarget(metadata.Profile, metadata.Region, metadata.Cluster)
+	target.config.RoleARN = metadata.RoleARN
+	target.config.RoleDuration, _ = time.ParseDuration(metadata.RoleDuration)
+	status.SSOExpiresAt, status.TokenExpiresAt = target.SessionExpiry()
+
+	return status, nil
+}
+
+// ShowStatus prints the status of the current context
+func (app *EKSLoginApp) ShowStatus(output string) error {
+	status, err := app.Status()
+	if err != nil {
+		return err
+	}
+
+	return printOutput(output, status, func() error {
+		printStatus(status)
+		return nil
+	})
+}
+
+// printStatus prints the status of the current context for people
+func printStatus(status *LoginStatus) {
+	if status.Context == "" {
+		yellow.Println("No current kubeconfig context")
+		return
+	}
+	fmt.Printf("Context: %s\n", status.Context)
+	if !status.Managed {
+		fmt.Println("Not created by eks-login")
+		return
+	}
+	fmt.Printf("Profile: %s\n", status.Profile)
+	fmt.Printf("Region: %s\n", status.Region)
+	fmt.Printf("Cluster: %s\n", status.Cluster)
+	if status.Namespace != "" {
+		fmt.Printf("Namespace: %s\n", status.Namespace)
+	}
+	if status.RoleARN != "" {
+		duration := status.RoleDuration
+		if duration == "" {
+			duration = "1h (default)"
+		}
+		fmt.Printf("Role: %s\n", status.RoleARN)
+		fmt.Printf("Role session duration: %s\n", duration)
+	}
+	printExpiry(status.SSOExpiresAt, status.TokenExpiresAt)
+}
+
+// newStatusCmd creates the status subcommand
+func newStatusCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current context and when its SSO session and EKS token expire",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.ShowStatus(output)
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	return cmd
+}
@@ -0,0 +1,69 @@
+package ekslogin
+
+import (
+	"fmt"
+	"slices"
+)
+
+// STSConfig selects the STS endpoint EKS tokens are signed for. EKS accepts
+// tokens of any regional STS endpoint of the cluster's partition.
+type STSConfig struct {
+	// Regional makes the AWS CLI use regional STS endpoints instead of the
+	// global sts.amazonaws.com, which AWS CLI v1 defaults to
+	Regional bool `yaml:"regional,omitempty"`
+	// Region signs tokens with the STS endpoint of this region instead of the
+	// cluster's, e.g. the one closest to you; implies regional
+	Region string `yaml:"region,omitempty"`
+}
+
+// regionalSTS reports whether STS calls must avoid the global endpoint
+func (app *EKSLoginApp) regionalSTS() bool {
+	return app.settings.STS.Regional || app.config.STSRegion != "" || app.settings.STS.Region != ""
+}
+
+// stsRegion returns the region whose STS endpoint signs the tokens of the
+// selected cluster: --sts-region, sts.region, or else the cluster's region
+func (app *EKSLoginApp) stsRegion() (string, error) {
+	region := app.config.STSRegion
+	if region == "" {
+		region = app.settings.STS.Region
+	}
+	if region == "" {
+		return app.config.Region, nil
+	}
+	if app.config.Region != "" && PartitionForRegion(region).ID != PartitionForRegion(app.config.Region).ID {
+		return "", usageError("STS region %s is not in the partition of region %s", region, app.config.Region)
+	}
+	return region, nil
+}
+
+// withRegionArg returns the arguments of an aws exec block with the value of
+// --region replaced; aws eks get-token only uses the region for STS
+func withRegionArg(args []string, region string) []string {
+	args = slices.Clone(args)
+	for i, arg := range args {
+		if arg == "--region" && i+1 < len(args) {
+			args[i+1] = region
+			return args
+		}
+	}
+	return append([]string{"--region", region}, args...)
+}
+
+// stsExecEnv returns the exec environment that keeps aws eks get-token off
+// the global STS endpoint
+func (app *EKSLoginApp) stsExecEnv() []ExecEnvVar {
+	if !app.regionalSTS() {
+		return nil
+	}
+	return []ExecEnvVar{{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"}}
+}
+
+// describeSTSRegion explains which STS endpoint signs tokens, for the summary
+func (app *EKSLoginApp) describeSTSRegion() string {
+	region, err := app.stsRegion()
+	if err != nil || region == app.config.Region {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", region, PartitionForRegion(region).Endpoint("sts", region))
+}
@@ -0,0 +1,174 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// knownTarget is a profile and region a cluster was used with before or is configured for
+type knownTarget struct {
+	Profile string
+	Region  string
+}
+
+// knownTargets returns where cluster is known from: past logins, most recent
+// first, then presets
+func (app *EKSLoginApp) knownTargets(cluster string) []knownTarget {
+	var targets []knownTarget
+	seen := make(map[knownTarget]bool)
+	add := func(target knownTarget) {
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+
+	state := app.LoadState()
+	keys := make([]string, 0, len(state.Used))
+	for key := range state.Used {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return state.Used[keys[i]].After(state.Used[keys[j]])
+	})
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 3)
+		if len(parts) == 3 && parts[2] == cluster {
+			add(knownTarget{Profile: parts[0], Region: parts[1]})
+		}
+	}
+
+	for _, name := range app.presetNames() {
+		preset := app.settings.Presets[name]
+		if preset.Cluster == cluster && preset.Profile != "" {
+			add(knownTarget{Profile: preset.Profile, Region: preset.Region})
+		}
+	}
+	return targets
+}
+
+// ApplyTargetArg sets the target from an alias, CLUSTER or PROFILE/CLUSTER argument.
+// A bare cluster name is resolved to the profile and region it is known from.
+func (app *EKSLoginApp) ApplyTargetArg(cmd *cobra.Command, arg string) error {
+	if app.ApplyAlias(cmd, arg) {
+		return nil
+	}
+
+	profile, cluster, qualified := strings.Cut(arg, "/")
+	if !qualified {
+		profile, cluster = "", arg
+	}
+	if cluster == "" || (qualified && profile == "") {
+		return usageError("invalid target %q: use CLUSTER or PROFILE/CLUSTER", arg)
+	}
+
+	if cmd.Flags().Changed("cluster") && app.config.Cluster != cluster {
+		return usageError("cluster given both as argument (%s) and --cluster (%s)", cluster, app.config.Cluster)
+	}
+	if qualified && cmd.Flags().Changed("profile") && app.config.Profile != profile {
+		return usageError("profile given both as argument (%s) and --profile (%s)", profile, app.config.Profile)
+	}
+	app.config.Cluster = cluster
+
+	profileSet := qualified || cmd.Flags().Changed("profile")
+	if qualified {
+		app.config.Profile = profile
+	}
+
+	var targets []knownTarget
+	for _, target := range app.knownTargets(cluster) {
+		if profileSet && target.Profile != app.config.Profile {
+			continue
+		}
+		if app.config.RegionSet && target.Region != "" && target.Region != app.config.Region {
+			continue
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	target := targets[0]
+	if len(targets) > 1 {
+		items := make([]string, len(targets))
+		for i, t := range targets {
+			items[i] = fmt.Sprintf("%s (%s)", t.Profile, t.Region)
+		}
+		blue.Printf("\n🎯 Cluster %s is known from several profiles:\n", cluster)
+		choice, err := app.Select("profile", items, 0)
+		if err != nil {
+			return err
+		}
+		target = targets[choice]
+	}
+
+	if !profileSet {
+		app.config.Profile = target.Profile
+	}
+	if !cmd.Flags().Changed("region") && target.Region != "" {
+		app.config.Region = target.Region
+		app.config.Regions = []string{target.Region}
+		app.config.RegionSet = true
+	}
+
+	cyan.Printf("🎯 Using cluster %s (profile: %s, region: %s)\n", cluster, app.config.Profile, app.config.Region)
+	return nil
+}
+
+// MatchCluster resolves a --cluster value that is not an exact cluster name: a
+// unique (case-insensitive) prefix selects its cluster, an ambiguous one offers
+// only the matching clusters. Without list permission the name is used as given.
+func (app *EKSLoginApp) MatchCluster() error {
+	regions := app.regions()
+	if len(regions) <= 1 {
+		regions = []string{app.config.Region}
+	}
+
+	targets, err := app.ListClusterTargets(regions)
+	if err != nil {
+		yellow.Printf("⚠️  Unable to list clusters to check %s: %v\n", app.config.Cluster, err)
+		return nil
+	}
+
+	name := app.config.Cluster
+	var matches []ClusterTarget
+	for _, target := range targets {
+		if target.Cluster == name {
+			app.useClusterTarget(target)
+			return nil
+		}
+		if strings.HasPrefix(strings.ToLower(target.Cluster), strings.ToLower(name)) {
+			matches = append(matches, target)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return withExitCode(ExitClusterNotFound, "cluster_not_found",
+			fmt.Errorf("cluster %s not found in region %s with profile %s", name, strings.Join(regions, ", "), app.config.Profile))
+	case 1:
+		app.useClusterTarget(matches[0])
+		cyan.Printf("🎯 Using cluster: %s (matched %q)\n", app.config.Cluster, name)
+		return nil
+	}
+
+	items := make([]string, len(matches))
+	for i, target := range matches {
+		items[i] = target.describe(len(regions) > 1)
+	}
+	if !app.config.Interactive {
+		return usageError("cluster %q is ambiguous, it matches: %s", name, strings.Join(items, ", "))
+	}
+
+	blue.Printf("\n🎯 Clusters matching %q:\n", name)
+	choice, err := app.Select("cluster", items, -1)
+	if err != nil {
+		return err
+	}
+	app.useClusterTarget(matches[choice])
+	return nil
+}
@@ -0,0 +1,150 @@
+package ekslogin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newTargetCommand returns the root command of app with flags set as given,
+// and the selection newTestApp starts from otherwise
+func newTargetCommand(t *testing.T, app *EKSLoginApp, flags map[string]string) *cobra.Command {
+	t.Helper()
+	cmd := NewRootCommand(app)
+	app.config.Profile, app.config.Region, app.config.RegionSet = "dev", "eu-west-1", false
+	var args []string
+	for name, value := range flags {
+		args = append(args, "--"+name+"="+value)
+	}
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	// As the root command's PersistentPreRun does
+	app.config.RegionSet = cmd.Flags().Changed("region")
+	if regions := app.regions(); len(regions) > 0 {
+		app.config.Region = regions[0]
+	}
+	return cmd
+}
+
+// rememberLogins records past logins to "profile/region/cluster" keys, the
+// first most recent
+func rememberLogins(t *testing.T, app *EKSLoginApp, keys ...string) {
+	t.Helper()
+	err := app.UpdateState(func(state *State) {
+		for i, key := range keys {
+			state.Used[key] = time.Now().Add(-time.Duration(i) * time.Hour)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyTargetArgAlias(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	cmd := newTargetCommand(t, app, map[string]string{"region": "us-east-1"})
+	app.settings.Aliases = map[string]Preset{
+		"pay": {Profile: "payments", Region: "eu-central-1", Cluster: "pay-prod", Namespace: "api"},
+	}
+
+	if err := app.ApplyTargetArg(cmd, "pay"); err != nil {
+		t.Fatalf("ApplyTargetArg(pay): %v", err)
+	}
+	// --region wins over the alias
+	if app.config.Profile != "payments" || app.config.Cluster != "pay-prod" || app.config.Region != "us-east-1" || app.config.Namespace != "api" {
+		t.Errorf("alias resolved to %s/%s/%s namespace %s", app.config.Profile, app.config.Region, app.config.Cluster, app.config.Namespace)
+	}
+}
+
+func TestApplyTargetArgQualified(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	cmd := newTargetCommand(t, app, nil)
+	rememberLogins(t, app, "ops/us-west-2/api", "staging/ap-south-1/api")
+
+	if err := app.ApplyTargetArg(cmd, "staging/api"); err != nil {
+		t.Fatalf("ApplyTargetArg(staging/api): %v", err)
+	}
+	if app.config.Profile != "staging" || app.config.Region != "ap-south-1" || app.config.Cluster != "api" {
+		t.Errorf("staging/api resolved to %s/%s/%s", app.config.Profile, app.config.Region, app.config.Cluster)
+	}
+}
+
+func TestApplyTargetArgBareCluster(t *testing.T) {
+	prompter := &fakePrompter{choice: 1}
+	app := newTestApp(t, &fakeExecutor{}, prompter)
+	cmd := newTargetCommand(t, app, nil)
+	rememberLogins(t, app, "ops/us-west-2/api", "staging/ap-south-1/api", "ops/us-west-2/web")
+
+	// Known from two profiles: the prompt lists the most recent first
+	if err := app.ApplyTargetArg(cmd, "api"); err != nil {
+		t.Fatalf("ApplyTargetArg(api): %v", err)
+	}
+	if len(prompter.labels) != 1 {
+		t.Errorf("prompted %d times, want once", len(prompter.labels))
+	}
+	if app.config.Profile != "staging" || app.config.Region != "ap-south-1" {
+		t.Errorf("second choice resolved to %s/%s, want staging/ap-south-1", app.config.Profile, app.config.Region)
+	}
+
+	// Known from one profile: used without asking
+	prompter.labels = nil
+	cmd = newTargetCommand(t, app, nil)
+	if err := app.ApplyTargetArg(cmd, "web"); err != nil {
+		t.Fatalf("ApplyTargetArg(web): %v", err)
+	}
+	if len(prompter.labels) != 0 || app.config.Profile != "ops" || app.config.Region != "us-west-2" {
+		t.Errorf("web resolved to %s/%s after %d prompts, want ops/us-west-2 without one", app.config.Profile, app.config.Region, len(prompter.labels))
+	}
+
+	// Unknown: the selection is kept
+	cmd = newTargetCommand(t, app, nil)
+	if err := app.ApplyTargetArg(cmd, "new"); err != nil {
+		t.Fatalf("ApplyTargetArg(new): %v", err)
+	}
+	if app.config.Profile != "dev" || app.config.Region != "eu-west-1" || app.config.Cluster != "new" {
+		t.Errorf("unknown cluster resolved to %s/%s/%s", app.config.Profile, app.config.Region, app.config.Cluster)
+	}
+}
+
+func TestApplyTargetArgFlagsNarrowKnownTargets(t *testing.T) {
+	prompter := &fakePrompter{}
+	app := newTestApp(t, &fakeExecutor{}, prompter)
+	rememberLogins(t, app, "ops/us-west-2/api", "staging/ap-south-1/api")
+
+	cmd := newTargetCommand(t, app, map[string]string{"profile": "staging"})
+	if err := app.ApplyTargetArg(cmd, "api"); err != nil {
+		t.Fatalf("ApplyTargetArg(api) --profile staging: %v", err)
+	}
+	if len(prompter.labels) != 0 || app.config.Region != "ap-south-1" {
+		t.Errorf("--profile staging resolved region %s after %d prompts", app.config.Region, len(prompter.labels))
+	}
+
+	cmd = newTargetCommand(t, app, map[string]string{"region": "us-west-2"})
+	if err := app.ApplyTargetArg(cmd, "api"); err != nil {
+		t.Fatalf("ApplyTargetArg(api) --region us-west-2: %v", err)
+	}
+	if len(prompter.labels) != 0 || app.config.Profile != "ops" {
+		t.Errorf("--region us-west-2 resolved profile %s after %d prompts", app.config.Profile, len(prompter.labels))
+	}
+}
+
+func TestApplyTargetArgRejectsConflicts(t *testing.T) {
+	tests := []struct {
+		arg   string
+		flags map[string]string
+	}{
+		{arg: "api", flags: map[string]string{"cluster": "web"}},
+		{arg: "ops/api", flags: map[string]string{"profile": "staging"}},
+		{arg: "ops/"},
+		{arg: "/api"},
+	}
+	for _, test := range tests {
+		app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+		cmd := newTargetCommand(t, app, test.flags)
+		if code, _ := exitCode(app.ApplyTargetArg(cmd, test.arg)); code != ExitUsage {
+			t.Errorf("ApplyTargetArg(%q) with %v exited %d, want a usage error", test.arg, test.flags, code)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ekslogin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ThemeConfig assigns the colors of eks-login's output. Preset picks a base
+// theme; the per-role colors override it.
+type ThemeConfig struct {
+	Preset string `yaml:"preset,omitempty"`
+	// Success marks completed steps, Warning cautions, Error failures, Info
+	// progress messages and Highlight the selected profile and cluster
+	Success   string `yaml:"success,omitempty"`
+	Warning   string `yaml:"warning,omitempty"`
+	Error     string `yaml:"error,omitempty"`
+	Info      string `yaml:"info,omitempty"`
+	Highlight string `yaml:"highlight,omitempty"`
+}
+
+// themePresets are the built-in themes. "colorblind" avoids telling success
+// and failure apart by red and green (blue and orange instead), "monochrome"
+// keeps only bold text and relies on the symbols.
+var themePresets = map[string]ThemeConfig{
+	"default":    {Success: "green", Warning: "yellow", Error: "red", Info: "blue", Highlight: "cyan"},
+	"colorblind": {Success: "blue", Warning: "yellow", Error: "208", Info: "cyan", Highlight: "magenta"},
+	"monochrome": {Success: "none", Warning: "none", Error: "none", Info: "none", Highlight: "none"},
+}
+
+// themeColorNames maps color names to their foreground attributes
+var themeColorNames = map[string]color.Attribute{
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+}
+
+// themePresetNames lists the presets for help and error messages
+func themePresetNames() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseThemeColor turns a color name, a 256-color palette number (0-255) or
+// "none" into a bold color
+func parseThemeColor(value string) (*color.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "none" {
+		return color.New(color.Bold), nil
+	}
+	if attribute, ok := themeColorNames[value]; ok {
+		return color.New(attribute, color.Bold), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		// 38;5;n selects n from the 256-color palette
+		return color.New(38, 5, color.Attribute(n), color.Bold), nil
+	}
+	return nil, fmt.Errorf("unknown color %q (use a name such as green or hi-blue, a number 0-255, or none)", value)
+}
+
+// applyTheme sets the output colors from theme, on top of its preset
+func applyTheme(theme ThemeConfig) error {
+	name := theme.Preset
+	if name == "" {
+		name = "default"
+	}
+	preset, ok := themePresets[name]
+	if !ok {
+		return fmt.Errorf("unknown theme preset %q (use one of %s)", name, strings.Join(themePresetNames(), ", "))
+	}
+
+	roles := []struct {
+		target   **color.Color
+		override string
+		base     string
+	}{
+		{&green, theme.Success, preset.Success},
+		{&yellow, theme.Warning, preset.Warning},
+		{&red, theme.Error, preset.Error},
+		{&blue, theme.Info, preset.Info},
+		{&cyan, theme.Highlight, preset.Highlight},
+	}
+	for _, role := range roles {
+		value := role.override
+		if value == "" {
+			value = role.base
+		}
+		c, err := parseThemeColor(value)
+		if err != nil {
+			return err
+		}
+		*role.target = c
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package ekslogin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultTimeout      = 2 * time.Minute
+	defaultLoginTimeout = 10 * time.Minute
+)
+
+// context returns the base context of the current run
+func (app *EKSLoginApp) context() context.Context {
+	if app.ctx == nil {
+		return context.Background()
+	}
+	return app.ctx
+}
+
+// timeout returns the limit for a single external operation
+func (app *EKSLoginApp) timeout() time.Duration {
+	switch {
+	case app.config.Timeout > 0:
+		return app.config.Timeout
+	case app.settings.Timeout > 0:
+		return app.settings.Timeout
+	default:
+		return defaultTimeout
+	}
+}
+
+// loginTimeout returns the limit for the interactive SSO login
+func (app *EKSLoginApp) loginTimeout() time.Duration {
+	if app.config.LoginTimeout > 0 {
+		return app.config.LoginTimeout
+	}
+	return defaultLoginTimeout
+}
+
+// withTimeout derives a context from the run's context that expires after d
+func (app *EKSLoginApp) withTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(app.context(), d)
+}
+
+// timeoutError replaces err with a readable message when ctx expired
+func timeoutError(ctx context.Context, operation string, d time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s", operation, d)
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package ekslogin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TmuxConfig configures the output of 'eks-login tmux-status'
+type TmuxConfig struct {
+	// Format uses the placeholders of 'eks-login prompt --format'
+	Format string `yaml:"format,omitempty"`
+	// Color, WarnColor and ExpiredColor are tmux colors (e.g. "green", "colour214", "#ff8700")
+	// used while the session is valid, about to expire, and expired
+	Color        string `yaml:"color,omitempty"`
+	WarnColor    string `yaml:"warn_color,omitempty"`
+	ExpiredColor string `yaml:"expired_color,omitempty"`
+	// WarnBefore is how long before expiry WarnColor is used (default 1h)
+	WarnBefore time.Duration `yaml:"warn_before,omitempty"`
+}
+
+// orDefault returns value, or fallback when value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// tmuxColor picks the color of the status segment from the session's remaining time
+func (c TmuxConfig) tmuxColor(status *LoginStatus) string {
+	if status.SSOExpiresAt == nil {
+		return orDefault(c.Color, "green")
+	}
+
+	warnBefore := c.WarnBefore
+	if warnBefore <= 0 {
+		warnBefore = time.Hour
+	}
+	switch left := time.Until(*status.SSOExpiresAt); {
+	case left <= 0:
+		return orDefault(c.ExpiredColor, "red")
+	case left < warnBefore:
+		return orDefault(c.WarnColor, "yellow")
+	default:
+		return orDefault(c.Color, "green")
+	}
+}
+
+// TmuxStatus renders the current context as a styled tmux status segment
+func (app *EKSLoginApp) TmuxStatus(format string) (string, error) {
+	status, err := app.CachedStatus()
+	if err != nil || status.Context == "" {
+		return "", err
+	}
+
+	config := app.settings.Tmux
+	if format == "" {
+		format = config.Format
+	}
+	if format == "" {
+		format = defaultSegmentFormat(status)
+	}
+	return fmt.Sprintf("#[fg=%s]%s#[default]", config.tmuxColor(status), status.FormatSegment(format)), nil
+}
+
+// newTmuxStatusCmd creates the tmux-status subcommand
+func newTmuxStatusCmd(app *EKSLoginApp) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "tmux-status",
+		Short: "Print a colored context/session segment for the tmux status line",
+		Long: `Print the current cluster and remaining SSO session time styled for tmux,
+turning yellow shortly before the session expires and red once it has.
+Only kubeconfig and cached files are read, so it can be polled every few seconds.
+
+The format and colors are configured under "tmux" in the config file.`,
+		Example: `  set -g status-right '#(eks-login tmux-status) %H:%M'
+  set -g status-interval 5`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			segment, err := app.TmuxStatus(format)
+			if err != nil || segment == "" {
+				return nil
+			}
+			fmt.Println(segment)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Segment format, like 'eks-login prompt --format' (overrides tmux.format)")
+	return cmd
+}
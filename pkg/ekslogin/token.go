@@ -0,0 +1,272 @@
+package ekslogin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// tokenPrefix marks a presigned STS URL as an EKS bearer token
+	tokenPrefix = "k8s-aws-v1."
+	// clusterIDHeader binds a token to the cluster it was issued for
+	clusterIDHeader = "x-k8s-aws-id"
+	// tokenPresignExpiry is how long STS accepts the presigned URL
+	tokenPresignExpiry = 60 * time.Second
+	// tokenLifetime is the expiry reported to kubectl; EKS accepts tokens for 15 minutes
+	tokenLifetime = 14 * time.Minute
+	// execCredentialAPIVersion is the client authentication API of the tokens we emit
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+)
+
+// execCredentialAPIVersions are the client authentication APIs kubectl may request
+var execCredentialAPIVersions = []string{
+	"client.authentication.k8s.io/v1alpha1",
+	"client.authentication.k8s.io/v1beta1",
+	"client.authentication.k8s.io/v1",
+}
+
+// TokenOptions tune the tokens of 'eks-login token'
+type TokenOptions struct {
+	// APIVersion of the ExecCredential; empty follows KUBERNETES_EXEC_INFO
+	APIVersion string
+	// SessionName of the assumed role, when a role ARN is given
+	SessionName string
+}
+
+// requestedAPIVersion returns the ExecCredential version kubectl asked for in
+// KUBERNETES_EXEC_INFO (set for exec plugins since Kubernetes 1.22), or v1beta1
+func requestedAPIVersion() string {
+	var info struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(os.Getenv("KUBERNETES_EXEC_INFO")), &info); err == nil && info.APIVersion != "" {
+		return info.APIVersion
+	}
+	return execCredentialAPIVersion
+}
+
+// validAPIVersion accepts full or short ("v1") client authentication versions
+func validAPIVersion(version string) (string, error) {
+	for _, valid := range execCredentialAPIVersions {
+		if version == valid || "client.authentication.k8s.io/"+version == valid {
+			return valid, nil
+		}
+	}
+	return "", usageError("unsupported ExecCredential version %q: use one of %v", version, execCredentialAPIVersions)
+}
+
+// tokenExecModes are the accepted values of --token-exec
+var tokenExecModes = []string{"aws", "eks-login"}
+
+// tokenExec returns the validated command kubeconfig exec blocks call for tokens
+func (app *EKSLoginApp) tokenExec() (string, error) {
+	mode := app.config.TokenExec
+	if mode == "" {
+		mode = app.settings.Default.TokenExec
+	}
+	if mode == "" && app.awsV1() {
+		// aws eks get-token of AWS CLI v1 cannot use SSO profiles
+		return "eks-login", nil
+	}
+	// aws eks get-token cannot set the session duration of the role it assumes
+	durationRole := app.config.RoleARN != "" && app.roleDuration() != 0
+	if mode == "" && durationRole {
+		return "eks-login", nil
+	}
+	if mode == "aws" && durationRole {
+		return "", usageError("--duration needs --token-exec eks-login: aws eks get-token cannot set the role session duration")
+	}
+	if mode == "" {
+		return "aws", nil
+	}
+	for _, valid := range tokenExecModes {
+		if mode == valid {
+			return mode, nil
+		}
+	}
+	return "", usageError("invalid token exec %q: use one of %v", mode, tokenExecModes)
+}
+
+// GenerateToken builds a bearer token for the selected cluster in-process by
+// presigning an STS GetCallerIdentity request, as aws eks get-token and
+// aws-iam-authenticator token do
+func (app *EKSLoginApp) GenerateToken(opts TokenOptions) (*ExecCredential, error) {
+	if app.config.Cluster == "" {
+		return nil, usageError("a cluster is required to generate a token")
+	}
+	app.config.Region = app.resolveRegion()
+
+	if opts.APIVersion == "" {
+		opts.APIVersion = requestedAPIVersion()
+	}
+	apiVersion, err := validAPIVersion(opts.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// EKS accepts tokens signed for any STS region of its partition
+	signingRegion, err := app.stsRegion()
+	if err != nil {
+		return nil, err
+	}
+
+	var creds *AWSCredentials
+	if app.config.RoleARN != "" {
+		// The role's session is cached, so kubectl does not assume it for every token
+		input := stsAssumeRoleInput{
+			RoleARN:     app.config.RoleARN,
+			SessionName: opts.SessionName,
+			Duration:    app.roleDurationSeconds(),
+		}
+		creds, err = app.cachedCredentials(assumeRoleCacheKey(input), "", func() (*AWSCredentials, error) {
+			source, err := app.ResolveCredentials()
+			if err != nil {
+				return nil, err
+			}
+			creds, err := app.stsAssumeRole(source, signingRegion, input)
+			if err != nil {
+				return nil, app.roleDurationError(err)
+			}
+			return creds, nil
+		})
+	} else {
+		creds, err = app.ResolveCredentials()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := app.stsEndpoint(signingRegion)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.RawQuery = "Action=GetCallerIdentity&Version=2011-06-15"
+	if endpoint.Path == "" {
+		endpoint.Path = "/"
+	}
+
+	now := time.Now()
+	signer := sigv4Signer{credentials: creds, region: signingRegion, service: "sts", now: now}
+	presigned := signer.Presign(*endpoint, http.Header{clusterIDHeader: {app.config.Cluster}}, tokenPresignExpiry)
+
+	credential := &ExecCredential{Kind: "ExecCredential", APIVersion: apiVersion}
+	credential.Status.Token = tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned))
+	credential.Status.ExpirationTimestamp = now.Add(tokenLifetime).UTC().Format(time.RFC3339)
+	return credential, nil
+}
+
+// tokenCommandPath returns how kubeconfig exec blocks should invoke eks-login:
+// by the name it was run as (eks-login or kubectl-eks_login) when that is on
+// PATH, otherwise by the absolute path of this binary
+func tokenCommandPath() string {
+	name := binaryName()
+	executable, err := os.Executable()
+	if err != nil {
+		return name
+	}
+	if found, err := exec.LookPath(name); err == nil {
+		if resolved, err := filepath.EvalSymlinks(found); err == nil {
+			if self, err := filepath.EvalSymlinks(executable); err == nil && resolved == self {
+				return name
+			}
+		}
+	}
+	return executable
+}
+
+// tokenExecConfig returns the exec block that makes kubectl call 'eks-login token'
+func (app *EKSLoginApp) tokenExecConfig(existing *ExecConfig) *ExecConfig {
+	args := []string{"token", "--cluster", app.config.Cluster, "--region", app.config.Region, "--no-project-config"}
+	if app.config.Profile != "" {
+		args = append(args, "--profile", app.config.Profile)
+	}
+	if app.config.RoleARN != "" {
+		args = append(args, "--role-arn", app.config.RoleARN)
+	}
+	if app.config.STSRegion != "" {
+		args = append(args, "--sts-region", app.config.STSRegion)
+	}
+	if app.config.RoleARN != "" && app.roleDuration() != 0 {
+		args = append(args, "--duration", formatRoleDuration(app.roleDuration()))
+	}
+
+	config := &ExecConfig{APIVersion: execCredentialAPIVersion, Command: tokenCommandPath(), Args: args}
+	if existing != nil {
+		config.Env = existing.Env
+		config.Extra = existing.Extra
+	}
+	config.Env = mergeExecEnv(withoutExecEnv(config.Env, app.replacedExecEnv()...), app.execEnv())
+	return config
+}
+
+// UseTokenExec points the user of the current context at 'eks-login token'
+// instead of 'aws eks get-token'. The caller saves the kubeconfig.
+func (app *EKSLoginApp) UseTokenExec(kubeconfig *Kubeconfig) error {
+	path := KubeconfigPath()
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, path)
+	}
+	user := kubeconfig.User(context.User)
+	if user == nil {
+		return fmt.Errorf("user %q not found in %s", context.User, path)
+	}
+
+	user.Exec = app.tokenExecConfig(user.Exec)
+	return nil
+}
+
+func newTokenCmd(app *EKSLoginApp) *cobra.Command {
+	var opts TokenOptions
+	var tokenOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print an EKS bearer token as an ExecCredential, without the AWS CLI",
+		Long: `Generate a bearer token for a cluster in-process by presigning an STS request,
+and print it as a client.authentication.k8s.io ExecCredential for kubectl.
+
+Credentials come from the profile (static keys, credential_process, a cached
+SSO session or role_arn chains); other profiles fall back to the AWS CLI.
+Role and SSO role sessions are shared with the AWS CLI's credential cache
+(~/.aws/cli/cache), so a session is not assumed again for every token.
+Kubeconfigs written with --token-exec eks-login call this command.
+
+The command also accepts the flags of 'aws-iam-authenticator token' (-i, --role,
+-s, --token-only), so kubeconfigs and tools wired to that binary can call it
+instead. The ExecCredential version follows KUBERNETES_EXEC_INFO unless
+--api-version is given.`,
+		Example: `  eks-login token --cluster my-cluster --region us-east-1 --profile dev
+  eks-login token -i my-cluster --role arn:aws:iam::123456789012:role/admin --token-only`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statusToStderr()
+			credential, err := app.GenerateToken(opts)
+			if err != nil {
+				return err
+			}
+			if tokenOnly {
+				fmt.Println(credential.Status.Token)
+				return nil
+			}
+			return printJSON(credential)
+		},
+	}
+	cmd.Flags().StringVar(&app.config.RoleARN, "role-arn", "", "Role to assume for the token")
+	cmd.Flags().StringVar(&app.config.RoleARN, "role", "", "Alias of --role-arn (aws-iam-authenticator)")
+	cmd.Flags().StringVarP(&app.config.Cluster, "cluster-id", "i", "", "Alias of --cluster (aws-iam-authenticator)")
+	cmd.Flags().StringVarP(&opts.SessionName, "session-name", "s", "", "Session name of the assumed role")
+	cmd.Flags().StringVar(&opts.APIVersion, "api-version", "", "ExecCredential version: v1alpha1, v1beta1 or v1 (default from KUBERNETES_EXEC_INFO, else v1beta1)")
+	cmd.Flags().BoolVar(&tokenOnly, "token-only", false, "Print only the bearer token")
+	cmd.Flags().DurationVar(&app.config.RoleDuration, "duration", 0, "Session duration of the role assumed for the token (15m to 12h, up to the role's maximum)")
+	cmd.Flags().StringVar(&app.config.STSRegion, "sts-region", "", "Sign the token with the STS endpoint of this region instead of the cluster's")
+	return cmd
+}
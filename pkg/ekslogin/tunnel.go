@@ -0,0 +1,402 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultTunnelPort is the local port the API endpoint is forwarded to
+	defaultTunnelPort = 8443
+	// tunnelSuffix is appended to the cluster name for the tunnel's kubeconfig entries
+	tunnelSuffix = "-tunnel"
+	// tunnelRestartDelay is the pause before a dropped tunnel is reopened
+	tunnelRestartDelay = 2 * time.Second
+	// tunnelMinUptime separates a dropped tunnel from one that fails to open
+	tunnelMinUptime = 10 * time.Second
+	// tunnelReadyTimeout bounds the wait for the local port to accept connections
+	tunnelReadyTimeout = 30 * time.Second
+
+	tunnelViaSSM = "ssm"
+	tunnelViaSSH = "ssh"
+)
+
+// TunnelConfig configures 'eks-login tunnel'
+type TunnelConfig struct {
+	// Bastion is the instance ID, or Name tag, the SSM session runs through
+	Bastion string `yaml:"bastion,omitempty"`
+	// Bastions maps cluster name globs to bastions, for clusters in different
+	// VPCs; the most specific matching glob applies
+	Bastions map[string]string `yaml:"bastions,omitempty"`
+	// LocalPort is the port the API endpoint is forwarded to (default 8443)
+	LocalPort int `yaml:"local_port,omitempty"`
+	// Via selects how the tunnel is opened: ssm (default) or ssh
+	Via string `yaml:"via,omitempty"`
+	// SSH configures tunnels through an SSH bastion, for environments without SSM
+	SSH SSHTunnelConfig `yaml:"ssh,omitempty"`
+}
+
+// SSHTunnelConfig configures the SSH bastion of 'eks-login tunnel --via ssh'
+type SSHTunnelConfig struct {
+	Host string `yaml:"host,omitempty"`
+	User string `yaml:"user,omitempty"`
+	// Key is the private key file; ssh's own defaults and agent are used without it
+	Key  string `yaml:"key,omitempty"`
+	Port int    `yaml:"port,omitempty"`
+}
+
+// TunnelOptions are the flags of 'eks-login tunnel'
+type TunnelOptions struct {
+	Bastion   string
+	LocalPort int
+	Via       string
+	SSHUser   string
+	SSHKey    string
+}
+
+// tunnelBastion returns the bastion for the selected cluster: --bastion, the
+// most specific matching bastions glob, or the default bastion
+func (app *EKSLoginApp) tunnelBastion(flag string) string {
+	if flag != "" {
+		return flag
+	}
+
+	tunnel := app.settings.Tunnel
+	if bastion, ok := mostSpecificMatch(tunnel.Bastions, app.config.Cluster); ok {
+		return bastion
+	}
+	return tunnel.Bastion
+}
+
+// tunnelPort returns the local port of the tunnel
+func (app *EKSLoginApp) tunnelPort(flag int) int {
+	if flag > 0 {
+		return flag
+	}
+	if app.settings.Tunnel.LocalPort > 0 {
+		return app.settings.Tunnel.LocalPort
+	}
+	return defaultTunnelPort
+}
+
+// tunnelVia returns how the tunnel is opened: --via, tunnel.via, or ssm
+func (app *EKSLoginApp) tunnelVia(flag string) (string, error) {
+	via := flag
+	if via == "" {
+		via = app.settings.Tunnel.Via
+	}
+	switch strings.ToLower(via) {
+	case "", tunnelViaSSM:
+		return tunnelViaSSM, nil
+	case tunnelViaSSH:
+		return tunnelViaSSH, nil
+	default:
+		return "", usageError("unknown tunnel type %q (use ssm or ssh)", via)
+	}
+}
+
+// sshTunnelCommand returns the ssh command forwarding localhost:port to host:443
+// through the SSH bastion
+func (app *EKSLoginApp) sshTunnelCommand(opts TunnelOptions, bastion, host string, port int) Command {
+	ssh := app.settings.Tunnel.SSH
+	user := ssh.User
+	if opts.SSHUser != "" {
+		user = opts.SSHUser
+	}
+	key := ssh.Key
+	if opts.SSHKey != "" {
+		key = opts.SSHKey
+	}
+
+	args := []string{"-N",
+		"-L", fmt.Sprintf("localhost:%d:%s:443", port, host),
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+		"-o", "ServerAliveCountMax=3",
+	}
+	if user != "" {
+		args = append(args, "-l", user)
+	}
+	if key != "" {
+		args = append(args, "-i", key)
+	}
+	if ssh.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(ssh.Port))
+	}
+	if app.config.CI {
+		args = append(args, "-o", "BatchMode=yes")
+	}
+	return Command{
+		Name:   "ssh",
+		Args:   append(args, bastion),
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// ResolveBastion returns the instance ID of a bastion given as an instance ID
+// or as the Name tag of a running instance, preferring the cluster's VPC
+func (app *EKSLoginApp) ResolveBastion(bastion, vpcID string) (string, error) {
+	if strings.HasPrefix(bastion, "i-") {
+		return bastion, nil
+	}
+
+	filters := []string{"Name=tag:Name,Values=" + bastion, "Name=instance-state-name,Values=running"}
+	if vpcID != "" {
+		filters = append(filters, "Name=vpc-id,Values="+vpcID)
+	}
+	args := append([]string{"ec2", "describe-instances", "--region", app.config.Region, "--filters"}, filters...)
+	args = append(args, "--query", "Reservations[].Instances[].InstanceId", "--output", "json")
+
+	output, err := app.AWS(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up bastion %s: %w", bastion, err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal([]byte(output), &ids); err != nil {
+		return "", fmt.Errorf("failed to parse bastion instances: %w", err)
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("no running instance named %s found in %s", bastion, vpcID)
+	}
+	return ids[0], nil
+}
+
+// writeTunnelContext adds a context for the selected cluster that reaches its
+// API endpoint through localhost:port and makes it current. It returns the
+// context's name and the previously current context.
+func (app *EKSLoginApp) writeTunnelContext(details *ClusterDetails, host string, port int) (string, string, error) {
+	tokenExec, err := app.tokenExec()
+	if err != nil {
+		return "", "", err
+	}
+	entries, err := app.kubeconfigEntries(details, tokenExec)
+	if err != nil {
+		return "", "", err
+	}
+	arn := entries.CurrentContext
+
+	lock, err := app.lockKubeconfig()
+	if err != nil {
+		return "", "", withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	defer lock.Unlock()
+
+	app.BackupKubeconfig()
+
+	path := KubeconfigPath()
+	kubeconfig, err := LoadKubeconfig(path)
+	if err != nil {
+		return "", "", withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	previous := kubeconfig.CurrentContext
+
+	// The API server certificate names the real endpoint, not localhost
+	name := app.config.Cluster + tunnelSuffix
+	cluster := *entries.Cluster(arn)
+	cluster.Server = "https://" + net.JoinHostPort("localhost", strconv.Itoa(port))
+	cluster.TLSServerName = host
+	cluster.ProxyURL = ""
+	if existing := kubeconfig.Cluster(name); existing != nil {
+		*existing = cluster
+	} else {
+		kubeconfig.Clusters = append(kubeconfig.Clusters, NamedKubeCluster{Name: name, Cluster: cluster})
+	}
+	context := *entries.Context(arn)
+	kubeconfig.SetUser(name, *entries.User(context.User))
+	context.Cluster, context.User = name, name
+	kubeconfig.SetContext(name, context)
+	kubeconfig.CurrentContext = name
+
+	if err := kubeconfig.Save(path); err != nil {
+		return "", "", withExitCode(ExitKubeconfigFailed, "kubeconfig_failed", err)
+	}
+	return name, previous, nil
+}
+
+// waitForPort reports once localhost:port accepts connections, or gives up after timeout
+func waitForPort(port int, timeout time.Duration, stop <-chan struct{}) bool {
+	address := net.JoinHostPort("localhost", strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			conn.Close()
+			return true
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return false
+}
+
+// runTunnel keeps command running, reopening it whenever it exits, until interrupted
+func (app *EKSLoginApp) runTunnel(command Command, port int, contextName string) error {
+	ctx := app.context()
+	for {
+		opened := make(chan struct{})
+		go func() {
+			if waitForPort(port, tunnelReadyTimeout, opened) {
+				green.Printf("✓ Tunnel open on localhost:%d; use context %s (Ctrl-C to close)\n", port, contextName)
+			}
+		}()
+
+		started := time.Now()
+		err := app.run(ctx, command)
+		close(opened)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// A session that fails right away will not recover by retrying
+		if err != nil && time.Since(started) < tunnelMinUptime {
+			return fmt.Errorf("tunnel failed: %w", err)
+		}
+		yellow.Printf("⚠️  Tunnel closed (%v), reopening...\n", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tunnelRestartDelay):
+		}
+	}
+}
+
+// Tunnel forwards the private API endpoint of the selected cluster to
+// localhost through an SSM session on a bastion (or an SSH bastion), and
+// points a kubeconfig context at it until interrupted
+func (app *EKSLoginApp) Tunnel(opts TunnelOptions) error {
+	via, err := app.tunnelVia(opts.Via)
+	if err != nil {
+		return err
+	}
+
+	var bastion string
+	if via == tunnelViaSSH {
+		bastion = opts.Bastion
+		if bastion == "" {
+			bastion = app.settings.Tunnel.SSH.Host
+		}
+		if bastion == "" {
+			return usageError("no SSH bastion: pass --bastion or set tunnel.ssh.host in %s", app.config.ConfigFile)
+		}
+		if _, err := exec.LookPath("ssh"); err != nil {
+			return withExitCode(ExitDependencyMissing, "dependency_missing", fmt.Errorf("required dependency 'ssh' not found in PATH"))
+		}
+	} else {
+		bastion = app.tunnelBastion(opts.Bastion)
+		if bastion == "" {
+			return usageError("no bastion for cluster %s: pass --bastion or set tunnel.bastion in %s", app.config.Cluster, app.config.ConfigFile)
+		}
+		if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+			return withExitCode(ExitDependencyMissing, "dependency_missing",
+				fmt.Errorf("required dependency 'session-manager-plugin' not found in PATH (see https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html)"))
+		}
+	}
+
+	details, err := app.DescribeCluster()
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(details.Endpoint)
+	if err != nil || endpoint.Hostname() == "" {
+		return fmt.Errorf("cluster %s has no usable API endpoint %q", app.config.Cluster, details.Endpoint)
+	}
+	host := endpoint.Hostname()
+	port := app.tunnelPort(opts.LocalPort)
+
+	var command Command
+	if via == tunnelViaSSH {
+		command = app.sshTunnelCommand(opts, bastion, host, port)
+	} else {
+		instance, err := app.ResolveBastion(bastion, details.ResourcesVpcConfig.VpcID)
+		if err != nil {
+			return err
+		}
+		bastion = instance
+		command = Command{
+			Name: "aws",
+			Args: app.awsArgs("ssm", "start-session",
+				"--region", app.config.Region,
+				"--target", instance,
+				"--document-name", "AWS-StartPortForwardingSessionToRemoteHost",
+				"--parameters", fmt.Sprintf("host=%s,portNumber=443,localPortNumber=%d", host, port)),
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		}
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		return usageError("local port %d is not available: %v (use --local-port)", port, err)
+	}
+	listener.Close()
+
+	name, previous, err := app.writeTunnelContext(details, host, port)
+	if err != nil {
+		return err
+	}
+	if previous != "" && previous != name {
+		app.AddCleanup(func() {
+			if err := app.useContext(previous); err != nil {
+				yellow.Printf("⚠️  Unable to restore current context %s: %v\n", previous, err)
+				return
+			}
+			cyan.Printf("📍 Current context restored to %s\n", previous)
+		})
+	}
+
+	blue.Printf("🚇 Opening %s tunnel to %s through %s...\n", strings.ToUpper(via), app.config.Cluster, bastion)
+	return app.runTunnel(command, port, name)
+}
+
+func newTunnelCmd(app *EKSLoginApp) *cobra.Command {
+	var opts TunnelOptions
+
+	cmd := &cobra.Command{
+		Use:   "tunnel",
+		Short: "Reach a private cluster through an SSM or SSH port-forwarding tunnel",
+		Long: `Start an SSM port-forwarding session through a bastion instance to the
+cluster's private API endpoint, and add a '<cluster>-tunnel' kubeconfig context
+that reaches it through localhost. The tunnel is reopened when it drops and
+kept open until interrupted; the previous context is then made current again.
+Requires the AWS Session Manager plugin.
+
+With --via ssh the endpoint is forwarded through an SSH bastion host instead,
+for environments without SSM.`,
+		Example: `  eks-login tunnel -p prod -c payments --bastion i-0123456789abcdef0
+  eks-login tunnel -c payments --bastion prod-bastion --local-port 9443
+  eks-login tunnel -c payments --via ssh --bastion bastion.example.com --ssh-user ec2-user --ssh-key ~/.ssh/bastion.pem`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.CheckDependencies(); err != nil {
+				return err
+			}
+			if err := app.SelectTarget(); err != nil {
+				return err
+			}
+			return app.Tunnel(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Bastion, "bastion", "", "Instance ID or Name tag of the bastion to tunnel through, or the host with --via ssh (default from the config)")
+	cmd.Flags().StringVar(&opts.Via, "via", "", "How to open the tunnel: ssm or ssh (default ssm, or tunnel.via in the config)")
+	cmd.Flags().StringVar(&opts.SSHUser, "ssh-user", "", "User on the SSH bastion (default from tunnel.ssh.user in the config)")
+	cmd.Flags().StringVar(&opts.SSHKey, "ssh-key", "", "Private key for the SSH bastion (default from tunnel.ssh.key in the config)")
+	cmd.Flags().IntVar(&opts.LocalPort, "local-port", 0, "Local port to forward the API endpoint to (default 8443)")
+	return cmd
+}
@@ -0,0 +1,30 @@
+package ekslogin
+
+import "testing"
+
+func TestTunnelBastionPicksMostSpecificGlob(t *testing.T) {
+	app := newTestApp(t, &fakeExecutor{}, &fakePrompter{})
+	app.settings.Tunnel = TunnelConfig{
+		Bastion: "shared-bastion",
+		Bastions: map[string]string{
+			"*":            "catch-all",
+			"payments-*":   "i-payments",
+			"payments-eu*": "i-payments-eu",
+		},
+	}
+
+	tests := map[string]string{
+		"payments-eu-1": "i-payments-eu",
+		"payments-us-1": "i-payments",
+		"search":        "catch-all",
+	}
+	for cluster, want := range tests {
+		app.config.Cluster = cluster
+		if got := app.tunnelBastion(""); got != want {
+			t.Errorf("tunnelBastion for %s = %q, want %q", cluster, got, want)
+		}
+	}
+	if got := app.tunnelBastion("i-flag"); got != "i-flag" {
+		t.Errorf("tunnelBastion with --bastion = %q, want the flag", got)
+	}
+}
@@ -0,0 +1,268 @@
+package ekslogin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// undoJournalName is the file in the backup directory listing the changes
+// 'eks-login undo' can revert
+const undoJournalName = "undo.json"
+
+// UndoRecord is a run of eks-login that changed a kubeconfig
+type UndoRecord struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	// Path is the kubeconfig the run changed
+	Path string `json:"path"`
+	// Backup is the snapshot taken before the change; empty when the
+	// kubeconfig did not exist yet
+	Backup string `json:"backup,omitempty"`
+	// Checksum is the SHA-256 of the kubeconfig as the run left it, to notice
+	// changes made since by other tools
+	Checksum string `json:"checksum"`
+}
+
+// fileChecksum returns the SHA-256 of the file at path, or "" if it does not exist
+func fileChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// undoJournalPath returns the location of the undo journal
+func (app *EKSLoginApp) undoJournalPath() (string, error) {
+	dir, err := app.backupDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, undoJournalName), nil
+}
+
+// UndoRecords returns the recorded kubeconfig changes, oldest first
+func (app *EKSLoginApp) UndoRecords() ([]UndoRecord, error) {
+	path, err := app.undoJournalPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read undo journal: %w", err)
+	}
+	var records []UndoRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse undo journal %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// updateUndoJournal applies update to the undo journal under its lock, keeping
+// as many records as backups are kept
+func (app *EKSLoginApp) updateUndoJournal(update func([]UndoRecord) []UndoRecord) error {
+	path, err := app.undoJournalPath()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	lock, err := LockFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	records, err := app.UndoRecords()
+	if err != nil {
+		return err
+	}
+	records = update(records)
+	records = records[max(0, len(records)-app.backupKeep()):]
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode undo journal: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(fmt.Errorf("failed to write undo journal: %w", err), os.Remove(tmp))
+	}
+	return nil
+}
+
+// recordUndo records that this run changed the kubeconfig at path, which was
+// snapshotted to backup before. Runs that left it unchanged are not recorded.
+func (app *EKSLoginApp) recordUndo(path, backup string) {
+	checksum, err := fileChecksum(path)
+	if err != nil || checksum == "" {
+		return
+	}
+	if backup != "" {
+		if before, err := fileChecksum(backup); err == nil && before == checksum {
+			return
+		}
+	}
+
+	record := UndoRecord{
+		Time:     time.Now().UTC().Truncate(time.Second),
+		Command:  strings.Join(append([]string{"eks-login"}, os.Args[1:]...), " "),
+		Path:     path,
+		Backup:   backup,
+		Checksum: checksum,
+	}
+	err = app.updateUndoJournal(func(records []UndoRecord) []UndoRecord {
+		return append(records, record)
+	})
+	if err != nil {
+		yellow.Printf("⚠️  Unable to record the change for 'eks-login undo': %v\n", err)
+	}
+}
+
+// Undo reverts the most recent kubeconfig change recorded by eks-login,
+// current context included, by restoring the snapshot taken before it. If the
+// kubeconfig was changed since, it asks first (--force skips the question).
+// Each undo goes one change further back.
+func (app *EKSLoginApp) Undo() error {
+	records, err := app.UndoRecords()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("nothing to undo: no kubeconfig changes by eks-login are recorded")
+	}
+	record := records[len(records)-1]
+
+	var data []byte
+	if record.Backup != "" {
+		if data, err = os.ReadFile(record.Backup); err != nil {
+			return fmt.Errorf("the backup of %s from before '%s' is no longer available: %w", record.Path, record.Command, err)
+		}
+		if _, err := LoadKubeconfigData(data); err != nil {
+			return fmt.Errorf("backup %s is not a valid kubeconfig: %w", record.Backup, err)
+		}
+	}
+
+	ctx, cancel := app.withTimeout(app.timeout())
+	defer cancel()
+	lock, err := LockFile(ctx, record.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if checksum, err := fileChecksum(record.Path); err != nil {
+		return fmt.Errorf("failed to read kubeconfig: %w", err)
+	} else if checksum != record.Checksum && !app.config.Force {
+		if !app.config.Interactive {
+			return withExitCode(ExitNotConfirmed, "not_confirmed", fmt.Errorf(
+				"%s was changed after '%s'; pass --force to undo anyway", record.Path, record.Command))
+		}
+		yellow.Printf("⚠️  %s was changed after '%s'; undoing also reverts those changes\n", record.Path, record.Command)
+		yellow.Print("Undo anyway? [y/N]: ")
+		input, err := app.prompter.ReadLine()
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			return errAborted
+		}
+	}
+
+	// The current file is kept too, like a restore; undo itself is not recorded
+	current, err := app.snapshotFile(record.Path)
+	if err != nil {
+		return err
+	}
+	if record.Backup == "" {
+		if err := os.Remove(record.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to undo: %w", err)
+		}
+	} else if err := writeKubeconfigFile(record.Path, data); err != nil {
+		return fmt.Errorf("failed to undo: %w", err)
+	}
+
+	err = app.updateUndoJournal(func(records []UndoRecord) []UndoRecord {
+		for i := len(records) - 1; i >= 0; i-- {
+			if records[i] == record {
+				return append(records[:i], records[i+1:]...)
+			}
+		}
+		return records
+	})
+	if err != nil {
+		return err
+	}
+
+	green.Printf("✓ Undid '%s' from %s\n", record.Command, record.Time.Local().Format("2006-01-02 15:04:05"))
+	if record.Backup == "" {
+		cyan.Printf("   Removed %s, which did not exist before\n", record.Path)
+	} else if kubeconfig, err := LoadKubeconfigData(data); err == nil {
+		cyan.Printf("   Restored %s (current context: %s)\n", record.Path, kubeconfig.CurrentContext)
+	}
+	if current != "" {
+		cyan.Printf("   Previous kubeconfig saved as %s\n", filepath.Base(current))
+	}
+	return nil
+}
+
+// newUndoCmd creates the undo subcommand
+func newUndoCmd(app *EKSLoginApp) *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the most recent kubeconfig change made by eks-login",
+		Long: `Revert the most recent kubeconfig change made by eks-login, current context
+included, by restoring the backup taken before it. Run it again to go further
+back; as many changes as backups are kept (backups.keep) can be undone.
+
+If the kubeconfig was changed since by another tool, undo asks first, because
+those changes are reverted too; --force skips the question.`,
+		Example: `  eks-login undo
+  eks-login undo --list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !list {
+				return app.Undo()
+			}
+			records, err := app.UndoRecords()
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				yellow.Println("No kubeconfig changes to undo")
+				return nil
+			}
+			for i := len(records) - 1; i >= 0; i-- {
+				record := records[i]
+				fmt.Printf("  %d. %s  %s  (%s)\n", len(records)-i,
+					record.Time.Local().Format("2006-01-02 15:04:05"), record.Command, record.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List the changes that can be undone, most recent first")
+	cmd.Flags().BoolVar(&app.config.Force, "force", false, "Undo even if the kubeconfig was changed since by another tool")
+	return cmd
+}
@@ -0,0 +1,79 @@
+package ekslogin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// userAlias returns the kubeconfig user entry name for the cluster with the
+// given ARN: --user-alias (or default.user_alias) with the placeholders
+// {arn}, {cluster}, {profile}, {region}, {account} and {role} expanded, or
+// the ARN itself as aws eks update-kubeconfig names it
+func (app *EKSLoginApp) userAlias(clusterARN string) string {
+	format := app.config.UserAlias
+	if format == "" {
+		format = app.settings.Default.UserAlias
+	}
+	if format == "" {
+		return clusterARN
+	}
+
+	account := ""
+	if arn, err := ParseARN(clusterARN); err == nil {
+		account = arn.AccountID
+	}
+	role := ""
+	if arn, err := ParseARN(app.config.RoleARN); err == nil {
+		role = arn.Resource[strings.LastIndex(arn.Resource, "/")+1:]
+	}
+
+	return strings.NewReplacer(
+		"{arn}", clusterARN,
+		"{cluster}", app.config.Cluster,
+		"{profile}", app.config.Profile,
+		"{region}", app.config.Region,
+		"{account}", account,
+		"{role}", role,
+	).Replace(format)
+}
+
+// RenameUser names the user entry of the current context after the user
+// alias, so contexts of the same cluster with different roles or profiles
+// keep separate credentials. The caller saves the kubeconfig.
+func (app *EKSLoginApp) RenameUser(kubeconfig *Kubeconfig) error {
+	path := KubeconfigPath()
+	context := kubeconfig.Context(kubeconfig.CurrentContext)
+	if context == nil {
+		return fmt.Errorf("current context %q not found in %s", kubeconfig.CurrentContext, path)
+	}
+	alias := app.userAlias(context.Cluster)
+	if alias == "" || alias == context.User {
+		return nil
+	}
+	user := kubeconfig.User(context.User)
+	if user == nil {
+		return fmt.Errorf("user %q not found in %s", context.User, path)
+	}
+
+	entry := *user
+	kubeconfig.SetUser(alias, entry)
+
+	// Drop the entry update-kubeconfig wrote unless another context still uses it
+	shared := false
+	for _, other := range kubeconfig.Contexts {
+		if other.Name != kubeconfig.CurrentContext && other.Context.User == context.User {
+			shared = true
+		}
+	}
+	if !shared {
+		users := kubeconfig.Users[:0]
+		for _, named := range kubeconfig.Users {
+			if named.Name != context.User {
+				users = append(users, named)
+			}
+		}
+		kubeconfig.Users = users
+	}
+	context.User = alias
+	return nil
+}
@@ -0,0 +1,209 @@
+package ekslogin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ContextCheck is the result of validating one kubeconfig context
+type ContextCheck struct {
+	Context string `json:"context"`
+	Profile string `json:"profile,omitempty"`
+	Region  string `json:"region"`
+	Cluster string `json:"cluster"`
+	// Status is ok, sso_expired, token_failed, cluster_deleted, describe_failed,
+	// unauthorized or unreachable
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// OK reports whether the context works
+func (c ContextCheck) OK() bool {
+	return c.Status == "ok"
+}
+
+// eksContextTarget is contextTarget for any EKS context: contexts eks-login did
+// not write are resolved from their cluster ARN and their user's AWS_PROFILE
+func (app *EKSLoginApp) eksContextTarget(kubeconfig *Kubeconfig, name string) (*EKSLoginApp, bool) {
+	if target, err := app.contextTarget(kubeconfig, name); err == nil {
+		return target, true
+	}
+
+	context := kubeconfig.Context(name)
+	if context == nil {
+		return nil, false
+	}
+	arn, err := ParseARN(context.Cluster)
+	if err != nil || arn.Service != "eks" || !strings.HasPrefix(arn.Resource, "cluster/") {
+		return nil, false
+	}
+
+	profile := ""
+	if user := kubeconfig.User(context.User); user != nil && user.Exec != nil {
+		for _, env := range user.Exec.Env {
+			if env.Name == "AWS_PROFILE" {
+				profile = env.Value
+			}
+		}
+	}
+	return app.forTarget(profile, arn.Region, strings.TrimPrefix(arn.Resource, "cluster/")), true
+}
+
+// check validates the selected cluster's context without changing anything:
+// the SSO session, minting a token, the cluster's existence and the API server
+func (app *EKSLoginApp) check(check *ContextCheck) {
+	if path, err := app.SSOTokenPath(); err == nil {
+		expiry, err := readSSOExpiry(path)
+		if err != nil {
+			check.Status, check.Detail = "sso_expired", "no cached SSO session"
+			return
+		}
+		if remaining := time.Until(expiry); remaining <= 0 {
+			check.Status, check.Detail = "sso_expired", fmt.Sprintf("expired %s ago", formatRemaining(-remaining))
+			return
+		}
+	}
+
+	credential, err := app.GetClusterToken()
+	if err != nil {
+		check.Status, check.Detail = "token_failed", firstLine(err)
+		return
+	}
+
+	details, err := app.DescribeCluster()
+	if err != nil {
+		check.Status, check.Detail = "describe_failed", firstLine(err)
+		if strings.Contains(err.Error(), "ResourceNotFoundException") {
+			check.Status, check.Detail = "cluster_deleted", "cluster no longer exists"
+		}
+		return
+	}
+	if err := app.probeEndpoint(details); err != nil {
+		check.Status, check.Detail = "unreachable", "API endpoint not reachable from this network (VPN or tunnel needed)"
+		return
+	}
+
+	client, err := app.clusterClient(details, credential.Status.Token)
+	if err != nil {
+		check.Status, check.Detail = "unreachable", firstLine(err)
+		return
+	}
+	version, latency, err := client.ServerVersion()
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized:
+		check.Status, check.Detail = "unauthorized", "the API server rejected the token"
+	case err != nil:
+		check.Status, check.Detail = "unreachable", firstLine(err)
+	default:
+		check.Status = "ok"
+		check.Detail = fmt.Sprintf("%s, %dms", version.GitVersion, latency.Milliseconds())
+	}
+}
+
+// firstLine returns the first line of an error, dropping captured stderr
+func firstLine(err error) string {
+	line, _, _ := strings.Cut(err.Error(), "\n")
+	return line
+}
+
+// ValidateContexts checks every EKS context in kubeconfig, limited to --profile when given
+func (app *EKSLoginApp) ValidateContexts() ([]ContextCheck, error) {
+	kubeconfig, err := LoadKubeconfig(KubeconfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []ContextCheck
+	var targets []*EKSLoginApp
+	for _, context := range kubeconfig.Contexts {
+		target, ok := app.eksContextTarget(kubeconfig, context.Name)
+		if !ok || (app.config.Profile != "" && target.config.Profile != app.config.Profile) {
+			continue
+		}
+		checks = append(checks, ContextCheck{
+			Context: context.Name,
+			Profile: target.config.Profile,
+			Region:  target.config.Region,
+			Cluster: target.config.Cluster,
+		})
+		targets = append(targets, target)
+	}
+
+	app.forEach(len(checks), func(i int) {
+		targets[i].check(&checks[i])
+	})
+
+	return checks, nil
+}
+
+// printChecks prints the validation results as a table
+func printChecks(checks []ContextCheck) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tPROFILE\tREGION\tSTATUS\tDETAIL")
+	for _, check := range checks {
+		status := green.Sprint(check.Status)
+		if !check.OK() {
+			status = red.Sprint(check.Status)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", check.Context, check.Profile, check.Region, status, check.Detail)
+	}
+	w.Flush()
+}
+
+func newValidateCmd(app *EKSLoginApp) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check which EKS contexts in kubeconfig currently work",
+		Long: `Check every EKS context in kubeconfig without modifying anything: whether
+its SSO session is valid, a token can be minted, the cluster still exists and
+its API server accepts the token. Exits non-zero when a context is broken.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return usageError("invalid output format %q: use json", output)
+			}
+			if output == "json" {
+				statusToStderr()
+			}
+
+			checks, err := app.ValidateContexts()
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				if err := printJSON(checks); err != nil {
+					return err
+				}
+			} else if len(checks) == 0 {
+				yellow.Println("No EKS contexts found in kubeconfig")
+			} else {
+				printChecks(checks)
+			}
+
+			var broken int
+			for _, check := range checks {
+				if !check.OK() {
+					broken++
+				}
+			}
+			if broken > 0 {
+				return fmt.Errorf("%d of %d context(s) are broken", broken, len(checks))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format: json")
+	return cmd
+}
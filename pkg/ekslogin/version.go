@@ -0,0 +1,174 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, injected with -ldflags "-X eks-login/pkg/ekslogin.Version=..." (and Commit, Date)
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// releaseRepo is the GitHub repository releases are published to
+const releaseRepo = "krutsko/eks-login-helper"
+
+// updateCheckInterval is how often the opt-in update check contacts GitHub
+const updateCheckInterval = 24 * time.Hour
+
+// UpdatesConfig controls the opt-in check for newer releases
+type UpdatesConfig struct {
+	// Check looks for a newer release at most once a day after login
+	Check bool `yaml:"check,omitempty"`
+}
+
+// buildInfo fills in commit and date from the Go build info when they were not injected
+func buildInfo() (version, commit, date string) {
+	version, commit, date = Version, Commit, Date
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version, commit, date
+	}
+
+	if version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && commit == "":
+			commit = setting.Value
+		case setting.Key == "vcs.time" && date == "":
+			date = setting.Value
+		}
+	}
+	return version, commit, date
+}
+
+// Release is a published GitHub release
+type Release struct {
+	TagName string         `json:"tag_name"`
+	URL     string         `json:"html_url"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// LatestRelease fetches the latest published release from GitHub
+func (app *EKSLoginApp) LatestRelease() (*Release, error) {
+	client, err := app.HTTPClient(10 * time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.withTimeout(10 * time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", releaseRepo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: GitHub returned %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &release, nil
+}
+
+// CheckForUpdate prints a notice when a newer release than the running version exists
+func (app *EKSLoginApp) CheckForUpdate() error {
+	release, err := app.LatestRelease()
+	if err != nil {
+		return err
+	}
+
+	version, _, _ := buildInfo()
+	if versionNumberPattern.MatchString(version) && compareVersions(version, release.TagName) >= 0 {
+		green.Printf("✓ eks-login %s is up to date\n", version)
+		return nil
+	}
+
+	yellow.Printf("⬆️  eks-login %s is available (running %s): %s\n", release.TagName, version, release.URL)
+	return nil
+}
+
+// updateCheckStamp is the file recording the last automatic update check
+func updateCheckStamp() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "eks-login", "update-check"), nil
+}
+
+// MaybeCheckForUpdate runs the opt-in update check at most once per interval.
+// Failures are silent: the check must never get in the way of a login.
+func (app *EKSLoginApp) MaybeCheckForUpdate() {
+	if !app.settings.Updates.Check || app.config.CI {
+		return
+	}
+
+	stamp, err := updateCheckStamp()
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < updateCheckInterval {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(stamp), 0o700); err != nil {
+		return
+	}
+	os.WriteFile(stamp, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0o600)
+
+	app.CheckForUpdate()
+}
+
+// newVersionCmd creates the version subcommand
+func newVersionCmd(app *EKSLoginApp) *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version number",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, commit, date := buildInfo()
+			fmt.Printf("EKS Login Helper %s\n", version)
+			if commit != "" {
+				fmt.Printf("  commit: %s\n", commit)
+			}
+			if date != "" {
+				fmt.Printf("  built:  %s\n", date)
+			}
+			fmt.Printf("  go:     %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+			if check {
+				return app.CheckForUpdate()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Check GitHub for a newer release")
+	return cmd
+}
@@ -0,0 +1,102 @@
+package ekslogin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"time"
+)
+
+// LoginEvent is posted to the protected-cluster webhook after each login
+type LoginEvent struct {
+	Event    string    `json:"event"`
+	User     string    `json:"user"`
+	Host     string    `json:"host,omitempty"`
+	Identity string    `json:"identity,omitempty"`
+	Profile  string    `json:"profile"`
+	Account  string    `json:"account,omitempty"`
+	Region   string    `json:"region"`
+	Cluster  string    `json:"cluster"`
+	Time     time.Time `json:"time"`
+}
+
+// loginEvent describes the login to the selected cluster
+func (app *EKSLoginApp) loginEvent() LoginEvent {
+	event := LoginEvent{
+		Event:   "login",
+		Profile: app.config.Profile,
+		Region:  app.config.Region,
+		Cluster: app.config.Cluster,
+		Time:    time.Now().UTC(),
+	}
+	if current, err := user.Current(); err == nil {
+		event.User = current.Username
+	}
+	event.Host, _ = os.Hostname()
+	if identity, err := app.GetCallerIdentity(); err == nil {
+		event.Identity = identity.Arn
+		event.Account = identity.Account
+	}
+	return event
+}
+
+// webhookPayload encodes event for the webhook: a message for Slack incoming webhooks,
+// the event itself for anything else
+func webhookPayload(webhook string, event LoginEvent) ([]byte, error) {
+	if u, err := url.Parse(webhook); err == nil && u.Host == "hooks.slack.com" {
+		who := event.User
+		if event.Identity != "" {
+			who = fmt.Sprintf("%s (%s)", event.User, event.Identity)
+		}
+		return json.Marshal(map[string]string{
+			"text": fmt.Sprintf(":rotating_light: %s logged in to protected cluster *%s* (profile %s, region %s) from %s",
+				who, event.Cluster, event.Profile, event.Region, event.Host),
+		})
+	}
+	return json.Marshal(event)
+}
+
+// PostLoginEvent reports a login to a protected cluster to the configured webhook.
+// Failures are only warned about: the webhook gives visibility, it does not gate access.
+func (app *EKSLoginApp) PostLoginEvent() {
+	webhook := app.settings.Protected.Webhook
+	if webhook == "" || !app.IsProtected() {
+		return
+	}
+
+	payload, err := webhookPayload(webhook, app.loginEvent())
+	if err == nil {
+		err = app.postWebhook(webhook, payload)
+	}
+	if err != nil {
+		yellow.Printf("⚠️  Unable to report protected cluster login: %v\n", err)
+	}
+}
+
+// postWebhook posts a JSON payload to webhook
+func (app *EKSLoginApp) postWebhook(webhook string, payload []byte) error {
+	client, err := app.HTTPClient(10 * time.Second)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(app.context(), http.MethodPost, webhook, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
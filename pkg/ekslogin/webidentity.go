@@ -0,0 +1,230 @@
+package ekslogin
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webIdentityAudience is the audience STS expects of CI OIDC tokens
+const webIdentityAudience = "sts.amazonaws.com"
+
+// gitLabTokenVar is the id_tokens variable eks-login reads in GitLab CI
+const gitLabTokenVar = "GITLAB_OIDC_TOKEN"
+
+// webIdentityCredentials is how the login authenticates without a profile:
+// an OIDC token kept in a file, exchanged for the role's credentials
+type webIdentityCredentials struct {
+	RoleARN     string
+	TokenFile   string
+	SessionName string
+}
+
+// Env returns the variables that make the AWS CLI and 'eks-login token'
+// assume the role with the same token file
+func (w *webIdentityCredentials) Env() []ExecEnvVar {
+	return []ExecEnvVar{
+		{Name: "AWS_ROLE_ARN", Value: w.RoleARN},
+		{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: w.TokenFile},
+		{Name: "AWS_ROLE_SESSION_NAME", Value: w.SessionName},
+	}
+}
+
+// webIdentitySessionName names the role session after the CI job, so
+// CloudTrail shows which pipeline run used it
+func webIdentitySessionName() string {
+	for _, name := range []string{"GITHUB_RUN_ID", "CI_JOB_ID", "BUILDKITE_BUILD_ID", "CODEBUILD_BUILD_NUMBER"} {
+		if id := os.Getenv(name); id != "" {
+			return "eks-login-" + id
+		}
+	}
+	return "eks-login-" + strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+// webIdentityTokenFile returns the file holding the OIDC token to exchange and
+// where the token came from: --web-identity-token-file, AWS_WEB_IDENTITY_TOKEN_FILE,
+// the GitHub Actions token endpoint or GitLab CI's GITLAB_OIDC_TOKEN. Tokens
+// that are not in a file yet are written to one, so kubectl can use them too.
+func (app *EKSLoginApp) webIdentityTokenFile() (path, source string, err error) {
+	if path := app.config.WebIdentityTokenFile; path != "" {
+		return path, path, nil
+	}
+	if path := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"); path != "" {
+		return path, "AWS_WEB_IDENTITY_TOKEN_FILE", nil
+	}
+
+	var token string
+	switch {
+	case os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != "":
+		if token, err = app.gitHubActionsToken(); err != nil {
+			return "", "", err
+		}
+		source = "GitHub Actions"
+	case os.Getenv(gitLabTokenVar) != "":
+		token, source = os.Getenv(gitLabTokenVar), "GitLab CI ("+gitLabTokenVar+")"
+	default:
+		return "", "", usageError("no web identity token: pass --web-identity-token-file, set AWS_WEB_IDENTITY_TOKEN_FILE, "+
+			"grant the GitHub Actions job 'id-token: write' or define the GitLab CI id_token %s", gitLabTokenVar)
+	}
+
+	// Each run gets a new file, created exclusively, so concurrent jobs on a
+	// shared runner never read each other's token. The file must outlive the
+	// run for kubectl; GitHub removes RUNNER_TEMP after the job, elsewhere it
+	// is left to the system's temporary directory cleanup.
+	dir := os.Getenv("RUNNER_TEMP")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	file, err := os.CreateTemp(dir, "eks-login-web-identity-token-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write web identity token: %w", err)
+	}
+	if _, err := file.WriteString(token); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", "", fmt.Errorf("failed to write web identity token: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", "", fmt.Errorf("failed to write web identity token: %w", err)
+	}
+	return file.Name(), source, nil
+}
+
+// gitHubActionsToken requests an OIDC token for STS from GitHub Actions; the
+// job needs the id-token: write permission
+func (app *EKSLoginApp) gitHubActionsToken() (string, error) {
+	endpoint, err := url.Parse(os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"))
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("audience", webIdentityAudience)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(app.context(), http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"))
+	body, err := app.doAWSRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an OIDC token from GitHub Actions: %w", err)
+	}
+
+	var response struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.Value == "" {
+		return "", fmt.Errorf("failed to parse the GitHub Actions OIDC token response")
+	}
+	return response.Value, nil
+}
+
+// stsAssumeRoleWithWebIdentity exchanges an OIDC token for the credentials of
+// a role. The call is not signed: the token is the proof of identity.
+func (app *EKSLoginApp) stsAssumeRoleWithWebIdentity(region string, identity *webIdentityCredentials) (*AWSCredentials, error) {
+	token, err := os.ReadFile(identity.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+	sessionName := identity.SessionName
+	if sessionName == "" {
+		sessionName = webIdentitySessionName()
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {identity.RoleARN},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	if seconds := app.roleDurationSeconds(); seconds != "" {
+		form.Set("DurationSeconds", seconds)
+	}
+	endpoint, err := app.stsEndpoint(region)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(app.context(), http.MethodPost, endpoint.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	response, err := app.doAWSRequest(req)
+	if err != nil {
+		return nil, app.roleDurationError(fmt.Errorf("failed to assume role %s with web identity: %w", identity.RoleARN, err))
+	}
+
+	var result struct {
+		Credentials AWSCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse web identity credentials: %w", err)
+	}
+	return &result.Credentials, nil
+}
+
+// environmentWebIdentity returns the web identity set by AWS_ROLE_ARN and
+// AWS_WEB_IDENTITY_TOKEN_FILE, as the AWS CLI reads it, or nil
+func environmentWebIdentity() *webIdentityCredentials {
+	if os.Getenv("AWS_ROLE_ARN") == "" || os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") == "" {
+		return nil
+	}
+	return &webIdentityCredentials{
+		RoleARN:     os.Getenv("AWS_ROLE_ARN"),
+		TokenFile:   os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+		SessionName: os.Getenv("AWS_ROLE_SESSION_NAME"),
+	}
+}
+
+// AuthenticateWebIdentity assumes --web-identity-role with the CI job's OIDC
+// token instead of an SSO profile. Later AWS calls use the role's credentials,
+// and the kubeconfig user assumes the role with the same token file.
+func (app *EKSLoginApp) AuthenticateWebIdentity() error {
+	if app.config.Everywhere {
+		return usageError("--everywhere searches profiles and cannot be combined with --web-identity-role")
+	}
+	if app.config.Profile != "" {
+		return usageError("--web-identity-role replaces --profile; pass only one of them")
+	}
+	path, source, err := app.webIdentityTokenFile()
+	if err != nil {
+		return err
+	}
+	// kubectl may run from another directory
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	identity := &webIdentityCredentials{
+		RoleARN:     app.config.WebIdentityRole,
+		TokenFile:   path,
+		SessionName: webIdentitySessionName(),
+	}
+
+	if app.config.DryRun {
+		yellow.Printf("🧪 The login would assume %s with the web identity token from %s\n", identity.RoleARN, source)
+		app.webIdentity = identity
+		return nil
+	}
+	spinner := app.StartSpinner("Assuming role with web identity")
+	creds, err := app.stsAssumeRoleWithWebIdentity(app.config.Region, identity)
+	spinner.Stop()
+	if err != nil {
+		return withExitCode(ExitSSOLoginFailed, "web_identity_failed", err)
+	}
+
+	app.credentials = creds
+	app.webIdentity = identity
+	green.Printf("✓ Assumed %s with the web identity token from %s\n", identity.RoleARN, source)
+	return nil
+}